@@ -1,11 +1,14 @@
 package completer
 
 import (
+	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/c-bata/go-prompt"
 	"github.com/ymtdzzz/otelgen/executor"
+	"github.com/ymtdzzz/otelgen/receiver"
 	"github.com/ymtdzzz/otelgen/telemetry"
 )
 
@@ -14,8 +17,26 @@ var commandSuggestions = map[string][]prompt.Suggest{
 		{Text: "create", Description: "Create a new signal"},
 		{Text: "set", Description: "Update an existing signal"},
 		{Text: "add", Description: "Add something to a signal"},
+		{Text: "delete", Description: "Delete a signal"},
+		{Text: "move", Description: "Move a span to another parent or trace"},
+		{Text: "clone", Description: "Clone a span with its descendants"},
 		{Text: "send", Description: "Send all traces to the collector"},
 		{Text: "list", Description: "List available traces and spans"},
+		{Text: "option", Description: "Show or change session options"},
+		{Text: "undo", Description: "Undo the last change"},
+		{Text: "redo", Description: "Redo the last undone change"},
+		{Text: "expect", Description: "Check the spans produced by the last send"},
+		{Text: "received", Description: "Show the spans received by the local receiver"},
+		{Text: "load", Description: "Load a scenario file, replacing all signals"},
+		{Text: "save", Description: "Save all signals to a scenario file"},
+		{Text: "generate", Description: "Generate random traces from a topology file"},
+		{Text: "let", Description: "Define a variable referred to as ${name}"},
+		{Text: "vars", Description: "List the variables"},
+		{Text: "trace", Description: "Build a trace from nested span blocks"},
+		{Text: "define", Description: "Define a template of spans"},
+		{Text: "instantiate", Description: "Create the spans of a template"},
+		{Text: "workspace", Description: "Create, switch or list workspaces"},
+		{Text: "lint", Description: "Check the signals against the semantic conventions"},
 		{Text: "exit", Description: "Exit the application"},
 	},
 	"create_type": {
@@ -37,15 +58,121 @@ var commandSuggestions = map[string][]prompt.Suggest{
 		{Text: "resource", Description: "Update a resource"},
 		{Text: "span", Description: "Update a span"},
 		{Text: "event", Description: "Update an event"},
+		{Text: "trace", Description: "Update a trace"},
+		{Text: "link", Description: "Update links between two spans"},
+		{Text: "spans", Description: "Update all the spans selected by a pattern, e.g. spans db_*"},
+	},
+	"set_trace": {
+		{Text: "inherit-resource", Description: "Inherit the resource of the parent span in the trace"},
+	},
+	"inherit_resource": {
+		{Text: "on", Description: "Spans inherit the resource of their parent"},
+		{Text: "off", Description: "Spans use only their own resource"},
+		{Text: "default", Description: "Follow the global inherit-resource option"},
+	},
+	"option": {
+		{Text: "inherit-resource", Description: "Inherit the resource of the parent span in all traces"},
+		{Text: "jitter", Description: "Scale span durations by a random factor on each send (0 to 1)"},
+	},
+	"define": {
+		{Text: "template", Description: "Define a template with parameters, e.g. template http_handler(svc, route) {"},
+	},
+	"workspace": {
+		{Text: "new", Description: "Create an empty workspace and switch to it"},
+		{Text: "switch", Description: "Switch to another workspace"},
+		{Text: "list", Description: "List the workspaces"},
+		{Text: "delete", Description: "Delete a workspace other than the current one"},
+		{Text: "copy", Description: "Copy the signals of a workspace to a new workspace"},
+	},
+	"send_errors_for": {
+		{Text: "resource", Description: "Fail only the spans with the resource"},
+		{Text: "span", Description: "Fail only the spans with the name"},
+	},
+	"duration": {
+		{Text: "100ms", Description: "Fixed duration"},
+		{Text: `"uniform(10ms, 50ms)"`, Description: "Uniform distribution between the minimum and the maximum"},
+		{Text: `"normal(100ms, 20ms)"`, Description: "Normal distribution with the mean and the standard deviation"},
+		{Text: `"lognormal(100ms, 50ms)"`, Description: "Log-normal distribution with the mean and the standard deviation"},
+		{Text: `"percentiles(p50=20ms, p90=80ms, p99=300ms)"`, Description: "Distribution with the percentile table"},
+	},
+	"kind": {
+		{Text: "internal", Description: "Internal operation of an application"},
+		{Text: "server", Description: "Server side handling of a synchronous request"},
+		{Text: "client", Description: "Client side of a synchronous request"},
+		{Text: "producer", Description: "Sender of an asynchronous message"},
+		{Text: "consumer", Description: "Receiver of an asynchronous message"},
+	},
+	"on_off": {
+		{Text: "on", Description: "Enable the option"},
+		{Text: "off", Description: "Disable the option"},
+	},
+	"delete_type": {
+		{Text: "trace", Description: "Delete a trace and all its spans"},
+		{Text: "span", Description: "Delete a span and its descendants"},
+		{Text: "resource", Description: "Delete a resource and unbind it from spans"},
+		{Text: "event", Description: "Delete an event and remove it from spans"},
+		{Text: "link", Description: "Delete links between two spans"},
+		{Text: "spans", Description: "Delete all the spans selected by a pattern, e.g. spans cache_*"},
+	},
+	"move_type": {
+		{Text: "span", Description: "Move a span with its descendants"},
+	},
+	"move_under_or_to": {
+		{Text: "under", Description: "Move the span under a parent span"},
+		{Text: "to", Description: "Move the span to a trace as its root span"},
+	},
+	"move_to_trace": {
+		{Text: "trace", Description: "Move the span to a trace as its root span"},
+	},
+	"clone_type": {
+		{Text: "span", Description: "Clone a span with its descendants"},
+	},
+	"spans": {
+		{Text: "spans", Description: "Select spans by a pattern, e.g. spans db_*"},
+	},
+	"span_selector": {
+		{Text: "where", Description: "Select only the spans with the resource or the attribute"},
+		{Text: "in", Description: "Select only the spans in the trace"},
+		{Text: "dry-run", Description: "Show the selected spans without changing them"},
+	},
+	"span_condition": {
+		{Text: "resource=", Description: "The span is bound to the resource"},
+		{Text: "attr", Description: "The span has the attribute with the value, e.g. attr http.method=GET"},
 	},
 	"add_type": {
 		{Text: "link", Description: "Add a link to the span"},
 		{Text: "event", Description: "Add an event to the span"},
 	},
+	"defaults": {
+		{Text: "on", Description: "Merge the SDK default and environment resource"},
+		{Text: "off", Description: "Use only the attributes of the resource"},
+	},
+	"expect_type": {
+		{Text: "span", Description: "Check a sent span"},
+		{Text: "trace", Description: "Check the number of spans in a sent trace"},
+	},
+	"expect_span": {
+		{Text: "has", Description: "Check an attribute, event, parent or resource of the span"},
+	},
+	"expect_trace": {
+		{Text: "spans=", Description: "Check the number of spans in the trace"},
+	},
+	"expect_has": {
+		{Text: "attribute", Description: "The span has the attribute (optionally with the value)"},
+		{Text: "event", Description: "The span has the event"},
+		{Text: "parent", Description: "The span is a child of the span"},
+		{Text: "resource", Description: "The span is bound to the resource (service.name)"},
+	},
+	"received": {
+		{Text: "list", Description: "List received traces"},
+		{Text: "show", Description: "Show the spans of a received trace"},
+		{Text: "clear", Description: "Remove the received spans"},
+	},
 	"list": {
 		{Text: "traces", Description: "List all available traces"},
 		{Text: "resources", Description: "List all available resources"},
 		{Text: "events", Description: "List all available events"},
+		{Text: "links", Description: "List links of spans with their indexes"},
 	},
 }
 
@@ -72,7 +199,8 @@ func (c *completerContext) completeCreate() []prompt.Suggest {
 }
 
 func (c *completerContext) completeCreateSpan() []prompt.Suggest {
-	if c.parsed.Create.Trace == nil && c.parsed.Create.ParentSpan == nil && c.parsed.Create.Name != nil {
+	// the name can be omitted for a span created as a convention, e.g. create span in trace ...
+	if c.parsed.Create.Trace == nil && c.parsed.Create.ParentSpan == nil && (c.parsed.Create.Name != nil || len(c.partialInput) > 2) {
 		if strings.Contains(c.inputText, "in trace ") {
 			return prompt.FilterHasPrefix(convertTracesToSuggestions(), c.currentWord, false)
 		} else if strings.Contains(c.inputText, "in ") {
@@ -100,8 +228,20 @@ func (c *completerContext) completeCreateSpan() []prompt.Suggest {
 		if c.isInputInProgress("resource") {
 			return prompt.FilterHasPrefix(convertResourcesToSuggestions(), c.currentWord, false)
 		}
+		if c.isInputInProgress("duration") {
+			return prompt.FilterHasPrefix(commandSuggestions["duration"], c.currentWord, false)
+		}
+		if c.isInputInProgress("kind") {
+			return prompt.FilterHasPrefix(commandSuggestions["kind"], c.currentWord, false)
+		}
+		if c.isInputInProgress("as") {
+			return prompt.FilterHasPrefix(convertSpanConventionsToSuggestions(), c.currentWord, false)
+		}
 
 		suggestions := []prompt.Suggest{}
+		if convention := c.parsed.Create.Convention(); convention != nil {
+			suggestions = append(suggestions, convertConventionParamsToSuggestions(convention)...)
+		}
 		if !c.isInputInProgress("resource") && !c.isInputInProgress("attributes") {
 			if !c.parsed.Create.HasArgResource() {
 				suggestions = append(suggestions, prompt.Suggest{Text: "resource", Description: "Set a resource for the span"})
@@ -109,6 +249,15 @@ func (c *completerContext) completeCreateSpan() []prompt.Suggest {
 			if !c.parsed.Create.HasArgAttrs() {
 				suggestions = append(suggestions, prompt.Suggest{Text: "attributes", Description: "Add attributes to the span"})
 			}
+			if !c.parsed.Create.HasArgDuration() {
+				suggestions = append(suggestions, prompt.Suggest{Text: "duration", Description: "Set a distribution of the span duration"})
+			}
+			if !c.parsed.Create.HasArgKind() {
+				suggestions = append(suggestions, prompt.Suggest{Text: "kind", Description: "Set the kind of the span"})
+			}
+			if c.parsed.Create.Convention() == nil {
+				suggestions = append(suggestions, prompt.Suggest{Text: "as", Description: "Fill the kind, name and attributes from a semantic convention"})
+			}
 		}
 
 		return prompt.FilterHasPrefix(suggestions, c.currentWord, false)
@@ -117,15 +266,27 @@ func (c *completerContext) completeCreateSpan() []prompt.Suggest {
 }
 
 func (c *completerContext) completeCreateResource() []prompt.Suggest {
-	if !c.parsed.Create.HasArgAttrs() && c.parsed.Create.Name != nil {
-		if c.isInputInProgress("attributes") {
-			return []prompt.Suggest{}
-		}
-		return prompt.FilterHasPrefix([]prompt.Suggest{
-			{Text: "attributes", Description: "Add attributes to the resource"},
-		}, c.currentWord, false)
+	if c.parsed.Create.Name == nil || c.isInputInProgress("attributes") {
+		return []prompt.Suggest{}
 	}
-	return []prompt.Suggest{}
+	if c.isInputInProgress("semconv") {
+		return prompt.FilterHasPrefix(convertSemconvVersionsToSuggestions(), c.currentWord, false)
+	}
+	if c.isInputInProgress("defaults") {
+		return prompt.FilterHasPrefix(commandSuggestions["defaults"], c.currentWord, false)
+	}
+
+	suggestions := []prompt.Suggest{}
+	if !c.parsed.Create.HasArgAttrs() {
+		suggestions = append(suggestions, prompt.Suggest{Text: "attributes", Description: "Add attributes to the resource"})
+	}
+	if !c.parsed.Create.HasArgSemconv() {
+		suggestions = append(suggestions, prompt.Suggest{Text: "semconv", Description: "Set the semconv version of the resource"})
+	}
+	if !c.parsed.Create.HasArgDefaults() {
+		suggestions = append(suggestions, prompt.Suggest{Text: "defaults", Description: "Merge the SDK default and environment resource"})
+	}
+	return prompt.FilterHasPrefix(suggestions, c.currentWord, false)
 }
 
 func (c *completerContext) completeCreateEvent() []prompt.Suggest {
@@ -151,6 +312,8 @@ func (c *completerContext) completeSet() []prompt.Suggest {
 		return c.completeSetResource()
 	case "event":
 		return c.completeSetEvent()
+	case "trace":
+		return c.completeSetTrace()
 	}
 	return []prompt.Suggest{}
 }
@@ -162,18 +325,34 @@ func (c *completerContext) completeSetSpan() []prompt.Suggest {
 	if c.isInputInProgress("resource") {
 		return prompt.FilterHasPrefix(convertResourcesToSuggestions(), c.currentWord, false)
 	}
+	if c.isInputInProgress("duration") {
+		return prompt.FilterHasPrefix(commandSuggestions["duration"], c.currentWord, false)
+	}
+	if c.isInputInProgress("kind") {
+		return prompt.FilterHasPrefix(commandSuggestions["kind"], c.currentWord, false)
+	}
+	if c.isInputInProgress("remove-attributes") {
+		if span, err := telemetry.LookupSpan(*c.parsed.Set.Name); err == nil {
+			return prompt.FilterHasPrefix(convertAttributeKeysToSuggestions(span.Attributes), c.currentWord, false)
+		}
+		return []prompt.Suggest{}
+	}
 
 	suggesstions := []prompt.Suggest{}
-	if !c.isInputInProgress("name") && !c.isInputInProgress("resource") && !c.isInputInProgress("attributes") {
+	if !c.isInputInProgress("name") && !c.isInputInProgress("resource") && !c.isInputInProgress("attributes") && !c.isInputInProgress("add-attributes") {
 		if !c.parsed.Set.HasArgName() {
 			suggesstions = append(suggesstions, prompt.Suggest{Text: "name", Description: "Set a new name for the span"})
 		}
 		if !c.parsed.Set.HasArgResource() {
 			suggesstions = append(suggesstions, prompt.Suggest{Text: "resource", Description: "Set a resource for the span"})
 		}
-		if !c.parsed.Set.HasArgAttrs() {
-			suggesstions = append(suggesstions, prompt.Suggest{Text: "attributes", Description: "Set attributes for the span"})
+		if !c.parsed.Set.HasArgDuration() {
+			suggesstions = append(suggesstions, prompt.Suggest{Text: "duration", Description: "Set a distribution of the span duration"})
 		}
+		if !c.parsed.Set.HasArgKind() {
+			suggesstions = append(suggesstions, prompt.Suggest{Text: "kind", Description: "Set the kind of the span"})
+		}
+		suggesstions = append(suggesstions, c.setAttrsSuggestions("span")...)
 	}
 	return prompt.FilterHasPrefix(suggesstions, c.currentWord, false)
 }
@@ -182,14 +361,30 @@ func (c *completerContext) completeSetResource() []prompt.Suggest {
 	if c.isInputInProgress("resource") {
 		return prompt.FilterHasPrefix(convertResourcesToSuggestions(), c.currentWord, false)
 	}
+	if c.isInputInProgress("semconv") {
+		return prompt.FilterHasPrefix(convertSemconvVersionsToSuggestions(), c.currentWord, false)
+	}
+	if c.isInputInProgress("defaults") {
+		return prompt.FilterHasPrefix(commandSuggestions["defaults"], c.currentWord, false)
+	}
+	if c.isInputInProgress("remove-attributes") {
+		if resource, ok := telemetry.GetResources()[*c.parsed.Set.Name]; ok {
+			return prompt.FilterHasPrefix(convertAttributeKeysToSuggestions(resource.Attributes), c.currentWord, false)
+		}
+		return []prompt.Suggest{}
+	}
 
 	suggesstions := []prompt.Suggest{}
-	if !c.isInputInProgress("name") && !c.isInputInProgress("attributes") {
+	if !c.isInputInProgress("name") && !c.isInputInProgress("attributes") && !c.isInputInProgress("add-attributes") {
 		if !c.parsed.Set.HasArgName() {
 			suggesstions = append(suggesstions, prompt.Suggest{Text: "name", Description: "Set a new name for the resource"})
 		}
-		if !c.parsed.Set.HasArgAttrs() {
-			suggesstions = append(suggesstions, prompt.Suggest{Text: "attributes", Description: "Set attributes for the resource"})
+		suggesstions = append(suggesstions, c.setAttrsSuggestions("resource")...)
+		if !c.parsed.Set.HasArgSemconv() {
+			suggesstions = append(suggesstions, prompt.Suggest{Text: "semconv", Description: "Set the semconv version of the resource"})
+		}
+		if !c.parsed.Set.HasArgDefaults() {
+			suggesstions = append(suggesstions, prompt.Suggest{Text: "defaults", Description: "Merge the SDK default and environment resource"})
 		}
 	}
 	return prompt.FilterHasPrefix(suggesstions, c.currentWord, false)
@@ -199,21 +394,97 @@ func (c *completerContext) completeSetEvent() []prompt.Suggest {
 	if c.isInputInProgress("event") {
 		return prompt.FilterHasPrefix(convertEventsToSuggestions(), c.currentWord, false)
 	}
+	if c.isInputInProgress("remove-attributes") {
+		if event, ok := telemetry.GetEvents()[*c.parsed.Set.Name]; ok {
+			return prompt.FilterHasPrefix(convertAttributeKeysToSuggestions(event.Attributes), c.currentWord, false)
+		}
+		return []prompt.Suggest{}
+	}
 
 	suggesstions := []prompt.Suggest{}
-	if !c.isInputInProgress("name") && !c.isInputInProgress("attributes") {
+	if !c.isInputInProgress("name") && !c.isInputInProgress("attributes") && !c.isInputInProgress("add-attributes") {
 		if !c.parsed.Set.HasArgName() {
 			suggesstions = append(suggesstions, prompt.Suggest{Text: "name", Description: "Set a new name for the event"})
 		}
-		if !c.parsed.Set.HasArgAttrs() {
-			suggesstions = append(suggesstions, prompt.Suggest{Text: "attributes", Description: "Set attributes for the event"})
-		}
+		suggesstions = append(suggesstions, c.setAttrsSuggestions("event")...)
 	}
 	return prompt.FilterHasPrefix(suggesstions, c.currentWord, false)
 }
 
+// setAttrsSuggestions suggests the attribute operations of the set command which can still be
+// specified. Replacing attributes and patching them are mutually exclusive.
+func (c *completerContext) setAttrsSuggestions(kind string) []prompt.Suggest {
+	return attrsSuggestions(kind, c.parsed.Set.HasArgAttrs(), c.parsed.Set.HasArgAddAttrs(), c.parsed.Set.HasArgRemoveAttrs())
+}
+
+func attrsSuggestions(kind string, hasAttrs, hasAddAttrs, hasRemoveAttrs bool) []prompt.Suggest {
+	suggestions := []prompt.Suggest{}
+	if hasAttrs {
+		return suggestions
+	}
+	if !hasAddAttrs && !hasRemoveAttrs {
+		suggestions = append(suggestions, prompt.Suggest{Text: "attributes", Description: fmt.Sprintf("Set attributes for the %s", kind)})
+	}
+	if !hasAddAttrs {
+		suggestions = append(suggestions, prompt.Suggest{Text: "add-attributes", Description: fmt.Sprintf("Add or overwrite attributes of the %s", kind)})
+	}
+	if !hasRemoveAttrs {
+		suggestions = append(suggestions, prompt.Suggest{Text: "remove-attributes", Description: fmt.Sprintf("Remove attributes from the %s", kind)})
+	}
+	return suggestions
+}
+
+func (c *completerContext) completeSetLink() []prompt.Suggest {
+	if c.isInputInProgress("link") {
+		return prompt.FilterHasPrefix(convertSpansToSuggestions(), c.currentWord, false)
+	}
+	if c.parsed.SetLink.From != nil && c.isInputInProgress(*c.parsed.SetLink.From) {
+		return prompt.FilterHasPrefix(convertLinkTargetsToSuggestions(*c.parsed.SetLink.From), c.currentWord, false)
+	}
+	if c.parsed.SetLink.From != nil && (c.parsed.SetLink.To != nil || c.parsed.SetLink.Index != nil) {
+		if c.isInputInProgress("attributes") || c.isInputInProgress("add-attributes") || c.isInputInProgress("remove-attributes") {
+			return []prompt.Suggest{}
+		}
+		link := c.parsed.SetLink
+		return prompt.FilterHasPrefix(attrsSuggestions("links", link.HasArgAttrs(), link.HasArgAddAttrs(), link.HasArgRemoveAttrs()), c.currentWord, false)
+	}
+	return []prompt.Suggest{}
+}
+
+func (c *completerContext) completeSetTrace() []prompt.Suggest {
+	if c.isInputInProgress("trace") {
+		return prompt.FilterHasPrefix(convertTracesToSuggestions(), c.currentWord, false)
+	}
+	if c.isInputInProgress("inherit-resource") {
+		return prompt.FilterHasPrefix(commandSuggestions["inherit_resource"], c.currentWord, false)
+	}
+	if c.parsed.Set.Name != nil && !c.parsed.Set.HasArgInheritResource() {
+		return prompt.FilterHasPrefix(commandSuggestions["set_trace"], c.currentWord, false)
+	}
+	return []prompt.Suggest{}
+}
+
+func (c *completerContext) completeOption() []prompt.Suggest {
+	if c.isInputInProgress("option") {
+		return prompt.FilterHasPrefix(commandSuggestions["option"], c.currentWord, false)
+	}
+	if c.parsed.Option.Key != nil && *c.parsed.Option.Key == "jitter" {
+		return []prompt.Suggest{}
+	}
+	if c.parsed.Option.Key != nil && (c.parsed.Option.Value == nil || c.isInputInProgress(*c.parsed.Option.Key)) {
+		return prompt.FilterHasPrefix(commandSuggestions["on_off"], c.currentWord, false)
+	}
+	return []prompt.Suggest{}
+}
+
 func (c *completerContext) completeAddLink() []prompt.Suggest {
-	if c.isInputInProgress("link") || (c.parsed.AddLink.From != nil && c.isInputInProgress(*c.parsed.AddLink.From)) {
+	if c.parsed.AddLink.Spans != nil {
+		return c.completeAddLinkSpans()
+	}
+	if c.isInputInProgress("link") {
+		return prompt.FilterHasPrefix(append(commandSuggestions["spans"], convertSpansToSuggestions()...), c.currentWord, false)
+	}
+	if c.parsed.AddLink.From != nil && c.isInputInProgress(*c.parsed.AddLink.From) {
 		return prompt.FilterHasPrefix(convertSpansToSuggestions(), c.currentWord, false)
 	}
 	if c.parsed.AddLink.From != nil && c.parsed.AddLink.To != nil {
@@ -230,8 +501,11 @@ func (c *completerContext) completeAddLink() []prompt.Suggest {
 }
 
 func (c *completerContext) completeAddEvent() []prompt.Suggest {
+	if c.parsed.AddEvent.Spans != nil {
+		return c.completeAddEventSpans()
+	}
 	if c.isInputInProgress("event") {
-		return prompt.FilterHasPrefix(convertSpansToSuggestions(), c.currentWord, false)
+		return prompt.FilterHasPrefix(append(commandSuggestions["spans"], convertSpansToSuggestions()...), c.currentWord, false)
 	}
 	if c.parsed.AddEvent.EventName == nil || c.isInputInProgress(*c.parsed.AddEvent.SpanName) {
 		return prompt.FilterHasPrefix(convertEventsToSuggestions(), c.currentWord, false)
@@ -239,10 +513,392 @@ func (c *completerContext) completeAddEvent() []prompt.Suggest {
 	return []prompt.Suggest{}
 }
 
+// completeSpanSelector suggests the patterns, conditions and traces of spans selected by a
+// bulk command, and reports whether the current word is a part of the selector
+func (c *completerContext) completeSpanSelector(sel *executor.SpanSelector) ([]prompt.Suggest, bool) {
+	switch {
+	case c.isInputInProgress("spans"):
+		return prompt.FilterHasPrefix(convertSpanNamesToSuggestions(), c.currentWord, false), true
+	case c.isInputInProgress("where") || c.isInputInProgress("and"):
+		return prompt.FilterHasPrefix(commandSuggestions["span_condition"], c.currentWord, false), true
+	case sel.Trace == nil && c.isInputInProgress("in"):
+		return prompt.FilterHasPrefix([]prompt.Suggest{
+			{Text: "trace", Description: "Select only the spans in the trace"},
+		}, c.currentWord, false), true
+	case c.isInputInProgress("trace"):
+		return prompt.FilterHasPrefix(convertTracesToSuggestions(), c.currentWord, false), true
+	case c.isInputInProgress("attr"):
+		return []prompt.Suggest{}, true
+	}
+	return nil, false
+}
+
+// spanSelectorSuggestions returns the keywords which can follow the selector
+func spanSelectorSuggestions(sel *executor.SpanSelector) []prompt.Suggest {
+	var suggestions []prompt.Suggest
+	if sel.Trace == nil {
+		for _, s := range commandSuggestions["span_selector"] {
+			if s.Text == "where" && len(sel.Conditions) > 0 {
+				s = prompt.Suggest{Text: "and", Description: "Add another condition"}
+			}
+			suggestions = append(suggestions, s)
+		}
+		return suggestions
+	}
+	return append(suggestions, prompt.Suggest{Text: "dry-run", Description: "Show the selected spans without changing them"})
+}
+
+func (c *completerContext) completeSetSpans() []prompt.Suggest {
+	sel := c.parsed.SetSpans.Spans
+	if suggestions, ok := c.completeSpanSelector(sel); ok {
+		return suggestions
+	}
+	if c.isInputInProgress("resource") {
+		return prompt.FilterHasPrefix(convertResourcesToSuggestions(), c.currentWord, false)
+	}
+	if c.isInputInProgress("duration") {
+		return prompt.FilterHasPrefix(commandSuggestions["duration"], c.currentWord, false)
+	}
+	if c.isInputInProgress("kind") {
+		return prompt.FilterHasPrefix(commandSuggestions["kind"], c.currentWord, false)
+	}
+	if sel.Pattern == nil || c.isInputInProgress("name") || c.isInputInProgress("attributes") ||
+		c.isInputInProgress("add-attributes") || c.isInputInProgress("remove-attributes") {
+		return []prompt.Suggest{}
+	}
+
+	set := &executor.SetCommand{Args: c.parsed.SetSpans.Args}
+	var suggestions []prompt.Suggest
+	if len(set.Args) == 0 {
+		suggestions = spanSelectorSuggestions(sel)
+	}
+	if !set.HasArgName() {
+		suggestions = append(suggestions, prompt.Suggest{Text: "name", Description: "Set a new name for the spans"})
+	}
+	if !set.HasArgResource() {
+		suggestions = append(suggestions, prompt.Suggest{Text: "resource", Description: "Set a resource for the spans"})
+	}
+	if !set.HasArgDuration() {
+		suggestions = append(suggestions, prompt.Suggest{Text: "duration", Description: "Set a distribution of the span durations"})
+	}
+	if !set.HasArgKind() {
+		suggestions = append(suggestions, prompt.Suggest{Text: "kind", Description: "Set the kind of the spans"})
+	}
+	suggestions = append(suggestions, attrsSuggestions("spans", set.HasArgAttrs(), set.HasArgAddAttrs(), set.HasArgRemoveAttrs())...)
+	if len(set.Args) > 0 {
+		suggestions = append(suggestions, prompt.Suggest{Text: "dry-run", Description: "Show the selected spans without changing them"})
+	}
+	return prompt.FilterHasPrefix(suggestions, c.currentWord, false)
+}
+
+func (c *completerContext) completeDeleteSpans() []prompt.Suggest {
+	sel := c.parsed.DeleteSpans.Spans
+	if suggestions, ok := c.completeSpanSelector(sel); ok {
+		return suggestions
+	}
+	if sel.Pattern == nil || c.parsed.DeleteSpans.DryRun {
+		return []prompt.Suggest{}
+	}
+	return prompt.FilterHasPrefix(spanSelectorSuggestions(sel), c.currentWord, false)
+}
+
+func (c *completerContext) completeAddEventSpans() []prompt.Suggest {
+	cmd := c.parsed.AddEvent
+	if suggestions, ok := c.completeSpanSelector(cmd.Spans); ok {
+		return suggestions
+	}
+	if cmd.Spans.Pattern == nil || cmd.DryRun {
+		return []prompt.Suggest{}
+	}
+	if cmd.EventName != nil && c.currentWord != *cmd.EventName {
+		return prompt.FilterHasPrefix([]prompt.Suggest{
+			{Text: "dry-run", Description: "Show the selected spans without changing them"},
+		}, c.currentWord, false)
+	}
+	suggestions := convertEventsToSuggestions()
+	if cmd.EventName == nil {
+		suggestions = append(spanSelectorSuggestions(cmd.Spans), suggestions...)
+	}
+	return prompt.FilterHasPrefix(suggestions, c.currentWord, false)
+}
+
+func (c *completerContext) completeAddLinkSpans() []prompt.Suggest {
+	cmd := c.parsed.AddLink
+	if suggestions, ok := c.completeSpanSelector(cmd.Spans); ok {
+		return suggestions
+	}
+	if cmd.Spans.Pattern == nil || cmd.DryRun || c.isInputInProgress("attributes") {
+		return []prompt.Suggest{}
+	}
+	if cmd.To == nil || c.currentWord == *cmd.To {
+		suggestions := convertSpansToSuggestions()
+		if cmd.To == nil {
+			suggestions = append(spanSelectorSuggestions(cmd.Spans), suggestions...)
+		}
+		return prompt.FilterHasPrefix(suggestions, c.currentWord, false)
+	}
+	var suggestions []prompt.Suggest
+	if len(cmd.Args) == 0 {
+		suggestions = append(suggestions, prompt.Suggest{Text: "attributes", Description: "Add attributes to the links"})
+	}
+	suggestions = append(suggestions, prompt.Suggest{Text: "dry-run", Description: "Show the selected spans without changing them"})
+	return prompt.FilterHasPrefix(suggestions, c.currentWord, false)
+}
+
+func (c *completerContext) completeDelete() []prompt.Suggest {
+	if c.parsed.Delete.Type == nil {
+		return prompt.FilterHasPrefix(commandSuggestions["delete_type"], c.currentWord, false)
+	}
+	if c.isInputInProgress(*c.parsed.Delete.Type) {
+		switch *c.parsed.Delete.Type {
+		case "trace":
+			return prompt.FilterHasPrefix(convertTracesToSuggestions(), c.currentWord, false)
+		case "span", "link":
+			return prompt.FilterHasPrefix(convertSpansToSuggestions(), c.currentWord, false)
+		case "resource":
+			return prompt.FilterHasPrefix(convertResourcesToSuggestions(), c.currentWord, false)
+		case "event":
+			return prompt.FilterHasPrefix(convertEventsToSuggestions(), c.currentWord, false)
+		}
+	}
+	if *c.parsed.Delete.Type == "link" && c.parsed.Delete.Name != nil && c.isInputInProgress(*c.parsed.Delete.Name) {
+		return prompt.FilterHasPrefix(convertLinkTargetsToSuggestions(*c.parsed.Delete.Name), c.currentWord, false)
+	}
+	return []prompt.Suggest{}
+}
+
+func (c *completerContext) completeMove() []prompt.Suggest {
+	if c.parsed.Move.Type == nil {
+		return prompt.FilterHasPrefix(commandSuggestions["move_type"], c.currentWord, false)
+	}
+	if c.isInputInProgress("span") || c.isInputInProgress("under") {
+		return prompt.FilterHasPrefix(convertSpansToSuggestions(), c.currentWord, false)
+	}
+	if c.isInputInProgress("trace") {
+		return prompt.FilterHasPrefix(convertTracesToSuggestions(), c.currentWord, false)
+	}
+	if c.isInputInProgress("to") {
+		return prompt.FilterHasPrefix(commandSuggestions["move_to_trace"], c.currentWord, false)
+	}
+	if c.parsed.Move.Name != nil && c.parsed.Move.Parent == nil && c.parsed.Move.Trace == nil {
+		return prompt.FilterHasPrefix(commandSuggestions["move_under_or_to"], c.currentWord, false)
+	}
+	return []prompt.Suggest{}
+}
+
+func (c *completerContext) completeClone() []prompt.Suggest {
+	if c.parsed.Clone.Type == nil {
+		return prompt.FilterHasPrefix(commandSuggestions["clone_type"], c.currentWord, false)
+	}
+	if c.isInputInProgress("span") || c.isInputInProgress("under") {
+		return prompt.FilterHasPrefix(convertSpansToSuggestions(), c.currentWord, false)
+	}
+	if c.parsed.Clone.Name == nil || c.isInputInProgress("as") || c.isInputInProgress("count") {
+		return []prompt.Suggest{}
+	}
+
+	suggestions := []prompt.Suggest{}
+	if c.parsed.Clone.Prefix == nil {
+		suggestions = append(suggestions, prompt.Suggest{Text: "as", Description: "Set the name prefix of the clones"})
+	} else {
+		if c.parsed.Clone.Count == nil {
+			suggestions = append(suggestions, prompt.Suggest{Text: "count", Description: "Set the number of clones"})
+		}
+		if c.parsed.Clone.Parent == nil {
+			suggestions = append(suggestions, prompt.Suggest{Text: "under", Description: "Set the parent span of the clones"})
+		}
+	}
+	return prompt.FilterHasPrefix(suggestions, c.currentWord, false)
+}
+
+func (c *completerContext) completeGenerate() []prompt.Suggest {
+	cmd := c.parsed.Generate
+	if cmd.Topology == nil {
+		if c.isInputInProgress("from") {
+			return []prompt.Suggest{}
+		}
+		return prompt.FilterHasPrefix([]prompt.Suggest{
+			{Text: "from", Description: "Set the topology file"},
+		}, c.currentWord, false)
+	}
+	if c.isInputInProgress("from") || c.isInputInProgress("count") || c.isInputInProgress("seed") || c.isInputInProgress("as") {
+		return []prompt.Suggest{}
+	}
+
+	// the options must be in this order, so only the ones after the last specified option are suggested
+	options := []struct {
+		set     bool
+		suggest prompt.Suggest
+	}{
+		{cmd.Count != nil, prompt.Suggest{Text: "count", Description: "Set the number of traces"}},
+		{cmd.Seed != nil, prompt.Suggest{Text: "seed", Description: "Set the seed to generate the same traces again"}},
+		{cmd.Prefix != nil, prompt.Suggest{Text: "as", Description: "Set the name prefix of the traces"}},
+		{cmd.Stream, prompt.Suggest{Text: "stream", Description: "Send the traces one by one without keeping them"}},
+	}
+	suggestions := []prompt.Suggest{}
+	for _, option := range options {
+		if option.set {
+			suggestions = suggestions[:0]
+			continue
+		}
+		suggestions = append(suggestions, option.suggest)
+	}
+	return prompt.FilterHasPrefix(suggestions, c.currentWord, false)
+}
+
+func (c *completerContext) completeSend() []prompt.Suggest {
+	cmd := c.parsed.Send
+	if c.isInputInProgress("seed") || c.isInputInProgress("errors") || c.isInputInProgress("attribute") {
+		return []prompt.Suggest{}
+	}
+	if c.isInputInProgress("for") {
+		return prompt.FilterHasPrefix(commandSuggestions["send_errors_for"], c.currentWord, false)
+	}
+	if len(cmd.Errors) == 0 {
+		suggestions := []prompt.Suggest{}
+		if !cmd.Lint && cmd.Seed == nil {
+			suggestions = append(suggestions, prompt.Suggest{Text: "--lint", Description: "Lint the signals first and send nothing if there are errors"})
+		}
+		if cmd.Seed == nil {
+			suggestions = append(suggestions, prompt.Suggest{Text: "seed", Description: "Set the seed to send the same random values again"})
+		}
+		suggestions = append(suggestions, prompt.Suggest{Text: "errors", Description: "Fail a percentage of the spans, e.g. errors 5%"})
+		return prompt.FilterHasPrefix(suggestions, c.currentWord, false)
+	}
+
+	if c.isInputInProgress("resource") {
+		return prompt.FilterHasPrefix(convertResourcesToSuggestions(), c.currentWord, false)
+	}
+	if c.isInputInProgress("span") {
+		return prompt.FilterHasPrefix(convertSpanNamesToSuggestions(), c.currentWord, false)
+	}
+
+	last := cmd.Errors[len(cmd.Errors)-1]
+
+	// the options must be in this order, so only the ones after the last specified option are suggested
+	options := []struct {
+		set     bool
+		suggest prompt.Suggest
+	}{
+		{last.Resource != nil || last.SpanName != nil, prompt.Suggest{Text: "for", Description: "Fail only the spans with a resource or a name"}},
+		{last.Attribute != nil, prompt.Suggest{Text: "attribute", Description: "Set the error attribute instead of error.type=injected"}},
+		{last.Propagate, prompt.Suggest{Text: "propagate", Description: "Fail the ancestors of the failed spans too"}},
+	}
+	suggestions := []prompt.Suggest{}
+	for _, option := range options {
+		if option.set {
+			suggestions = suggestions[:0]
+			continue
+		}
+		suggestions = append(suggestions, option.suggest)
+	}
+	suggestions = append(suggestions, prompt.Suggest{Text: "errors", Description: "Fail a percentage of other spans"})
+	return prompt.FilterHasPrefix(suggestions, c.currentWord, false)
+}
+
 func (c *completerContext) completeList() []prompt.Suggest {
 	if c.parsed.List.Type == nil {
 		return prompt.FilterHasPrefix(commandSuggestions["list"], c.currentWord, false)
 	}
+	if c.isInputInProgress("links") {
+		return prompt.FilterHasPrefix(convertSpansToSuggestions(), c.currentWord, false)
+	}
+	return []prompt.Suggest{}
+}
+
+func (c *completerContext) completeExpect() []prompt.Suggest {
+	if c.parsed.Expect.Type == nil {
+		return prompt.FilterHasPrefix(commandSuggestions["expect_type"], c.currentWord, false)
+	}
+	if c.isInputInProgress("span") || c.isInputInProgress("parent") {
+		return prompt.FilterHasPrefix(convertSentSpansToSuggestions(), c.currentWord, false)
+	}
+	if c.isInputInProgress("trace") {
+		return prompt.FilterHasPrefix(convertSentTracesToSuggestions(), c.currentWord, false)
+	}
+	if c.isInputInProgress("has") {
+		return prompt.FilterHasPrefix(commandSuggestions["expect_has"], c.currentWord, false)
+	}
+	if c.parsed.Expect.Name == nil || c.parsed.Expect.Has != nil || c.parsed.Expect.Spans != nil {
+		return []prompt.Suggest{}
+	}
+	return prompt.FilterHasPrefix(commandSuggestions["expect_"+*c.parsed.Expect.Type], c.currentWord, false)
+}
+
+func (c *completerContext) completeReceived() []prompt.Suggest {
+	if c.parsed.Received.Action == nil {
+		return prompt.FilterHasPrefix(commandSuggestions["received"], c.currentWord, false)
+	}
+	if c.isInputInProgress("show") {
+		return prompt.FilterHasPrefix(convertReceivedTracesToSuggestions(), c.currentWord, false)
+	}
+	return []prompt.Suggest{}
+}
+
+func (c *completerContext) completeInstantiate() []prompt.Suggest {
+	cmd := c.parsed.Instantiate
+	if c.isInputInProgress("instantiate") {
+		return prompt.FilterHasPrefix(convertTemplatesToSuggestions(), c.currentWord, false)
+	}
+	if c.isInputInProgress("under") {
+		return prompt.FilterHasPrefix(convertSpansToSuggestions(), c.currentWord, false)
+	}
+	if cmd.Name == nil {
+		return []prompt.Suggest{}
+	}
+	t, ok := telemetry.GetTemplates()[*cmd.Name]
+	if !ok || strings.Contains(c.currentWord, "=") {
+		return []prompt.Suggest{}
+	}
+
+	suggestions := []prompt.Suggest{}
+	if cmd.Parent == nil && len(cmd.Args) == 0 {
+		suggestions = append(suggestions, prompt.Suggest{Text: "under", Description: "Create the spans under a span"})
+	}
+	given := make(map[string]bool)
+	for _, arg := range cmd.Args {
+		given[arg.Key] = true
+	}
+	for _, p := range t.Params {
+		if !given[p] {
+			suggestions = append(suggestions, prompt.Suggest{Text: p + "=", Description: "Parameter of the template"})
+		}
+	}
+	return prompt.FilterHasPrefix(suggestions, c.currentWord, false)
+}
+
+func (c *completerContext) completeWorkspace() []prompt.Suggest {
+	cmd := c.parsed.Workspace
+	if cmd.Action == nil || c.isInputInProgress("workspace") {
+		return prompt.FilterHasPrefix(commandSuggestions["workspace"], c.currentWord, false)
+	}
+	switch *cmd.Action {
+	case "switch", "delete", "copy":
+		if c.isInputInProgress(*cmd.Action) {
+			return prompt.FilterHasPrefix(convertWorkspacesToSuggestions(), c.currentWord, false)
+		}
+	}
+	return []prompt.Suggest{}
+}
+
+// completeVariable suggests the variables when the current word has an unclosed ${
+func (c *completerContext) completeVariable() ([]prompt.Suggest, bool) {
+	i := strings.LastIndex(c.currentWord, "${")
+	if i < 0 || strings.Contains(c.currentWord[i:], "}") {
+		return nil, false
+	}
+	prefix := c.currentWord[:i]
+	suggestions := convertVariablesToSuggestions()
+	for j := range suggestions {
+		suggestions[j].Text = prefix + "${" + suggestions[j].Text + "}"
+	}
+	return prompt.FilterHasPrefix(suggestions, c.currentWord, false), true
+}
+
+func (c *completerContext) completeLet() []prompt.Suggest {
+	if c.isInputInProgress("let") {
+		return prompt.FilterHasPrefix(convertVariablesToSuggestions(), c.currentWord, false)
+	}
 	return []prompt.Suggest{}
 }
 
@@ -276,6 +932,10 @@ func Completer(d prompt.Document) []prompt.Suggest {
 		return prompt.FilterHasPrefix(commandSuggestions[""], text, false)
 	}
 
+	if suggestions, ok := cctx.completeVariable(); ok {
+		return suggestions
+	}
+
 	if cctx.parsed == nil {
 		return []prompt.Suggest{}
 	}
@@ -283,18 +943,49 @@ func Completer(d prompt.Document) []prompt.Suggest {
 	if cctx.isInputInProgress("add") {
 		return prompt.FilterHasPrefix(commandSuggestions["add_type"], cctx.currentWord, false)
 	}
+	if cctx.isInputInProgress("define") {
+		return prompt.FilterHasPrefix(commandSuggestions["define"], cctx.currentWord, false)
+	}
 
 	switch {
 	case cctx.parsed.Create != nil:
 		return cctx.completeCreate()
+	case cctx.parsed.SetLink != nil:
+		return cctx.completeSetLink()
+	case cctx.parsed.SetSpans != nil:
+		return cctx.completeSetSpans()
 	case cctx.parsed.Set != nil:
 		return cctx.completeSet()
 	case cctx.parsed.AddLink != nil:
 		return cctx.completeAddLink()
 	case cctx.parsed.AddEvent != nil:
 		return cctx.completeAddEvent()
+	case cctx.parsed.DeleteSpans != nil:
+		return cctx.completeDeleteSpans()
+	case cctx.parsed.Delete != nil:
+		return cctx.completeDelete()
+	case cctx.parsed.Move != nil:
+		return cctx.completeMove()
+	case cctx.parsed.Clone != nil:
+		return cctx.completeClone()
 	case cctx.parsed.List != nil:
 		return cctx.completeList()
+	case cctx.parsed.Option != nil:
+		return cctx.completeOption()
+	case cctx.parsed.Expect != nil:
+		return cctx.completeExpect()
+	case cctx.parsed.Received != nil:
+		return cctx.completeReceived()
+	case cctx.parsed.Generate != nil:
+		return cctx.completeGenerate()
+	case cctx.parsed.Send != nil:
+		return cctx.completeSend()
+	case cctx.parsed.Let != nil:
+		return cctx.completeLet()
+	case cctx.parsed.Instantiate != nil:
+		return cctx.completeInstantiate()
+	case cctx.parsed.Workspace != nil:
+		return cctx.completeWorkspace()
 	}
 
 	return []prompt.Suggest{}
@@ -311,10 +1002,138 @@ func convertTracesToSuggestions() []prompt.Suggest {
 	return suggestions
 }
 
+// convertSpansToSuggestions suggests the display name of each span, or its handle
+// when the name is shared with other spans or needs quoting
+func convertSentTracesToSuggestions() []prompt.Suggest {
+	result := telemetry.LastSendResult()
+	if result == nil {
+		return []prompt.Suggest{}
+	}
+	var suggestions []prompt.Suggest
+	for _, t := range result.Traces {
+		suggestions = append(suggestions, prompt.Suggest{Text: t.Name, Description: fmt.Sprintf("%d spans", t.Spans)})
+	}
+	return suggestions
+}
+
+func convertSentSpansToSuggestions() []prompt.Suggest {
+	result := telemetry.LastSendResult()
+	if result == nil {
+		return []prompt.Suggest{}
+	}
+	var suggestions []prompt.Suggest
+	for handle := range result.SpanContexts {
+		suggestions = append(suggestions, prompt.Suggest{Text: handle})
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		return suggestions[i].Text < suggestions[j].Text
+	})
+	return suggestions
+}
+
+func convertReceivedTracesToSuggestions() []prompt.Suggest {
+	r := receiver.Default()
+	if r == nil {
+		return []prompt.Suggest{}
+	}
+	var suggestions []prompt.Suggest
+	for _, t := range r.Traces() {
+		root := t.Roots()[0]
+		suggestions = append(suggestions, prompt.Suggest{
+			Text:        t.TraceID.String(),
+			Description: fmt.Sprintf("%s (%s) %d spans", root.Name, root.Resource.ServiceName(), len(t.Spans)),
+		})
+	}
+	return suggestions
+}
+
+// convertSpanNamesToSuggestions suggests the names of the spans without duplicates
+func convertTemplatesToSuggestions() []prompt.Suggest {
+	templates := telemetry.GetTemplates()
+	suggestions := make([]prompt.Suggest, 0, len(templates))
+	for name, t := range templates {
+		suggestions = append(suggestions, prompt.Suggest{Text: name, Description: "(" + strings.Join(t.Params, ", ") + ")"})
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		return suggestions[i].Text < suggestions[j].Text
+	})
+	return suggestions
+}
+
+func convertWorkspacesToSuggestions() []prompt.Suggest {
+	var suggestions []prompt.Suggest
+	for _, ws := range telemetry.Workspaces() {
+		description := fmt.Sprintf("%d traces, %d spans", ws.Traces, ws.Spans)
+		if ws.Current {
+			description += " (current)"
+		}
+		suggestions = append(suggestions, prompt.Suggest{Text: ws.Name, Description: description})
+	}
+	return suggestions
+}
+
+func convertVariablesToSuggestions() []prompt.Suggest {
+	vars := executor.Variables()
+	suggestions := make([]prompt.Suggest, 0, len(vars))
+	for name, value := range vars {
+		suggestions = append(suggestions, prompt.Suggest{Text: name, Description: value})
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		return suggestions[i].Text < suggestions[j].Text
+	})
+	return suggestions
+}
+
+func convertSpanNamesToSuggestions() []prompt.Suggest {
+	names := make(map[string]bool)
+	for _, span := range telemetry.GetSpans() {
+		names[span.Name] = true
+	}
+	suggestions := make([]prompt.Suggest, 0, len(names))
+	for name := range names {
+		suggestions = append(suggestions, prompt.Suggest{Text: name})
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		return suggestions[i].Text < suggestions[j].Text
+	})
+	return suggestions
+}
+
 func convertSpansToSuggestions() []prompt.Suggest {
 	var suggestions []prompt.Suggest
-	for spanName := range telemetry.GetSpans() {
-		suggestions = append(suggestions, prompt.Suggest{Text: spanName})
+	for _, span := range telemetry.GetSpans() {
+		ref := telemetry.SpanRef(span)
+		if ref == span.Name {
+			suggestions = append(suggestions, prompt.Suggest{Text: ref})
+		} else {
+			suggestions = append(suggestions, prompt.Suggest{Text: ref, Description: span.Name})
+		}
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		return suggestions[i].Text < suggestions[j].Text
+	})
+	return suggestions
+}
+
+// convertLinkTargetsToSuggestions suggests the indexes of the links of the span
+// followed by the spans which can be specified as the link target
+func convertLinkTargetsToSuggestions(spanRef string) []prompt.Suggest {
+	suggestions := []prompt.Suggest{}
+	if span, err := telemetry.LookupSpan(spanRef); err == nil {
+		for i, link := range span.Links {
+			suggestions = append(suggestions, prompt.Suggest{
+				Text:        strconv.Itoa(i),
+				Description: "Link to " + telemetry.SpanRef(link.TargetSpan),
+			})
+		}
+	}
+	return append(suggestions, convertSpansToSuggestions()...)
+}
+
+func convertAttributeKeysToSuggestions(attributes map[string]string) []prompt.Suggest {
+	var suggestions []prompt.Suggest
+	for key, value := range attributes {
+		suggestions = append(suggestions, prompt.Suggest{Text: key, Description: value})
 	}
 	sort.Slice(suggestions, func(i, j int) bool {
 		return suggestions[i].Text < suggestions[j].Text
@@ -343,3 +1162,43 @@ func convertEventsToSuggestions() []prompt.Suggest {
 	})
 	return suggestions
 }
+
+func convertSpanConventionsToSuggestions() []prompt.Suggest {
+	var suggestions []prompt.Suggest
+	for _, c := range telemetry.SpanConventions() {
+		suggestions = append(suggestions, prompt.Suggest{Text: c.Name, Description: c.Description})
+	}
+	return suggestions
+}
+
+// convertConventionParamsToSuggestions returns the parameters of the convention which are not specified yet
+func convertConventionParamsToSuggestions(arg *executor.ConventionArg) []prompt.Suggest {
+	convention, err := telemetry.LookupSpanConvention(arg.Name)
+	if err != nil {
+		return nil
+	}
+	specified := map[string]bool{}
+	for _, kv := range arg.Params {
+		specified[kv.Key] = true
+	}
+	var suggestions []prompt.Suggest
+	for _, p := range convention.Params {
+		if specified[p.Name] {
+			continue
+		}
+		description := p.Description
+		if p.Required {
+			description += " (required)"
+		}
+		suggestions = append(suggestions, prompt.Suggest{Text: p.Name + "=", Description: description})
+	}
+	return suggestions
+}
+
+func convertSemconvVersionsToSuggestions() []prompt.Suggest {
+	var suggestions []prompt.Suggest
+	for _, version := range telemetry.SupportedSemconvVersions() {
+		suggestions = append(suggestions, prompt.Suggest{Text: version})
+	}
+	return suggestions
+}
@@ -1,11 +1,19 @@
 package completer
 
 import (
+	"bytes"
+	"context"
 	"testing"
 
 	"github.com/c-bata/go-prompt"
 	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/otelgen/executor"
+	"github.com/ymtdzzz/otelgen/receiver"
 	"github.com/ymtdzzz/otelgen/telemetry"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
 )
 
 func TestCompleteCommand(t *testing.T) {
@@ -21,6 +29,13 @@ func TestCompleteCommand(t *testing.T) {
 			input: "c",
 			want: []prompt.Suggest{
 				{Text: "create", Description: "Create a new signal"},
+				{Text: "clone", Description: "Clone a span with its descendants"},
+			},
+		},
+		{
+			input: "u",
+			want: []prompt.Suggest{
+				{Text: "undo", Description: "Undo the last change"},
 			},
 		},
 		{
@@ -142,12 +157,16 @@ func TestCompleteCreateSpan(t *testing.T) {
 			want: []prompt.Suggest{
 				{Text: "resource", Description: "Set a resource for the span"},
 				{Text: "attributes", Description: "Add attributes to the span"},
+				{Text: "duration", Description: "Set a distribution of the span duration"},
+				{Text: "kind", Description: "Set the kind of the span"},
+				{Text: "as", Description: "Fill the kind, name and attributes from a semantic convention"},
 			},
 		},
 		{
 			input: "create span span1 in trace my-trace a",
 			want: []prompt.Suggest{
 				{Text: "attributes", Description: "Add attributes to the span"},
+				{Text: "as", Description: "Fill the kind, name and attributes from a semantic convention"},
 			},
 		},
 		{
@@ -161,6 +180,14 @@ func TestCompleteCreateSpan(t *testing.T) {
 				{Text: "my-resource"},
 			},
 		},
+		{
+			input: "create span span1 in trace my-trace duration ",
+			want:  commandSuggestions["duration"],
+		},
+		{
+			input: "create span span1 in trace my-trace kind ",
+			want:  commandSuggestions["kind"],
+		},
 		{
 			input: "create span span1 in trace my-trace resource me",
 			want: []prompt.Suggest{
@@ -171,16 +198,53 @@ func TestCompleteCreateSpan(t *testing.T) {
 			input: "create span span1 in trace my-trace resource me-resource ",
 			want: []prompt.Suggest{
 				{Text: "attributes", Description: "Add attributes to the span"},
+				{Text: "duration", Description: "Set a distribution of the span duration"},
+				{Text: "kind", Description: "Set the kind of the span"},
+				{Text: "as", Description: "Fill the kind, name and attributes from a semantic convention"},
 			},
 		},
 		{
 			input: "create span span1 in trace my-trace attributes key=val ",
 			want: []prompt.Suggest{
 				{Text: "resource", Description: "Set a resource for the span"},
+				{Text: "duration", Description: "Set a distribution of the span duration"},
+				{Text: "kind", Description: "Set the kind of the span"},
+				{Text: "as", Description: "Fill the kind, name and attributes from a semantic convention"},
+			},
+		},
+		{
+			input: "create span in ",
+			want:  commandSuggestions["create_in_trace"],
+		},
+		{
+			input: "create span in trace my-trace as ",
+			want: []prompt.Suggest{
+				{Text: "db", Description: "database call made by a client"},
+				{Text: "http.client", Description: "HTTP request sent by a client"},
+				{Text: "http.server", Description: "HTTP request handled by a server"},
+				{Text: "messaging.kafka", Description: "Kafka message sent by a producer or received by a consumer"},
+				{Text: "rpc.grpc", Description: "gRPC call made by a client or handled by a server"},
+			},
+		},
+		{
+			input: "create span in trace my-trace as h",
+			want: []prompt.Suggest{
+				{Text: "http.client", Description: "HTTP request sent by a client"},
+				{Text: "http.server", Description: "HTTP request handled by a server"},
+			},
+		},
+		{
+			input: "create span in trace my-trace as rpc.grpc service=shop.Cart ",
+			want: []prompt.Suggest{
+				{Text: "method=", Description: "name of the method, e.g. AddItem (required)"},
+				{Text: "status=", Description: "numeric gRPC status code, e.g. 0"},
+				{Text: "resource", Description: "Set a resource for the span"},
+				{Text: "attributes", Description: "Add attributes to the span"},
+				{Text: "duration", Description: "Set a distribution of the span duration"},
+				{Text: "kind", Description: "Set the kind of the span"},
 			},
 		},
 	}
-
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
 			telemetry.InitStore()
@@ -217,6 +281,27 @@ func TestCompleteCreateResource(t *testing.T) {
 		},
 		{
 			input: "create resource resource1 ",
+			want: []prompt.Suggest{
+				{Text: "attributes", Description: "Add attributes to the resource"},
+				{Text: "semconv", Description: "Set the semconv version of the resource"},
+				{Text: "defaults", Description: "Merge the SDK default and environment resource"},
+			},
+		},
+		{
+			input: "create resource resource1 semconv v1.2",
+			want: []prompt.Suggest{
+				{Text: "v1.20.0"},
+				{Text: "v1.21.0"},
+				{Text: "v1.24.0"},
+				{Text: "v1.26.0"},
+			},
+		},
+		{
+			input: "create resource resource1 semconv v1.26.0 defaults ",
+			want:  commandSuggestions["defaults"],
+		},
+		{
+			input: "create resource resource1 semconv v1.26.0 defaults on ",
 			want: []prompt.Suggest{
 				{Text: "attributes", Description: "Add attributes to the resource"},
 			},
@@ -247,6 +332,7 @@ func TestCompleteSet(t *testing.T) {
 			input: "set s",
 			want: []prompt.Suggest{
 				{Text: "span", Description: "Update a span"},
+				{Text: "spans", Description: "Update all the spans selected by a pattern, e.g. spans db_*"},
 			},
 		},
 		{
@@ -291,7 +377,11 @@ func TestCompleteSetSpan(t *testing.T) {
 			want: []prompt.Suggest{
 				{Text: "name", Description: "Set a new name for the span"},
 				{Text: "resource", Description: "Set a resource for the span"},
+				{Text: "duration", Description: "Set a distribution of the span duration"},
+				{Text: "kind", Description: "Set the kind of the span"},
 				{Text: "attributes", Description: "Set attributes for the span"},
+				{Text: "add-attributes", Description: "Add or overwrite attributes of the span"},
+				{Text: "remove-attributes", Description: "Remove attributes from the span"},
 			},
 		},
 		{
@@ -308,7 +398,11 @@ func TestCompleteSetSpan(t *testing.T) {
 			input: "set span my-span name new-span-name ",
 			want: []prompt.Suggest{
 				{Text: "resource", Description: "Set a resource for the span"},
+				{Text: "duration", Description: "Set a distribution of the span duration"},
+				{Text: "kind", Description: "Set the kind of the span"},
 				{Text: "attributes", Description: "Set attributes for the span"},
+				{Text: "add-attributes", Description: "Add or overwrite attributes of the span"},
+				{Text: "remove-attributes", Description: "Remove attributes from the span"},
 			},
 		},
 		{
@@ -316,6 +410,8 @@ func TestCompleteSetSpan(t *testing.T) {
 			want: []prompt.Suggest{
 				{Text: "name", Description: "Set a new name for the span"},
 				{Text: "resource", Description: "Set a resource for the span"},
+				{Text: "duration", Description: "Set a distribution of the span duration"},
+				{Text: "kind", Description: "Set the kind of the span"},
 			},
 		},
 		{
@@ -328,24 +424,44 @@ func TestCompleteSetSpan(t *testing.T) {
 		{
 			input: "set span my-span name new-span-name resource me-resource ",
 			want: []prompt.Suggest{
+				{Text: "duration", Description: "Set a distribution of the span duration"},
+				{Text: "kind", Description: "Set the kind of the span"},
 				{Text: "attributes", Description: "Set attributes for the span"},
+				{Text: "add-attributes", Description: "Add or overwrite attributes of the span"},
+				{Text: "remove-attributes", Description: "Remove attributes from the span"},
 			},
 		},
 		{
 			input: "set span my-span resource my-resource ",
 			want: []prompt.Suggest{
 				{Text: "name", Description: "Set a new name for the span"},
+				{Text: "duration", Description: "Set a distribution of the span duration"},
+				{Text: "kind", Description: "Set the kind of the span"},
 				{Text: "attributes", Description: "Set attributes for the span"},
+				{Text: "add-attributes", Description: "Add or overwrite attributes of the span"},
+				{Text: "remove-attributes", Description: "Remove attributes from the span"},
 			},
 		},
 		{
 			input: "set span my-span resource my-resource name ",
 			want:  []prompt.Suggest{},
 		},
+		{
+			input: "set span my-span duration ",
+			want:  commandSuggestions["duration"],
+		},
+		{
+			input: "set span my-span kind ",
+			want:  commandSuggestions["kind"],
+		},
 		{
 			input: "set span my-span resource my-resource name new-span-name ",
 			want: []prompt.Suggest{
+				{Text: "duration", Description: "Set a distribution of the span duration"},
+				{Text: "kind", Description: "Set the kind of the span"},
 				{Text: "attributes", Description: "Set attributes for the span"},
+				{Text: "add-attributes", Description: "Add or overwrite attributes of the span"},
+				{Text: "remove-attributes", Description: "Remove attributes from the span"},
 			},
 		},
 	}
@@ -394,6 +510,10 @@ func TestCompleteSetResource(t *testing.T) {
 			want: []prompt.Suggest{
 				{Text: "name", Description: "Set a new name for the resource"},
 				{Text: "attributes", Description: "Set attributes for the resource"},
+				{Text: "add-attributes", Description: "Add or overwrite attributes of the resource"},
+				{Text: "remove-attributes", Description: "Remove attributes from the resource"},
+				{Text: "semconv", Description: "Set the semconv version of the resource"},
+				{Text: "defaults", Description: "Merge the SDK default and environment resource"},
 			},
 		},
 		{
@@ -410,8 +530,14 @@ func TestCompleteSetResource(t *testing.T) {
 			input: "set resource my-resource attributes key=value ",
 			want: []prompt.Suggest{
 				{Text: "name", Description: "Set a new name for the resource"},
+				{Text: "semconv", Description: "Set the semconv version of the resource"},
+				{Text: "defaults", Description: "Merge the SDK default and environment resource"},
 			},
 		},
+		{
+			input: "set resource my-resource defaults o",
+			want:  commandSuggestions["defaults"],
+		},
 	}
 
 	for _, tt := range tests {
@@ -452,6 +578,8 @@ func TestCompleteSetEvent(t *testing.T) {
 			want: []prompt.Suggest{
 				{Text: "name", Description: "Set a new name for the event"},
 				{Text: "attributes", Description: "Set attributes for the event"},
+				{Text: "add-attributes", Description: "Add or overwrite attributes of the event"},
+				{Text: "remove-attributes", Description: "Remove attributes from the event"},
 			},
 		},
 		{
@@ -468,6 +596,8 @@ func TestCompleteSetEvent(t *testing.T) {
 			input: "set event my-event name new-event-name ",
 			want: []prompt.Suggest{
 				{Text: "attributes", Description: "Set attributes for the event"},
+				{Text: "add-attributes", Description: "Add or overwrite attributes of the event"},
+				{Text: "remove-attributes", Description: "Remove attributes from the event"},
 			},
 		},
 	}
@@ -505,6 +635,7 @@ func TestCompleteAdd(t *testing.T) {
 		{
 			input: "add link ",
 			want: []prompt.Suggest{
+				{Text: "spans", Description: "Select spans by a pattern, e.g. spans db_*"},
 				{Text: "me-span"},
 				{Text: "my-span"},
 			},
@@ -537,6 +668,7 @@ func TestCompleteAdd(t *testing.T) {
 		{
 			input: "add event ",
 			want: []prompt.Suggest{
+				{Text: "spans", Description: "Select spans by a pattern, e.g. spans db_*"},
 				{Text: "me-span"},
 				{Text: "my-span"},
 			},
@@ -610,3 +742,1003 @@ func TestCompleteList(t *testing.T) {
 		})
 	}
 }
+
+func TestCompleteSetTrace(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []prompt.Suggest
+	}{
+		{
+			input: "set trace ",
+			want: []prompt.Suggest{
+				{Text: "me-trace"},
+				{Text: "my-trace"},
+			},
+		},
+		{
+			input: "set trace my-trace ",
+			want:  commandSuggestions["set_trace"],
+		},
+		{
+			input: "set trace my-trace inherit-resource ",
+			want:  commandSuggestions["inherit_resource"],
+		},
+		{
+			input: "set trace my-trace inherit-resource d",
+			want: []prompt.Suggest{
+				{Text: "default", Description: "Follow the global inherit-resource option"},
+			},
+		},
+		{
+			input: "set trace my-trace inherit-resource on ",
+			want:  []prompt.Suggest{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			telemetry.InitStore()
+			telemetry.CreateTrace("my-trace")
+			telemetry.CreateTrace("me-trace")
+
+			buf := prompt.NewBuffer()
+			buf.InsertText(tt.input, false, true)
+			doc := buf.Document()
+			got := Completer(*doc)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompleteOption(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []prompt.Suggest
+	}{
+		{
+			input: "option ",
+			want:  commandSuggestions["option"],
+		},
+		{
+			input: "option inherit-resource ",
+			want:  commandSuggestions["on_off"],
+		},
+		{
+			input: "option inherit-resource of",
+			want: []prompt.Suggest{
+				{Text: "off", Description: "Disable the option"},
+			},
+		},
+		{
+			input: "option inherit-resource off ",
+			want:  []prompt.Suggest{},
+		},
+		{
+			input: "option jitter ",
+			want:  []prompt.Suggest{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			buf := prompt.NewBuffer()
+			buf.InsertText(tt.input, false, true)
+			doc := buf.Document()
+			got := Completer(*doc)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompleteDelete(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []prompt.Suggest
+	}{
+		{
+			input: "delete ",
+			want:  commandSuggestions["delete_type"],
+		},
+		{
+			input: "delete s",
+			want: []prompt.Suggest{
+				{Text: "span", Description: "Delete a span and its descendants"},
+				{Text: "spans", Description: "Delete all the spans selected by a pattern, e.g. spans cache_*"},
+			},
+		},
+		{
+			input: "delete trace ",
+			want: []prompt.Suggest{
+				{Text: "me-trace"},
+				{Text: "my-trace"},
+			},
+		},
+		{
+			input: "delete span my",
+			want: []prompt.Suggest{
+				{Text: "my-span"},
+			},
+		},
+		{
+			input: "delete resource ",
+			want: []prompt.Suggest{
+				{Text: "me-resource"},
+				{Text: "my-resource"},
+			},
+		},
+		{
+			input: "delete event ",
+			want: []prompt.Suggest{
+				{Text: "me-event"},
+				{Text: "my-event"},
+			},
+		},
+		{
+			input: "delete link my-span ",
+			want: []prompt.Suggest{
+				{Text: "me-span"},
+				{Text: "my-span"},
+			},
+		},
+		{
+			input: "delete link my-span me-span ",
+			want:  []prompt.Suggest{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			telemetry.InitStore()
+			telemetry.CreateTrace("my-trace")
+			telemetry.CreateResource("my-resource", map[string]string{"key": "value"})
+			telemetry.CreateEvent("my-event", map[string]string{"key": "value"})
+			telemetry.AddSpanToTrace("my-trace", "my-span", map[string]string{"key": "value"})
+			telemetry.CreateTrace("me-trace")
+			telemetry.CreateResource("me-resource", map[string]string{"key": "value"})
+			telemetry.CreateEvent("me-event", map[string]string{"key": "value"})
+			telemetry.AddSpanToTrace("me-trace", "me-span", map[string]string{"key": "value"})
+
+			buf := prompt.NewBuffer()
+			buf.InsertText(tt.input, false, true)
+			doc := buf.Document()
+			got := Completer(*doc)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompleteMove(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []prompt.Suggest
+	}{
+		{
+			input: "move ",
+			want:  commandSuggestions["move_type"],
+		},
+		{
+			input: "move span ",
+			want: []prompt.Suggest{
+				{Text: "me-span"},
+				{Text: "my-span"},
+			},
+		},
+		{
+			input: "move span my-span ",
+			want:  commandSuggestions["move_under_or_to"],
+		},
+		{
+			input: "move span my-span u",
+			want: []prompt.Suggest{
+				{Text: "under", Description: "Move the span under a parent span"},
+			},
+		},
+		{
+			input: "move span my-span under m",
+			want: []prompt.Suggest{
+				{Text: "me-span"},
+				{Text: "my-span"},
+			},
+		},
+		{
+			input: "move span my-span to ",
+			want:  commandSuggestions["move_to_trace"],
+		},
+		{
+			input: "move span my-span to trace ",
+			want: []prompt.Suggest{
+				{Text: "me-trace"},
+				{Text: "my-trace"},
+			},
+		},
+		{
+			input: "move span my-span to trace my-trace ",
+			want:  []prompt.Suggest{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			telemetry.InitStore()
+			telemetry.CreateTrace("my-trace")
+			telemetry.AddSpanToTrace("my-trace", "my-span", map[string]string{"key": "value"})
+			telemetry.CreateTrace("me-trace")
+			telemetry.AddSpanToTrace("me-trace", "me-span", map[string]string{"key": "value"})
+
+			buf := prompt.NewBuffer()
+			buf.InsertText(tt.input, false, true)
+			doc := buf.Document()
+			got := Completer(*doc)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompleteClone(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []prompt.Suggest
+	}{
+		{
+			input: "clone ",
+			want:  commandSuggestions["clone_type"],
+		},
+		{
+			input: "clone span ",
+			want: []prompt.Suggest{
+				{Text: "me-span"},
+				{Text: "my-span"},
+			},
+		},
+		{
+			input: "clone span my-span ",
+			want: []prompt.Suggest{
+				{Text: "as", Description: "Set the name prefix of the clones"},
+			},
+		},
+		{
+			input: "clone span my-span as ",
+			want:  []prompt.Suggest{},
+		},
+		{
+			input: "clone span my-span as db ",
+			want: []prompt.Suggest{
+				{Text: "count", Description: "Set the number of clones"},
+				{Text: "under", Description: "Set the parent span of the clones"},
+			},
+		},
+		{
+			input: "clone span my-span as db count 3 ",
+			want: []prompt.Suggest{
+				{Text: "under", Description: "Set the parent span of the clones"},
+			},
+		},
+		{
+			input: "clone span my-span as db count 3 under me",
+			want: []prompt.Suggest{
+				{Text: "me-span"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			telemetry.InitStore()
+			telemetry.CreateTrace("my-trace")
+			telemetry.AddSpanToTrace("my-trace", "my-span", map[string]string{"key": "value"})
+			telemetry.CreateTrace("me-trace")
+			telemetry.AddSpanToTrace("me-trace", "me-span", map[string]string{"key": "value"})
+
+			buf := prompt.NewBuffer()
+			buf.InsertText(tt.input, false, true)
+			doc := buf.Document()
+			got := Completer(*doc)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompleteSpanHandles(t *testing.T) {
+	telemetry.InitStore()
+	telemetry.CreateTrace("checkout")
+	telemetry.AddSpanToTrace("checkout", "GET /users", map[string]string{})
+	telemetry.AddSpanToSpan("checkout/GET__users", "db", map[string]string{})
+	telemetry.CreateTrace("search")
+	telemetry.AddSpanToTrace("search", "root", map[string]string{})
+	telemetry.AddSpanToSpan("root", "db", map[string]string{})
+
+	buf := prompt.NewBuffer()
+	buf.InsertText("set span ", false, true)
+	got := Completer(*buf.Document())
+
+	assert.Equal(t, []prompt.Suggest{
+		{Text: "checkout/GET__users", Description: "GET /users"},
+		{Text: "checkout/db", Description: "db"},
+		{Text: "root"},
+		{Text: "search/db", Description: "db"},
+	}, got)
+}
+
+func TestCompleteSetLink(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []prompt.Suggest
+	}{
+		{
+			input: "set l",
+			want: []prompt.Suggest{
+				{Text: "link", Description: "Update links between two spans"},
+			},
+		},
+		{
+			input: "set link ",
+			want: []prompt.Suggest{
+				{Text: "my-span"},
+				{Text: "target-span"},
+			},
+		},
+		{
+			input: "set link my-span ",
+			want: []prompt.Suggest{
+				{Text: "0", Description: "Link to target-span"},
+				{Text: "my-span"},
+				{Text: "target-span"},
+			},
+		},
+		{
+			input: "set link my-span t",
+			want: []prompt.Suggest{
+				{Text: "target-span"},
+			},
+		},
+		{
+			input: "set link my-span 0 ",
+			want: []prompt.Suggest{
+				{Text: "attributes", Description: "Set attributes for the links"},
+				{Text: "add-attributes", Description: "Add or overwrite attributes of the links"},
+				{Text: "remove-attributes", Description: "Remove attributes from the links"},
+			},
+		},
+		{
+			input: "set link my-span target-span ",
+			want: []prompt.Suggest{
+				{Text: "attributes", Description: "Set attributes for the links"},
+				{Text: "add-attributes", Description: "Add or overwrite attributes of the links"},
+				{Text: "remove-attributes", Description: "Remove attributes from the links"},
+			},
+		},
+		{
+			input: "set link my-span target-span add-attributes a=x ",
+			want: []prompt.Suggest{
+				{Text: "remove-attributes", Description: "Remove attributes from the links"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			telemetry.InitStore()
+			telemetry.CreateTrace("my-trace")
+			telemetry.AddSpanToTrace("my-trace", "my-span", map[string]string{})
+			telemetry.AddSpanToSpan("my-span", "target-span", map[string]string{})
+			telemetry.AddLinkToSpan("my-span", "target-span", map[string]string{})
+
+			buf := prompt.NewBuffer()
+			buf.InsertText(tt.input, false, true)
+			got := Completer(*buf.Document())
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompleteRemoveAttributes(t *testing.T) {
+	telemetry.InitStore()
+	telemetry.CreateTrace("my-trace")
+	telemetry.AddSpanToTrace("my-trace", "my-span", map[string]string{"http.method": "GET", "http.url": "/"})
+
+	buf := prompt.NewBuffer()
+	buf.InsertText("set span my-span remove-attributes http.m", false, true)
+	got := Completer(*buf.Document())
+
+	assert.Equal(t, []prompt.Suggest{
+		{Text: "http.method", Description: "GET"},
+	}, got)
+}
+
+func TestCompleteListLinks(t *testing.T) {
+	telemetry.InitStore()
+	telemetry.CreateTrace("my-trace")
+	telemetry.AddSpanToTrace("my-trace", "my-span", map[string]string{})
+
+	tests := []struct {
+		input string
+		want  []prompt.Suggest
+	}{
+		{
+			input: "list l",
+			want: []prompt.Suggest{
+				{Text: "links", Description: "List links of spans with their indexes"},
+			},
+		},
+		{
+			input: "list links ",
+			want: []prompt.Suggest{
+				{Text: "my-span"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			buf := prompt.NewBuffer()
+			buf.InsertText(tt.input, false, true)
+			got := Completer(*buf.Document())
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompleteExpect(t *testing.T) {
+	telemetry.InitTracerManager(telemetry.EnableMemoryExporter(), nil)
+	telemetry.InitStore()
+	telemetry.CreateTrace("my-trace")
+	telemetry.AddSpanToTrace("my-trace", "my-span", map[string]string{})
+	telemetry.AddSpanToSpan("my-span", "child-span", map[string]string{})
+	telemetry.SendAllTraces()
+
+	tests := []struct {
+		input string
+		want  []prompt.Suggest
+	}{
+		{
+			input: "expect ",
+			want:  commandSuggestions["expect_type"],
+		},
+		{
+			input: "expect span ",
+			want: []prompt.Suggest{
+				{Text: "my-trace/child-span"},
+				{Text: "my-trace/my-span"},
+			},
+		},
+		{
+			input: "expect trace ",
+			want: []prompt.Suggest{
+				{Text: "my-trace", Description: "2 spans"},
+			},
+		},
+		{
+			input: "expect span child-span ",
+			want:  commandSuggestions["expect_span"],
+		},
+		{
+			input: "expect trace my-trace ",
+			want:  commandSuggestions["expect_trace"],
+		},
+		{
+			input: "expect span child-span has ",
+			want:  commandSuggestions["expect_has"],
+		},
+		{
+			input: "expect span child-span has parent my-trace/m",
+			want: []prompt.Suggest{
+				{Text: "my-trace/my-span"},
+			},
+		},
+		{
+			input: "expect span child-span has attribute ",
+			want:  []prompt.Suggest{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			buf := prompt.NewBuffer()
+			buf.InsertText(tt.input, false, true)
+			got := Completer(*buf.Document())
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompleteReceived(t *testing.T) {
+	r, err := receiver.Start("127.0.0.1:0", "")
+	if err != nil {
+		t.Fatalf("Failed to start receiver: %v", err)
+	}
+	receiver.SetDefault(r)
+	t.Cleanup(func() {
+		receiver.SetDefault(nil)
+		r.Shutdown(context.Background())
+	})
+	r.Export(&coltracepb.ExportTraceServiceRequest{
+		ResourceSpans: []*tracepb.ResourceSpans{{
+			Resource: &resourcepb.Resource{Attributes: []*commonpb.KeyValue{
+				{Key: "service.name", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "cart"}}},
+			}},
+			ScopeSpans: []*tracepb.ScopeSpans{{
+				Spans: []*tracepb.Span{{
+					TraceId: bytes.Repeat([]byte{0xab}, 16),
+					SpanId:  bytes.Repeat([]byte{1}, 8),
+					Name:    "checkout",
+				}},
+			}},
+		}},
+	})
+
+	tests := []struct {
+		input string
+		want  []prompt.Suggest
+	}{
+		{
+			input: "received ",
+			want:  commandSuggestions["received"],
+		},
+		{
+			input: "received s",
+			want: []prompt.Suggest{
+				{Text: "show", Description: "Show the spans of a received trace"},
+			},
+		},
+		{
+			input: "received show ",
+			want: []prompt.Suggest{
+				{Text: "abababababababababababababababab", Description: "checkout (cart) 1 spans"},
+			},
+		},
+		{
+			input: "received show abab",
+			want: []prompt.Suggest{
+				{Text: "abababababababababababababababab", Description: "checkout (cart) 1 spans"},
+			},
+		},
+		{
+			input: "received list ",
+			want:  []prompt.Suggest{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			buf := prompt.NewBuffer()
+			buf.InsertText(tt.input, false, true)
+			got := Completer(*buf.Document())
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompleteGenerate(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []prompt.Suggest
+	}{
+		{
+			input: "generate ",
+			want:  []prompt.Suggest{{Text: "from", Description: "Set the topology file"}},
+		},
+		{
+			input: "generate from ",
+			want:  []prompt.Suggest{},
+		},
+		{
+			input: "generate from topology.yaml ",
+			want: []prompt.Suggest{
+				{Text: "count", Description: "Set the number of traces"},
+				{Text: "seed", Description: "Set the seed to generate the same traces again"},
+				{Text: "as", Description: "Set the name prefix of the traces"},
+				{Text: "stream", Description: "Send the traces one by one without keeping them"},
+			},
+		},
+		{
+			input: "generate from topology.yaml count 10 seed 1 ",
+			want: []prompt.Suggest{
+				{Text: "as", Description: "Set the name prefix of the traces"},
+				{Text: "stream", Description: "Send the traces one by one without keeping them"},
+			},
+		},
+		{
+			input: "generate from topology.yaml seed ",
+			want:  []prompt.Suggest{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			buf := prompt.NewBuffer()
+			buf.InsertText(tt.input, false, true)
+			got := Completer(*buf.Document())
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompleteSend(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []prompt.Suggest
+	}{
+		{
+			input: "send ",
+			want: []prompt.Suggest{
+				{Text: "--lint", Description: "Lint the signals first and send nothing if there are errors"},
+				{Text: "seed", Description: "Set the seed to send the same random values again"},
+				{Text: "errors", Description: "Fail a percentage of the spans, e.g. errors 5%"},
+			},
+		},
+		{
+			input: "send --lint ",
+			want: []prompt.Suggest{
+				{Text: "seed", Description: "Set the seed to send the same random values again"},
+				{Text: "errors", Description: "Fail a percentage of the spans, e.g. errors 5%"},
+			},
+		},
+		{
+			input: "send seed 42 ",
+			want: []prompt.Suggest{
+				{Text: "errors", Description: "Fail a percentage of the spans, e.g. errors 5%"},
+			},
+		},
+		{
+			input: "send errors ",
+			want:  []prompt.Suggest{},
+		},
+		{
+			input: "send errors 5% ",
+			want: []prompt.Suggest{
+				{Text: "for", Description: "Fail only the spans with a resource or a name"},
+				{Text: "attribute", Description: "Set the error attribute instead of error.type=injected"},
+				{Text: "propagate", Description: "Fail the ancestors of the failed spans too"},
+				{Text: "errors", Description: "Fail a percentage of other spans"},
+			},
+		},
+		{
+			input: "send errors 5% for ",
+			want:  commandSuggestions["send_errors_for"],
+		},
+		{
+			input: "send errors 5% for resource ",
+			want: []prompt.Suggest{
+				{Text: "me-resource"},
+				{Text: "my-resource"},
+			},
+		},
+		{
+			input: "send errors 5% for resource my",
+			want: []prompt.Suggest{
+				{Text: "my-resource"},
+			},
+		},
+		{
+			input: "send errors 5% for span ",
+			want: []prompt.Suggest{
+				{Text: "me-span"},
+				{Text: "my-span"},
+			},
+		},
+		{
+			input: "send errors 5% for span my-span ",
+			want: []prompt.Suggest{
+				{Text: "attribute", Description: "Set the error attribute instead of error.type=injected"},
+				{Text: "propagate", Description: "Fail the ancestors of the failed spans too"},
+				{Text: "errors", Description: "Fail a percentage of other spans"},
+			},
+		},
+		{
+			input: "send errors 5% attribute ",
+			want:  []prompt.Suggest{},
+		},
+		{
+			input: "send errors 5% propagate ",
+			want: []prompt.Suggest{
+				{Text: "errors", Description: "Fail a percentage of other spans"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			telemetry.InitStore()
+			telemetry.CreateTrace("my-trace")
+			telemetry.CreateResource("my-resource", map[string]string{"key": "value"})
+			telemetry.AddSpanToTrace("my-trace", "my-span", map[string]string{"key": "value"})
+			telemetry.CreateTrace("me-trace")
+			telemetry.CreateResource("me-resource", map[string]string{"key": "value"})
+			telemetry.AddSpanToTrace("me-trace", "me-span", map[string]string{"key": "value"})
+
+			buf := prompt.NewBuffer()
+			buf.InsertText(tt.input, false, true)
+			doc := buf.Document()
+			got := Completer(*doc)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompleteVariables(t *testing.T) {
+	assert.NoError(t, executor.SetVariable("env", "staging"))
+	assert.NoError(t, executor.SetVariable("endpoint", "/cart"))
+
+	tests := []struct {
+		input string
+		want  []prompt.Suggest
+	}{
+		{
+			input: "create span ${",
+			want: []prompt.Suggest{
+				{Text: "${endpoint}", Description: "/cart"},
+				{Text: "${env}", Description: "staging"},
+			},
+		},
+		{
+			input: "create span my-span attributes env=${env",
+			want: []prompt.Suggest{
+				{Text: "env=${env}", Description: "staging"},
+			},
+		},
+		{
+			input: "create resource ${env} ",
+			want: []prompt.Suggest{
+				{Text: "attributes", Description: "Add attributes to the resource"},
+				{Text: "semconv", Description: "Set the semconv version of the resource"},
+				{Text: "defaults", Description: "Merge the SDK default and environment resource"},
+			},
+		},
+		{
+			input: "let ",
+			want: []prompt.Suggest{
+				{Text: "endpoint", Description: "/cart"},
+				{Text: "env", Description: "staging"},
+			},
+		},
+		{
+			input: "let env = ",
+			want:  []prompt.Suggest{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			telemetry.InitStore()
+
+			buf := prompt.NewBuffer()
+			buf.InsertText(tt.input, false, true)
+			doc := buf.Document()
+			got := Completer(*doc)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompleteTemplates(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []prompt.Suggest
+	}{
+		{
+			input: "define ",
+			want:  commandSuggestions["define"],
+		},
+		{
+			input: "instantiate ",
+			want: []prompt.Suggest{
+				{Text: "cache", Description: "()"},
+				{Text: "http_handler", Description: "(svc, route)"},
+			},
+		},
+		{
+			input: "instantiate http_handler ",
+			want: []prompt.Suggest{
+				{Text: "under", Description: "Create the spans under a span"},
+				{Text: "svc=", Description: "Parameter of the template"},
+				{Text: "route=", Description: "Parameter of the template"},
+			},
+		},
+		{
+			input: "instantiate http_handler under ",
+			want: []prompt.Suggest{
+				{Text: "my-span"},
+			},
+		},
+		{
+			input: "instantiate http_handler under my-span svc=cart ",
+			want: []prompt.Suggest{
+				{Text: "route=", Description: "Parameter of the template"},
+			},
+		},
+		{
+			input: "instantiate http_handler under my-span svc=",
+			want:  []prompt.Suggest{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			telemetry.InitStore()
+			telemetry.CreateTrace("my-trace")
+			telemetry.AddSpanToTrace("my-trace", "my-span", map[string]string{})
+			assert.NoError(t, telemetry.DefineTemplate(telemetry.Template{Name: "http_handler", Params: []string{"svc", "route"}}))
+			assert.NoError(t, telemetry.DefineTemplate(telemetry.Template{Name: "cache"}))
+
+			buf := prompt.NewBuffer()
+			buf.InsertText(tt.input, false, true)
+			doc := buf.Document()
+			got := Completer(*doc)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompleteSpanSelectors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []prompt.Suggest
+	}{
+		{
+			input: "set spans ",
+			want: []prompt.Suggest{
+				{Text: "db_insert"},
+				{Text: "db_query"},
+			},
+		},
+		{
+			input: "set spans db_* ",
+			want: []prompt.Suggest{
+				{Text: "where", Description: "Select only the spans with the resource or the attribute"},
+				{Text: "in", Description: "Select only the spans in the trace"},
+				{Text: "dry-run", Description: "Show the selected spans without changing them"},
+				{Text: "name", Description: "Set a new name for the spans"},
+				{Text: "resource", Description: "Set a resource for the spans"},
+				{Text: "duration", Description: "Set a distribution of the span durations"},
+				{Text: "kind", Description: "Set the kind of the spans"},
+				{Text: "attributes", Description: "Set attributes for the spans"},
+				{Text: "add-attributes", Description: "Add or overwrite attributes of the spans"},
+				{Text: "remove-attributes", Description: "Remove attributes from the spans"},
+			},
+		},
+		{
+			input: "set spans db_* where ",
+			want:  commandSuggestions["span_condition"],
+		},
+		{
+			input: "set spans db_* where resource=db a",
+			want: []prompt.Suggest{
+				{Text: "and", Description: "Add another condition"},
+				{Text: "attributes", Description: "Set attributes for the spans"},
+				{Text: "add-attributes", Description: "Add or overwrite attributes of the spans"},
+			},
+		},
+		{
+			input: "set spans db_* in ",
+			want: []prompt.Suggest{
+				{Text: "trace", Description: "Select only the spans in the trace"},
+			},
+		},
+		{
+			input: "set spans db_* in trace ",
+			want: []prompt.Suggest{
+				{Text: "my-trace"},
+			},
+		},
+		{
+			input: "set spans db_* resource ",
+			want: []prompt.Suggest{
+				{Text: "db"},
+			},
+		},
+		{
+			input: "set spans db_* resource db ",
+			want: []prompt.Suggest{
+				{Text: "name", Description: "Set a new name for the spans"},
+				{Text: "duration", Description: "Set a distribution of the span durations"},
+				{Text: "kind", Description: "Set the kind of the spans"},
+				{Text: "attributes", Description: "Set attributes for the spans"},
+				{Text: "add-attributes", Description: "Add or overwrite attributes of the spans"},
+				{Text: "remove-attributes", Description: "Remove attributes from the spans"},
+				{Text: "dry-run", Description: "Show the selected spans without changing them"},
+			},
+		},
+		{
+			input: "delete spans db_* in trace my-trace ",
+			want: []prompt.Suggest{
+				{Text: "dry-run", Description: "Show the selected spans without changing them"},
+			},
+		},
+		{
+			input: "add event spans db_* in trace my-trace ",
+			want: []prompt.Suggest{
+				{Text: "dry-run", Description: "Show the selected spans without changing them"},
+				{Text: "retry"},
+			},
+		},
+		{
+			input: "add event spans db_* retry ",
+			want: []prompt.Suggest{
+				{Text: "dry-run", Description: "Show the selected spans without changing them"},
+			},
+		},
+		{
+			input: "add link spans db_* where attr db.system=pg db_q",
+			want: []prompt.Suggest{
+				{Text: "db_query"},
+			},
+		},
+		{
+			input: "add link spans db_* db_query ",
+			want: []prompt.Suggest{
+				{Text: "attributes", Description: "Add attributes to the links"},
+				{Text: "dry-run", Description: "Show the selected spans without changing them"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			telemetry.InitStore()
+			telemetry.CreateTrace("my-trace")
+			telemetry.CreateResource("db", map[string]string{})
+			telemetry.CreateEvent("retry", map[string]string{})
+			telemetry.AddSpanToTrace("my-trace", "db_query", map[string]string{"db.system": "pg"})
+			telemetry.AddSpanToSpan("db_query", "db_insert", map[string]string{})
+
+			buf := prompt.NewBuffer()
+			buf.InsertText(tt.input, false, true)
+			doc := buf.Document()
+			got := Completer(*doc)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCompleteWorkspace(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []prompt.Suggest
+	}{
+		{
+			input: "workspace ",
+			want:  commandSuggestions["workspace"],
+		},
+		{
+			input: "workspace s",
+			want: []prompt.Suggest{
+				{Text: "switch", Description: "Switch to another workspace"},
+			},
+		},
+		{
+			input: "workspace switch ",
+			want: []prompt.Suggest{
+				{Text: "batch", Description: "1 traces, 0 spans (current)"},
+				{Text: "default", Description: "0 traces, 0 spans"},
+			},
+		},
+		{
+			input: "workspace copy b",
+			want: []prompt.Suggest{
+				{Text: "batch", Description: "1 traces, 0 spans (current)"},
+			},
+		},
+		{
+			input: "workspace new ",
+			want:  []prompt.Suggest{},
+		},
+	}
+
+	telemetry.InitStore()
+	assert.NoError(t, telemetry.NewWorkspace("batch"))
+	telemetry.CreateTrace("job")
+	t.Cleanup(func() {
+		_ = telemetry.SwitchWorkspace(telemetry.DefaultWorkspace)
+		_ = telemetry.DeleteWorkspace("batch")
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			buf := prompt.NewBuffer()
+			buf.InsertText(tt.input, false, true)
+			doc := buf.Document()
+			got := Completer(*doc)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
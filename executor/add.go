@@ -6,10 +6,22 @@ import (
 	"github.com/ymtdzzz/otelgen/telemetry"
 )
 
-func handleAddLinkCommand(cmd *AddLinkCommand) {
+func handleAddLinkCommand(cmd *AddLinkCommand) bool {
 	if err := cmd.Validate(); err != nil {
 		fmt.Printf("Error validating add link command: %v\n", err)
-		return
+		return false
+	}
+	if err := runAddLinkCommand(cmd); err != nil {
+		fmt.Printf("Error %v\n", err)
+		return false
+	}
+	return true
+}
+
+// runAddLinkCommand runs the validated add link command and returns what failed
+func runAddLinkCommand(cmd *AddLinkCommand) error {
+	if cmd.Spans != nil {
+		return addLinkToSpans(cmd)
 	}
 
 	var (
@@ -24,21 +36,33 @@ func handleAddLinkCommand(cmd *AddLinkCommand) {
 
 	_, err := telemetry.AddLinkToSpan(*cmd.From, *cmd.To, attributes)
 	if err != nil {
-		fmt.Printf("Error adding link: %v\n", err)
-		return
+		return fmt.Errorf("adding link: %w", err)
 	}
 	fmt.Printf("Added link from '%s' to '%s'\n", *cmd.From, *cmd.To)
+	return nil
 }
 
-func handleAddEventCommand(cmd *AddEventCommand) {
+func handleAddEventCommand(cmd *AddEventCommand) bool {
 	if err := cmd.Validate(); err != nil {
 		fmt.Printf("Error validating add event command: %v\n", err)
-		return
+		return false
+	}
+	if err := runAddEventCommand(cmd); err != nil {
+		fmt.Printf("Error %v\n", err)
+		return false
+	}
+	return true
+}
+
+// runAddEventCommand runs the validated add event command and returns what failed
+func runAddEventCommand(cmd *AddEventCommand) error {
+	if cmd.Spans != nil {
+		return addEventToSpans(cmd)
 	}
 
 	if _, err := telemetry.AddEventToSpan(*cmd.SpanName, *cmd.EventName); err != nil {
-		fmt.Printf("Error adding event to span: %v\n", err)
-		return
+		return fmt.Errorf("adding event to span: %w", err)
 	}
 	fmt.Printf("Added event '%s' to span '%s'\n", *cmd.EventName, *cmd.SpanName)
+	return nil
 }
@@ -21,7 +21,7 @@ func TestHandleAddLink_OK(t *testing.T) {
 
 	handleAddLinkCommand(cmd.AddLink)
 
-	span, exists := telemetry.GetSpans()["my-span"]
+	span, exists := telemetry.GetSpans()["my-trace/my-span"]
 	assert.True(t, exists)
 
 	links := span.Links
@@ -75,7 +75,7 @@ func TestHandleAddEvent_OK(t *testing.T) {
 
 	handleAddEventCommand(cmd.AddEvent)
 
-	span, exists := telemetry.GetSpans()["my-span"]
+	span, exists := telemetry.GetSpans()["my-trace/my-span"]
 	assert.True(t, exists)
 
 	events := span.Events
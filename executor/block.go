@@ -0,0 +1,128 @@
+package executor
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ymtdzzz/otelgen/telemetry"
+)
+
+// generatorPattern matches the generator expressions, whose braces are not the braces of a block
+var generatorPattern = regexp.MustCompile(`\{\{[^}]*\}\}`)
+
+// pendingBlock are the lines of a trace block which continues on the next lines
+// because its braces are not closed yet
+var pendingBlock []string
+
+// collectBlockLine returns the whole trace block once its braces are closed. It reports false
+// while the block continues on the next lines.
+func collectBlockLine(line string) (string, bool) {
+	if pendingBlock == nil && !strings.HasPrefix(line, "trace ") {
+		return line, true
+	}
+	if pendingBlock != nil && strings.HasPrefix(line, "#") {
+		return "", false
+	}
+	block := strings.Join(append(pendingBlock, line), " ")
+	if braceDepth(block) > 0 {
+		pendingBlock = append(pendingBlock, line)
+		return "", false
+	}
+	pendingBlock = nil
+	return block, true
+}
+
+// braceDepth returns the number of the unclosed braces outside strings and generator expressions
+func braceDepth(s string) int {
+	depth := 0
+	var quote rune
+	for _, r := range generatorPattern.ReplaceAllString(s, "") {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '{':
+			depth++
+		case r == '}':
+			depth--
+		}
+	}
+	return depth
+}
+
+func handleTraceBlockCommand(cmd *TraceBlockCommand) bool {
+	if err := cmd.Validate(); err != nil {
+		fmt.Printf("Error validating trace command: %v\n", err)
+		return false
+	}
+
+	created := 0
+	err := telemetry.Atomically(func() error {
+		if !telemetry.IsTraceExists(*cmd.Name) {
+			telemetry.CreateTrace(*cmd.Name)
+		}
+		for _, root := range cmd.Block.Spans {
+			n, err := buildSpanBlock(root, func(attrs map[string]string) (*telemetry.Span, error) {
+				return telemetry.AddSpanToTrace(*cmd.Name, root.Name, attrs)
+			})
+			if err != nil {
+				return err
+			}
+			created += n
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("Error building trace %s, nothing is changed: %v\n", *cmd.Name, err)
+		return false
+	}
+	fmt.Printf("Built trace %s with %d spans\n", *cmd.Name, created)
+	return true
+}
+
+// buildSpanBlock adds the span with addSpan and then its children, and returns the number of the added spans
+func buildSpanBlock(b *SpanBlock, addSpan func(attrs map[string]string) (*telemetry.Span, error)) (int, error) {
+	attrs := make(map[string]string)
+	for _, p := range b.Properties {
+		if p.Key != "resource" && p.Key != "kind" && p.Key != "duration" {
+			attrs[p.Key] = p.Value
+		}
+	}
+	span, err := addSpan(attrs)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, p := range b.Properties {
+		switch p.Key {
+		case "resource":
+			if !telemetry.IsResourceExists(p.Value) {
+				return 0, fmt.Errorf("resource '%s' of span '%s' does not exist", p.Value, b.Name)
+			}
+			_, err = telemetry.SetResourceToSpan(span.Handle, p.Value)
+		case "kind":
+			err = setSpanKind(span.Handle, p.Value)
+		case "duration":
+			_, err = setSpanDuration(span.Handle, p.Value)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	count := 1
+	for _, child := range b.Children {
+		n, err := buildSpanBlock(child, func(attrs map[string]string) (*telemetry.Span, error) {
+			return telemetry.AddSpanToSpan(span.Handle, child.Name, attrs)
+		})
+		if err != nil {
+			return 0, err
+		}
+		count += n
+	}
+	return count, nil
+}
@@ -0,0 +1,92 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/otelgen/telemetry"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestHandleTraceBlock(t *testing.T) {
+	telemetry.InitStore()
+	telemetry.CreateResource("cart", map[string]string{})
+	telemetry.CreateResource("db", map[string]string{})
+	t.Cleanup(func() {
+		pendingBlock = nil
+	})
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{
+			input: `trace checkout { span "GET /cart" [resource=cart kind=server http.method=GET] { span "SELECT carts" [resource=db, duration=30ms] span cache } }`,
+			want:  "Built trace checkout with 3 spans\n",
+		},
+		{
+			input: "trace search {",
+			want:  "",
+		},
+		{
+			input: `  span "GET /search" [resource=cart] {`,
+			want:  "",
+		},
+		{
+			input: `    span "SELECT items" [resource=nope timeout=30s]`,
+			want:  "",
+		},
+		{
+			input: "  }",
+			want:  "",
+		},
+		{
+			input: "}",
+			want:  "Error building trace search, nothing is changed: resource 'nope' of span 'SELECT items' does not exist\n",
+		},
+		{
+			input: "trace checkout { span again }",
+			want:  "Error building trace checkout, nothing is changed: trace checkout already has a root span\n",
+		},
+		{
+			input: "trace empty",
+			want:  "Error validating trace command: spans of trace empty must be specified in { }\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			output := captureOutput(func() {
+				Executor(tt.input)
+			})
+			assert.Equal(t, tt.want, output)
+		})
+	}
+
+	assert.Len(t, telemetry.GetTraces(), 1, "Failed blocks should be rolled back")
+	spans := telemetry.GetSpans()
+	assert.Len(t, spans, 3)
+	root := spans["checkout/GET__cart"]
+	assert.Equal(t, trace.SpanKindServer, root.Kind)
+	assert.Equal(t, "cart", root.Resource.Name)
+	assert.Equal(t, map[string]string{"http.method": "GET"}, root.Attributes)
+	assert.Equal(t, []string{"SELECT carts", "cache"}, []string{root.Children[0].Name, root.Children[1].Name})
+	assert.Equal(t, "fixed(30ms)", root.Children[0].Duration.String())
+}
+
+func TestBraceDepth(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{input: "trace t {", want: 1},
+		{input: `trace t { span "a {" [id={{uuid}}] {`, want: 2},
+		{input: "trace t { span a { span b } }", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.want, braceDepth(tt.input))
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package executor
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ymtdzzz/otelgen/telemetry"
+)
+
+// selectSpans returns the spans matched by the selector. For dry-run, the spans are
+// shown and nil is returned so that nothing is changed.
+func selectSpans(sel *SpanSelector, dryRun bool) ([]*telemetry.Span, error) {
+	spans, err := telemetry.SelectSpans(sel.Selector())
+	if err != nil {
+		return nil, err
+	}
+	if len(spans) == 0 {
+		return nil, fmt.Errorf("no spans match %s", sel)
+	}
+	if dryRun {
+		fmt.Printf("%d spans match %s (dry run, nothing is changed):\n", len(spans), sel)
+		for _, span := range spans {
+			fmt.Printf("  %s\n", span.Handle)
+		}
+		return nil, nil
+	}
+	return spans, nil
+}
+
+// joinSpanHandles returns the handles of the spans in the order of selection joined by comma
+func joinSpanHandles(spans []*telemetry.Span) string {
+	handles := make([]string, 0, len(spans))
+	for _, span := range spans {
+		handles = append(handles, span.Handle)
+	}
+	return strings.Join(handles, ", ")
+}
+
+func handleSetSpansCommand(cmd *SetSpansCommand) bool {
+	if err := cmd.Validate(); err != nil {
+		fmt.Printf("Error validating set spans command: %v\n", err)
+		return false
+	}
+
+	spans, err := selectSpans(cmd.Spans, cmd.DryRun)
+	if err != nil {
+		fmt.Printf("Error selecting spans: %v\n", err)
+		return false
+	}
+	if spans == nil {
+		return true
+	}
+
+	err = telemetry.Atomically(func() error {
+		for _, span := range spans {
+			if err := updateSpan(span.Handle, cmd.Args); err != nil {
+				return fmt.Errorf("%s: %w", span.Handle, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("Error setting spans, nothing is changed: %v\n", err)
+		return false
+	}
+	fmt.Printf("Updated %d spans: %s\n", len(spans), joinSpanHandles(spans))
+	return true
+}
+
+func handleDeleteSpansCommand(cmd *DeleteSpansCommand) bool {
+	if err := cmd.Validate(); err != nil {
+		fmt.Printf("Error validating delete spans command: %v\n", err)
+		return false
+	}
+
+	spans, err := selectSpans(cmd.Spans, cmd.DryRun)
+	if err != nil {
+		fmt.Printf("Error selecting spans: %v\n", err)
+		return false
+	}
+	if spans == nil {
+		return true
+	}
+
+	var (
+		deleted []*telemetry.Span
+		removed []*telemetry.Span
+		links   int
+	)
+	err = telemetry.Atomically(func() error {
+		for _, span := range spans {
+			// the span is already removed with a selected ancestor
+			if _, exists := telemetry.GetSpans()[span.Handle]; !exists {
+				continue
+			}
+			result, err := telemetry.DeleteSpan(span.Handle)
+			if err != nil {
+				return fmt.Errorf("%s: %w", span.Handle, err)
+			}
+			deleted = append(deleted, span)
+			removed = append(removed, result.Spans[1:]...)
+			links += result.Links
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("Error deleting spans, nothing is changed: %v\n", err)
+		return false
+	}
+
+	fmt.Printf("Deleted %d spans: %s\n", len(deleted), joinSpanHandles(deleted))
+	var descendants []*telemetry.Span
+	for _, span := range removed {
+		if !slices.Contains(spans, span) {
+			descendants = append(descendants, span)
+		}
+	}
+	if len(descendants) > 0 {
+		fmt.Printf("  Removed descendant spans: %s\n", joinSpanNames(descendants))
+	}
+	if links > 0 {
+		fmt.Printf("  Removed links pointing at deleted spans: %d\n", links)
+	}
+	return true
+}
+
+// addEventToSpans adds the event to the spans selected by the validated add event command
+func addEventToSpans(cmd *AddEventCommand) error {
+	spans, err := selectSpans(cmd.Spans, cmd.DryRun)
+	if err != nil {
+		return fmt.Errorf("selecting spans: %w", err)
+	}
+	if spans == nil {
+		return nil
+	}
+
+	err = telemetry.Atomically(func() error {
+		for _, span := range spans {
+			if _, err := telemetry.AddEventToSpan(span.Handle, *cmd.EventName); err != nil {
+				return fmt.Errorf("%s: %w", span.Handle, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("adding event to spans, nothing is changed: %w", err)
+	}
+	fmt.Printf("Added event '%s' to %d spans: %s\n", *cmd.EventName, len(spans), joinSpanHandles(spans))
+	return nil
+}
+
+// addLinkToSpans adds the links from the spans selected by the validated add link command
+func addLinkToSpans(cmd *AddLinkCommand) error {
+	spans, err := selectSpans(cmd.Spans, cmd.DryRun)
+	if err != nil {
+		return fmt.Errorf("selecting spans: %w", err)
+	}
+	if spans == nil {
+		return nil
+	}
+
+	var attributes map[string]string
+	for _, arg := range cmd.Args {
+		if arg.Attrs != nil {
+			attributes = convertKeyValuesToMap(arg.Attrs)
+		}
+	}
+
+	err = telemetry.Atomically(func() error {
+		for _, span := range spans {
+			if _, err := telemetry.AddLinkToSpan(span.Handle, *cmd.To, attributes); err != nil {
+				return fmt.Errorf("%s: %w", span.Handle, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("adding links, nothing is changed: %w", err)
+	}
+	fmt.Printf("Added links from %d spans to '%s': %s\n", len(spans), *cmd.To, joinSpanHandles(spans))
+	return nil
+}
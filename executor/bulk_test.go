@@ -0,0 +1,159 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/otelgen/telemetry"
+)
+
+func setupBulkSpans() {
+	telemetry.InitStore()
+	telemetry.CreateResource("db", map[string]string{})
+	telemetry.CreateResource("postgres", map[string]string{})
+	telemetry.CreateEvent("retry", map[string]string{})
+	telemetry.CreateTrace("t")
+	_, _ = telemetry.AddSpanToTrace("t", "root", map[string]string{})
+	_, _ = telemetry.AddSpanToSpan("root", "db_query", map[string]string{"db.system": "pg"})
+	_, _ = telemetry.AddSpanToSpan("db_query", "cache_get", map[string]string{})
+	_, _ = telemetry.AddSpanToSpan("root", "db_insert", map[string]string{})
+	_, _ = telemetry.SetResourceToSpan("db_query", "db")
+}
+
+func TestHandleSetSpansCommand(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{
+			input: "set spans db_* resource postgres dry-run",
+			want:  "2 spans match spans db_* (dry run, nothing is changed):\n  t/db_query\n  t/db_insert\n",
+		},
+		{
+			input: "set spans db_* where resource=db add-attributes x=1",
+			want:  "Updated 1 spans: t/db_query\n",
+		},
+		{
+			input: "set spans where resource=db add-attributes y=2",
+			want:  "Updated 1 spans: t/db_query\n",
+		},
+		{
+			input: "set spans * where attr db.system=pg and resource=db in trace t kind client",
+			want:  "Updated 1 spans: t/db_query\n",
+		},
+		{
+			input: "set spans cache_* resource postgres",
+			want:  "Updated 1 spans: t/cache_get\n",
+		},
+		{
+			input: "set spans nope_* resource postgres",
+			want:  "Error selecting spans: no spans match spans nope_*\n",
+		},
+		{
+			input: "set spans db_* resource nope",
+			want:  "Error validating set spans command: resource 'nope' does not exist\n",
+		},
+		{
+			input: "set spans db_* where resource=nope resource postgres",
+			want:  "Error validating set spans command: resource 'nope' does not exist\n",
+		},
+		{
+			input: "set spans db_*",
+			want:  "Error validating set spans command: operation (name, resource, attributes etc.) must be specified for set spans command\n",
+		},
+	}
+
+	setupBulkSpans()
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			output := captureOutput(func() {
+				Executor(tt.input)
+			})
+			assert.Equal(t, tt.want, output)
+		})
+	}
+
+	spans := telemetry.GetSpans()
+	assert.Equal(t, "db", spans["t/db_query"].Resource.Name, "Dry run should not change the spans")
+	assert.Equal(t, map[string]string{"db.system": "pg", "x": "1", "y": "2"}, spans["t/db_query"].Attributes)
+	assert.Equal(t, "postgres", spans["t/cache_get"].Resource.Name)
+	assert.Nil(t, spans["t/db_insert"].Resource)
+}
+
+func TestHandleDeleteSpansCommand(t *testing.T) {
+	setupBulkSpans()
+
+	output := captureOutput(func() {
+		Executor("delete spans * where resource=db dry-run")
+	})
+	assert.Equal(t, "1 spans match spans * where resource=db (dry run, nothing is changed):\n  t/db_query\n", output)
+	assert.Len(t, telemetry.GetSpans(), 4)
+
+	output = captureOutput(func() {
+		Executor("delete spans in trace t dry-run")
+	})
+	assert.Equal(t, "4 spans match spans in trace t (dry run, nothing is changed):\n  t/root\n  t/db_query\n  t/cache_get\n  t/db_insert\n", output)
+
+	output = captureOutput(func() {
+		Executor("delete spans *_* in trace t")
+	})
+	assert.Equal(t, "Deleted 2 spans: t/db_query, t/db_insert\n", output, "Selected descendants of deleted spans should be skipped")
+	assert.Len(t, telemetry.GetSpans(), 1)
+	assert.Contains(t, telemetry.GetSpans(), "t/root")
+
+	output = captureOutput(func() {
+		Executor("undo")
+	})
+	assert.Equal(t, "Undone: delete spans *_* in trace t\n", output)
+	assert.Len(t, telemetry.GetSpans(), 4)
+}
+
+func TestHandleAddEventAndLinkToSpans(t *testing.T) {
+	setupBulkSpans()
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{
+			input: "add event spans db_* retry",
+			want:  "Added event 'retry' to 2 spans: t/db_query, t/db_insert\n",
+		},
+		{
+			input: "add event spans db_* retry dry-run",
+			want:  "2 spans match spans db_* (dry run, nothing is changed):\n  t/db_query\n  t/db_insert\n",
+		},
+		{
+			input: "add event db_query retry dry-run",
+			want:  "Error validating add event command: dry-run can only be specified with spans\n",
+		},
+		{
+			input: "add link spans db_* root attributes kind=retry",
+			want:  "Added links from 2 spans to 'root': t/db_query, t/db_insert\n",
+		},
+		{
+			input: "add link spans db_* nope",
+			want:  "Error validating add link command: span 'nope' does not exist\n",
+		},
+		{
+			input: "add link spans db_*",
+			want:  "Error validating add link command: span to link to must be specified for add link command\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			output := captureOutput(func() {
+				Executor(tt.input)
+			})
+			assert.Equal(t, tt.want, output)
+		})
+	}
+
+	spans := telemetry.GetSpans()
+	assert.Len(t, spans["t/db_query"].Events, 1)
+	assert.Len(t, spans["t/db_insert"].Events, 1)
+	assert.Len(t, spans["t/db_insert"].Links, 1)
+	assert.Equal(t, map[string]string{"kind": "retry"}, spans["t/db_insert"].Links[0].Attributes)
+	assert.Empty(t, spans["t/cache_get"].Events)
+}
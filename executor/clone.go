@@ -0,0 +1,45 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ymtdzzz/otelgen/telemetry"
+)
+
+func handleCloneCommand(cmd *CloneCommand) bool {
+	if err := cmd.Validate(); err != nil {
+		fmt.Printf("Error validating clone command: %v\n", err)
+		return false
+	}
+	if err := runCloneCommand(cmd); err != nil {
+		fmt.Printf("Error %v\n", err)
+		return false
+	}
+	return true
+}
+
+// runCloneCommand runs the validated clone command and returns what failed
+func runCloneCommand(cmd *CloneCommand) error {
+	var (
+		count      = 1
+		parentName string
+	)
+	if cmd.Count != nil {
+		count = *cmd.Count
+	}
+	if cmd.Parent != nil {
+		parentName = *cmd.Parent
+	}
+
+	clones, err := telemetry.CloneSpan(*cmd.Name, *cmd.Prefix, count, parentName)
+	if err != nil {
+		return fmt.Errorf("cloning span: %w", err)
+	}
+	names := make([]string, 0, len(clones))
+	for _, clone := range clones {
+		names = append(names, clone.Name)
+	}
+	fmt.Printf("Cloned span %s %d time(s): %s\n", *cmd.Name, len(clones), strings.Join(names, ", "))
+	return nil
+}
@@ -0,0 +1,58 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/otelgen/telemetry"
+)
+
+func TestHandleClone(t *testing.T) {
+	tests := []struct {
+		input     string
+		want      string
+		wantSpans []string
+	}{
+		{
+			input:     "clone span db-query as db count 3",
+			want:      "Cloned span db-query 3 time(s): db_1, db_2, db_3\n",
+			wantSpans: []string{"db_1", "db_2", "db_3"},
+		},
+		{
+			input:     "clone span db-query as db under other-span",
+			want:      "Cloned span db-query 1 time(s): db_1\n",
+			wantSpans: []string{"db_1"},
+		},
+		{
+			input: "clone span db-query count 3",
+			want:  "Error validating clone command: prefix must be specified with 'as' for clone command\n",
+		},
+		{
+			input: "clone span db-query as db count 0",
+			want:  "Error validating clone command: count must be greater than 0\n",
+		},
+		{
+			input: "clone span root-span as root",
+			want:  "Error cloning span: span root-span is a root span, parent span must be specified\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			telemetry.InitStore()
+			telemetry.CreateTrace("my-trace")
+			telemetry.AddSpanToTrace("my-trace", "root-span", map[string]string{})
+			telemetry.AddSpanToSpan("root-span", "db-query", map[string]string{})
+			telemetry.AddSpanToSpan("root-span", "other-span", map[string]string{})
+
+			output := captureOutput(func() {
+				Executor(tt.input)
+			})
+
+			assert.Equal(t, tt.want, output)
+			for _, name := range tt.wantSpans {
+				assert.True(t, telemetry.IsSpanExists(name), "Span %s should be cloned", name)
+			}
+		})
+	}
+}
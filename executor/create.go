@@ -4,36 +4,50 @@ import (
 	"fmt"
 
 	"github.com/ymtdzzz/otelgen/telemetry"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func handleCreateCommand(cmd *CreateCommand) {
+func handleCreateCommand(cmd *CreateCommand) bool {
 	if err := cmd.Validate(); err != nil {
 		fmt.Printf("Error validating create command: %v\n", err)
-		return
+		return false
 	}
+	if err := runCreateCommand(cmd); err != nil {
+		fmt.Printf("Error %v\n", err)
+		return false
+	}
+	return true
+}
 
+// runCreateCommand runs the validated create command and returns what failed
+func runCreateCommand(cmd *CreateCommand) error {
+	var err error
 	switch *cmd.Type {
 	case "span":
-		if err := handleCreateSpan(cmd); err != nil {
-			fmt.Printf("Error creating span: %v\n", err)
-		}
+		err = handleCreateSpan(cmd)
 	case "resource":
-		if err := handleCreateResource(cmd); err != nil {
-			fmt.Printf("Error creating resource: %v\n", err)
-		}
+		err = handleCreateResource(cmd)
 	case "event":
-		if err := handleCreateEvent(cmd); err != nil {
-			fmt.Printf("Error creating event: %v\n", err)
-		}
+		err = handleCreateEvent(cmd)
 	default:
-		fmt.Printf("Unknown target type for create command: %s\n", *cmd.Type)
+		return fmt.Errorf("unknown target type for create command: %s", *cmd.Type)
+	}
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", *cmd.Type, err)
 	}
+	return nil
 }
 
 func handleCreateSpan(cmd *CreateCommand) error {
 	var (
+		name         string
 		resourceName string
 		attributes   map[string]string
+		duration     *string
+		kind         *string
+		convention   *telemetry.ConventionSpan
+		version      string
+		span         *telemetry.Span
 	)
 
 	for _, arg := range cmd.Args {
@@ -43,6 +57,32 @@ func handleCreateSpan(cmd *CreateCommand) error {
 		if len(arg.Attrs) > 0 {
 			attributes = convertKeyValuesToMap(arg.Attrs)
 		}
+		if arg.Duration != nil {
+			duration = arg.Duration
+		}
+		if arg.Kind != nil {
+			kind = arg.Kind
+		}
+	}
+
+	if cmd.Name != nil {
+		name = *cmd.Name
+	}
+	if arg := cmd.Convention(); arg != nil {
+		version = conventionSemconvVersion(cmd, resourceName)
+		c, err := applyConvention(arg, kind, version)
+		if err != nil {
+			return err
+		}
+		convention = c
+		if name == "" {
+			name = c.Name
+		}
+		// the attributes given explicitly win over the ones of the convention
+		for key, value := range attributes {
+			c.Attributes[key] = value
+		}
+		attributes = c.Attributes
 	}
 
 	if cmd.Trace != nil {
@@ -51,40 +91,124 @@ func handleCreateSpan(cmd *CreateCommand) error {
 			trace = telemetry.CreateTrace(*cmd.Trace)
 			fmt.Printf("Created trace: %s\n", trace.Name)
 		}
-		span, err := telemetry.AddSpanToTrace(*cmd.Trace, *cmd.Name, attributes)
+		created, err := telemetry.AddSpanToTrace(*cmd.Trace, name, attributes)
 		if err != nil {
 			return err
 		}
+		span = created
 		fmt.Printf("Created span: %s in trace: %s\n", span.Name, trace.Name)
 	} else if cmd.ParentSpan != nil {
-		span, err := telemetry.AddSpanToSpan(*cmd.ParentSpan, *cmd.Name, attributes)
+		created, err := telemetry.AddSpanToSpan(*cmd.ParentSpan, name, attributes)
 		if err != nil {
 			return err
 		}
+		span = created
 		fmt.Printf("Created span: %s with parent span: %s\n", span.Name, *cmd.ParentSpan)
 	}
+	// the created span is referred to by its handle as the name can be shared with other spans
 	if resourceName != "" {
-		resource, err := telemetry.SetResourceToSpan(*cmd.Name, resourceName)
+		resource, err := telemetry.SetResourceToSpan(span.Handle, resourceName)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Set resource %s to span %s\n", resource.Name, span.Name)
+	}
+	if duration != nil {
+		d, err := setSpanDuration(span.Handle, *duration)
 		if err != nil {
 			return err
 		}
-		fmt.Printf("Set resource %s to span %s\n", resource.Name, *cmd.Name)
+		fmt.Printf("Set duration %s to span %s\n", d, span.Name)
+	}
+	if convention != nil {
+		if _, err := telemetry.SetSpanKind(span.Handle, convention.Kind); err != nil {
+			return err
+		}
+		fmt.Printf("Applied %s conventions of semconv %s to span %s: kind %s, %d attributes\n",
+			cmd.Convention().Name, version, span.Name, convention.Kind, len(convention.Attributes))
+	} else if kind != nil {
+		if err := setSpanKind(span.Handle, *kind); err != nil {
+			return err
+		}
+		fmt.Printf("Set kind %s to span %s\n", *kind, span.Name)
 	}
 	return nil
 }
 
+// conventionSemconvVersion returns the semantic conventions version which the span created by
+// the command is exported with, which is the one of its resource or of the resource of its parent
+func conventionSemconvVersion(cmd *CreateCommand, resourceName string) string {
+	if resource, exists := telemetry.GetResources()[resourceName]; exists {
+		return resource.EffectiveSemconvVersion()
+	}
+	if cmd.ParentSpan != nil {
+		if parent, err := telemetry.LookupSpan(*cmd.ParentSpan); err == nil && parent.Resource != nil {
+			return parent.Resource.EffectiveSemconvVersion()
+		}
+	}
+	return telemetry.DefaultSemconvVersion
+}
+
+// applyConvention returns the span name, the kind and the attributes filled by the convention
+func applyConvention(arg *ConventionArg, kind *string, version string) (*telemetry.ConventionSpan, error) {
+	convention, err := telemetry.LookupSpanConvention(arg.Name)
+	if err != nil {
+		return nil, err
+	}
+	k := trace.SpanKindUnspecified
+	if kind != nil {
+		if k, err = telemetry.ParseSpanKind(*kind); err != nil {
+			return nil, err
+		}
+	}
+	return convention.Apply(convertKeyValuesToMap(arg.Params), k, version)
+}
+
+// setSpanKind parses the span kind and sets it to the span
+func setSpanKind(ref, kind string) error {
+	k, err := telemetry.ParseSpanKind(kind)
+	if err != nil {
+		return err
+	}
+	_, err = telemetry.SetSpanKind(ref, k)
+	return err
+}
+
+// setSpanDuration parses the duration distribution and sets it to the span
+func setSpanDuration(ref, duration string) (*telemetry.Distribution, error) {
+	d, err := telemetry.ParseDistribution(duration)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := telemetry.SetSpanDuration(ref, &d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
 func handleCreateResource(cmd *CreateCommand) error {
 	var (
-		attributes map[string]string
+		attributes     map[string]string
+		semconvVersion string
+		mergeDefaults  *bool
 	)
 
 	for _, arg := range cmd.Args {
 		if len(arg.Attrs) > 0 {
 			attributes = convertKeyValuesToMap(arg.Attrs)
 		}
+		if arg.Semconv != nil {
+			semconvVersion = *arg.Semconv
+		}
+		if arg.Defaults != nil {
+			mergeDefaults = arg.mergeDefaults()
+		}
 	}
 
 	resource := telemetry.CreateResource(*cmd.Name, attributes)
+	if _, err := telemetry.SetResourceSemantics(resource.Name, semconvVersion, mergeDefaults); err != nil {
+		return err
+	}
 	fmt.Printf("Created resource: %s with attributes: %v\n", resource.Name, attributes)
 	return nil
 }
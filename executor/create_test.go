@@ -3,9 +3,11 @@ package executor
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/ymtdzzz/otelgen/telemetry"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestHandleCreateSpan_Trace_OK(t *testing.T) {
@@ -22,7 +24,7 @@ func TestHandleCreateSpan_Trace_OK(t *testing.T) {
 	assert.True(t, exists, "Trace should exist after creation")
 	assert.Equal(t, "my-trace", trace.Name, "Trace name should match")
 
-	span, exists := telemetry.GetSpans()["my-span"]
+	span, exists := telemetry.GetSpans()["my-trace/my-span"]
 	assert.True(t, exists, "Span should exist after creation")
 	assert.Equal(t, "my-span", span.Name, "Span name should match")
 	assert.Equal(t, map[string]string{"key": "value", "http.method": "GET"}, span.Attributes, "Span attributes should match")
@@ -56,12 +58,12 @@ func TestHandleCreateSpan_ParentSpan_OK(t *testing.T) {
 
 	handleCreateCommand(cmd.Create)
 
-	span, exists := telemetry.GetSpans()["child-span"]
+	span, exists := telemetry.GetSpans()["my-trace/child-span"]
 	assert.True(t, exists, "Child span should exist after creation")
 	assert.Equal(t, "child-span", span.Name, "Child span name should match")
 	assert.Equal(t, map[string]string{"key": "value", "http.method": "GET"}, span.Attributes, "Child span attributes should match")
 
-	parentSpan, exists := telemetry.GetSpans()["parent-span"]
+	parentSpan, exists := telemetry.GetSpans()["my-trace/parent-span"]
 	assert.True(t, exists, "Parent span should exist")
 	assert.Equal(t, span, parentSpan.Children[0], "Child span should be added to parent span's children")
 }
@@ -108,3 +110,90 @@ func TestHandleCreateCommand_ValidateError(t *testing.T) {
 	assert.True(t, strings.Contains(output, "Error validating create command"))
 	assert.True(t, strings.Contains(output, "type and name must be specified"))
 }
+
+func TestHandleCreateSpan_DuplicateNames(t *testing.T) {
+	telemetry.InitStore()
+	telemetry.CreateTrace("checkout")
+	telemetry.CreateTrace("search")
+
+	captureOutput(func() {
+		Executor(`create span "GET /users" in trace checkout`)
+		Executor(`create span "GET /users" in trace search`)
+		Executor(`create span db with parent checkout/GET__users attributes db.statement="SELECT * FROM users"`)
+	})
+
+	spans := telemetry.GetSpans()
+	assert.Len(t, spans, 3)
+	assert.Equal(t, "GET /users", spans["checkout/GET__users"].Name)
+	assert.Equal(t, "GET /users", spans["search/GET__users"].Name)
+	assert.Equal(t, spans["checkout/db"], spans["checkout/GET__users"].Children[0])
+	assert.Equal(t, "SELECT * FROM users", spans["checkout/db"].Attributes["db.statement"])
+
+	output := captureOutput(func() {
+		Executor(`create span child with parent "GET /users"`)
+	})
+	assert.Equal(t, "Error validating create command: span name GET /users is ambiguous, use one of: checkout/GET__users, search/GET__users\n", output)
+}
+
+func TestHandleCreateSpan_Duration(t *testing.T) {
+	telemetry.InitStore()
+
+	output := captureOutput(func() {
+		Executor(`create span my-span in trace my-trace duration "normal(100ms, 20ms)"`)
+	})
+
+	assert.Equal(t, "Created trace: my-trace\nCreated span: my-span in trace: my-trace\nSet duration normal(100ms, 20ms) to span my-span\n", output)
+	span := telemetry.GetSpans()["my-trace/my-span"]
+	assert.Equal(t, &telemetry.Distribution{Kind: "normal", Params: []time.Duration{100 * time.Millisecond, 20 * time.Millisecond}}, span.Duration)
+}
+
+func TestHandleCreateSpan_Kind(t *testing.T) {
+	telemetry.InitStore()
+
+	output := captureOutput(func() {
+		Executor("create span my-span in trace my-trace kind server duration 100ms")
+		Executor("create span my-span in trace other-trace kind sideways")
+	})
+
+	assert.Equal(t, "Created trace: my-trace\nCreated span: my-span in trace: my-trace\nSet duration fixed(100ms) to span my-span\nSet kind server to span my-span\n"+
+		"Error validating create command: unknown span kind sideways (internal, server, client, producer or consumer)\n", output)
+	assert.Equal(t, trace.SpanKindServer, telemetry.GetSpans()["my-trace/my-span"].Kind)
+}
+
+func TestHandleCreateSpan_Convention(t *testing.T) {
+	telemetry.InitStore()
+	telemetry.CreateResource("legacy", map[string]string{})
+	_, _ = telemetry.SetResourceSemantics("legacy", "v1.20.0", nil)
+
+	output := captureOutput(func() {
+		Executor("create span in trace checkout as http.server method=GET route=/users status=200 attributes user.id=42")
+		Executor("create span query with parent checkout/GET__users resource legacy as db system=postgresql operation=SELECT table=users")
+		Executor("create span with parent checkout/GET__users as messaging.kafka topic=orders kind consumer")
+	})
+
+	assert.Equal(t, "Created trace: checkout\nCreated span: GET /users in trace: checkout\n"+
+		"Applied http.server conventions of semconv v1.26.0 to span GET /users: kind server, 4 attributes\n"+
+		"Created span: query with parent span: checkout/GET__users\nSet resource legacy to span query\n"+
+		"Applied db conventions of semconv v1.20.0 to span query: kind client, 3 attributes\n"+
+		"Created span: receive orders with parent span: checkout/GET__users\n"+
+		"Applied messaging.kafka conventions of semconv v1.26.0 to span receive orders: kind consumer, 4 attributes\n", output)
+
+	server := telemetry.GetSpans()["checkout/GET__users"]
+	assert.Equal(t, trace.SpanKindServer, server.Kind)
+	assert.Equal(t, map[string]string{
+		"http.request.method":       "GET",
+		"http.route":                "/users",
+		"http.response.status_code": "200",
+		"user.id":                   "42",
+	}, server.Attributes)
+
+	query := telemetry.GetSpans()["checkout/query"]
+	assert.Equal(t, trace.SpanKindClient, query.Kind)
+	assert.Equal(t, map[string]string{
+		"db.system":    "postgresql",
+		"db.operation": "SELECT",
+		"db.sql.table": "users",
+	}, query.Attributes)
+
+	assert.Equal(t, trace.SpanKindConsumer, telemetry.GetSpans()["checkout/receive_orders"].Kind)
+}
@@ -0,0 +1,87 @@
+package executor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ymtdzzz/otelgen/telemetry"
+)
+
+func handleDeleteCommand(cmd *DeleteCommand) bool {
+	if err := cmd.Validate(); err != nil {
+		fmt.Printf("Error validating delete command: %v\n", err)
+		return false
+	}
+	if err := runDeleteCommand(cmd); err != nil {
+		fmt.Printf("Error %v\n", err)
+		return false
+	}
+	return true
+}
+
+// runDeleteCommand runs the validated delete command and returns what failed
+func runDeleteCommand(cmd *DeleteCommand) error {
+	var (
+		result *telemetry.DeleteResult
+		err    error
+	)
+
+	switch *cmd.Type {
+	case "trace":
+		result, err = telemetry.DeleteTrace(*cmd.Name)
+	case "span":
+		result, err = telemetry.DeleteSpan(*cmd.Name)
+	case "resource":
+		result, err = telemetry.DeleteResource(*cmd.Name)
+	case "event":
+		result, err = telemetry.DeleteEvent(*cmd.Name)
+	case "link":
+		result, err = telemetry.DeleteLinks(cmd.LinkRef())
+	default:
+		return fmt.Errorf("unknown target type for delete command: %s", *cmd.Type)
+	}
+	if err != nil {
+		return fmt.Errorf("deleting %s: %w", *cmd.Type, err)
+	}
+
+	switch *cmd.Type {
+	case "trace":
+		fmt.Printf("Deleted trace: %s\n", *cmd.Name)
+		if len(result.Spans) > 0 {
+			fmt.Printf("  Removed spans: %s\n", joinSpanNames(result.Spans))
+		}
+	case "span":
+		fmt.Printf("Deleted span: %s\n", *cmd.Name)
+		if len(result.Spans) > 1 {
+			fmt.Printf("  Removed descendant spans: %s\n", joinSpanNames(result.Spans[1:]))
+		}
+	case "resource":
+		fmt.Printf("Deleted resource: %s\n", *cmd.Name)
+		if len(result.Affected) > 0 {
+			fmt.Printf("  Unbound from spans: %s\n", joinSpanNames(result.Affected))
+		}
+	case "event":
+		fmt.Printf("Deleted event: %s\n", *cmd.Name)
+		if len(result.Affected) > 0 {
+			fmt.Printf("  Removed from spans: %s\n", joinSpanNames(result.Affected))
+		}
+	case "link":
+		fmt.Printf("Deleted %s\n", describeLinks(cmd.LinkRef(), result.Links))
+		return nil
+	}
+	if result.Links > 0 {
+		fmt.Printf("  Removed links pointing at deleted spans: %d\n", result.Links)
+	}
+	return nil
+}
+
+// joinSpanNames returns the sorted names of the spans joined by comma
+func joinSpanNames(spans []*telemetry.Span) string {
+	names := make([]string, 0, len(spans))
+	for _, span := range spans {
+		names = append(names, span.Name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,118 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/otelgen/telemetry"
+)
+
+func setupDeleteStore() {
+	telemetry.InitStore()
+	telemetry.CreateTrace("my-trace")
+	telemetry.AddSpanToTrace("my-trace", "root-span", map[string]string{})
+	telemetry.AddSpanToSpan("root-span", "child-span", map[string]string{})
+	telemetry.AddSpanToSpan("child-span", "grandchild-span", map[string]string{})
+
+	telemetry.CreateTrace("another-trace")
+	telemetry.AddSpanToTrace("another-trace", "another-span", map[string]string{})
+	telemetry.AddLinkToSpan("another-span", "grandchild-span", map[string]string{})
+
+	telemetry.CreateResource("my-resource", map[string]string{})
+	telemetry.SetResourceToSpan("root-span", "my-resource")
+	telemetry.SetResourceToSpan("another-span", "my-resource")
+
+	telemetry.CreateEvent("my-event", map[string]string{})
+	telemetry.AddEventToSpan("child-span", "my-event")
+}
+
+func TestHandleDelete(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		check func(t *testing.T)
+	}{
+		{
+			input: "delete trace my-trace",
+			want: `Deleted trace: my-trace
+  Removed spans: child-span, grandchild-span, root-span
+  Removed links pointing at deleted spans: 1
+`,
+			check: func(t *testing.T) {
+				assert.False(t, telemetry.IsTraceExists("my-trace"))
+				assert.Len(t, telemetry.GetSpans(), 1)
+			},
+		},
+		{
+			input: "delete span child-span",
+			want: `Deleted span: child-span
+  Removed descendant spans: grandchild-span
+  Removed links pointing at deleted spans: 1
+`,
+			check: func(t *testing.T) {
+				assert.Empty(t, telemetry.GetSpans()["my-trace/root-span"].Children)
+				assert.Empty(t, telemetry.GetSpans()["another-trace/another-span"].Links)
+			},
+		},
+		{
+			input: "delete resource my-resource",
+			want: `Deleted resource: my-resource
+  Unbound from spans: another-span, root-span
+`,
+			check: func(t *testing.T) {
+				assert.Nil(t, telemetry.GetSpans()["my-trace/root-span"].Resource)
+			},
+		},
+		{
+			input: "delete event my-event",
+			want: `Deleted event: my-event
+  Removed from spans: child-span
+`,
+			check: func(t *testing.T) {
+				assert.Empty(t, telemetry.GetSpans()["my-trace/child-span"].Events)
+			},
+		},
+		{
+			input: "delete link another-span grandchild-span",
+			want:  "Deleted 1 link(s) from 'another-span' to 'grandchild-span'\n",
+			check: func(t *testing.T) {
+				assert.Empty(t, telemetry.GetSpans()["another-trace/another-span"].Links)
+			},
+		},
+		{
+			input: "delete link another-span 0",
+			want:  "Deleted link 0 of 'another-span'\n",
+			check: func(t *testing.T) {
+				assert.Empty(t, telemetry.GetSpans()["another-trace/another-span"].Links)
+			},
+		},
+		{
+			input: "delete link another-span 1",
+			want:  "Error deleting link: span another-span has no link at index 1\n",
+			check: func(t *testing.T) {},
+		},
+		{
+			input: "delete link root-span child-span",
+			want:  "Error deleting link: no link from span root-span to span child-span\n",
+			check: func(t *testing.T) {},
+		},
+		{
+			input: "delete span non-existing-span",
+			want:  "Error validating delete command: span 'non-existing-span' does not exist\n",
+			check: func(t *testing.T) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			setupDeleteStore()
+
+			output := captureOutput(func() {
+				Executor(tt.input)
+			})
+
+			assert.Equal(t, tt.want, output)
+			tt.check(t)
+		})
+	}
+}
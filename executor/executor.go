@@ -4,18 +4,34 @@ import (
 	"fmt"
 	"os"
 	"strings"
+
+	"github.com/ymtdzzz/otelgen/telemetry"
 )
 
 func Executor(input string) {
+	execute(input)
+}
+
+// execute runs the command and reports whether it succeeded. A command fails when it cannot be parsed or
+// validated, when running it fails, when the expectation of expect is not met, or when lint or send --lint
+// find errors
+func execute(input string) bool {
 	input = strings.TrimSpace(input)
 	if input == "" {
-		return
+		return true
+	}
+	if pendingTemplate != nil {
+		return defineTemplateLine(input)
+	}
+	input, complete := collectBlockLine(input)
+	if !complete {
+		return true
 	}
 
 	cmd, err := ParseCommand(input)
 	if err != nil {
 		fmt.Printf("Error parsing command: %v\n", err)
-		return
+		return false
 	}
 
 	switch {
@@ -23,18 +39,72 @@ func Executor(input string) {
 		fmt.Println("Bye!")
 		os.Exit(0)
 	case cmd.Create != nil:
-		handleCreateCommand(cmd.Create)
+		return track(input, func() bool { return handleCreateCommand(cmd.Create) })
+	case cmd.SetLink != nil:
+		return track(input, func() bool { return handleSetLinkCommand(cmd.SetLink) })
+	case cmd.SetSpans != nil:
+		return track(input, func() bool { return handleSetSpansCommand(cmd.SetSpans) })
 	case cmd.Set != nil:
-		handleSetCommand(cmd.Set)
+		return track(input, func() bool { return handleSetCommand(cmd.Set) })
 	case cmd.AddLink != nil:
-		handleAddLinkCommand(cmd.AddLink)
+		return track(input, func() bool { return handleAddLinkCommand(cmd.AddLink) })
 	case cmd.AddEvent != nil:
-		handleAddEventCommand(cmd.AddEvent)
+		return track(input, func() bool { return handleAddEventCommand(cmd.AddEvent) })
+	case cmd.DeleteSpans != nil:
+		return track(input, func() bool { return handleDeleteSpansCommand(cmd.DeleteSpans) })
+	case cmd.Delete != nil:
+		return track(input, func() bool { return handleDeleteCommand(cmd.Delete) })
+	case cmd.Move != nil:
+		return track(input, func() bool { return handleMoveCommand(cmd.Move) })
+	case cmd.Clone != nil:
+		return track(input, func() bool { return handleCloneCommand(cmd.Clone) })
 	case cmd.Send != nil:
-		handleSendCommand()
+		return handleSendCommand(cmd.Send)
 	case cmd.List != nil:
-		handleListCommand(cmd.List)
+		return handleListCommand(cmd.List)
+	case cmd.Option != nil:
+		return handleOptionCommand(cmd.Option)
+	case cmd.Undo != nil:
+		return handleUndoCommand()
+	case cmd.Redo != nil:
+		return handleRedoCommand()
+	case cmd.Expect != nil:
+		return handleExpectCommand(cmd.Expect)
+	case cmd.Received != nil:
+		return handleReceivedCommand(cmd.Received)
+	case cmd.Load != nil:
+		return track(input, func() bool { return handleLoadCommand(cmd.Load) })
+	case cmd.Save != nil:
+		return handleSaveCommand(cmd.Save)
+	case cmd.Generate != nil:
+		if cmd.Generate.Stream {
+			return handleGenerateCommand(cmd.Generate)
+		}
+		return track(input, func() bool { return handleGenerateCommand(cmd.Generate) })
+	case cmd.Let != nil:
+		return handleLetCommand(cmd.Let)
+	case cmd.Vars != nil:
+		handleVarsCommand()
+	case cmd.Define != nil:
+		return handleDefineCommand(cmd.Define)
+	case cmd.Instantiate != nil:
+		return track(input, func() bool { return handleInstantiateCommand(cmd.Instantiate) })
+	case cmd.TraceBlock != nil:
+		return track(input, func() bool { return handleTraceBlockCommand(cmd.TraceBlock) })
+	case cmd.Workspace != nil:
+		return handleWorkspaceCommand(cmd.Workspace)
+	case cmd.Lint != nil:
+		return handleLintCommand()
 	default:
 		fmt.Printf("Unknown command: %v\n", cmd)
+		return false
 	}
+	return true
+}
+
+// track runs the handler of a command changing the store as one undoable change and returns its result
+func track(input string, handle func() bool) bool {
+	ok := true
+	telemetry.Track(input, func() { ok = handle() })
+	return ok
 }
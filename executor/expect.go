@@ -0,0 +1,162 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ymtdzzz/otelgen/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// handleExpectCommand checks the expectation against the spans of the last send
+// and reports whether it is met
+func handleExpectCommand(cmd *ExpectCommand) bool {
+	if err := cmd.Validate(); err != nil {
+		fmt.Printf("Error validating expect command: %v\n", err)
+		return false
+	}
+
+	exporter := telemetry.GetMemoryExporter()
+	if exporter == nil {
+		fmt.Println("Error checking expectation: expect requires the memory exporter (start otelgen with --exporter memory)")
+		return false
+	}
+	result := telemetry.LastSendResult()
+	if result == nil {
+		fmt.Println("Error checking expectation: no traces have been sent yet")
+		return false
+	}
+
+	var err error
+	switch *cmd.Type {
+	case "trace":
+		err = expectTrace(cmd, result, exporter.Spans())
+	case "span":
+		err = expectSpan(cmd, result, exporter.Spans())
+	}
+	if err != nil {
+		fmt.Printf("Expectation failed: %s: %v\n", cmd, err)
+		return false
+	}
+	fmt.Printf("Expectation passed: %s\n", cmd)
+	return true
+}
+
+func expectTrace(cmd *ExpectCommand, result *telemetry.SendResult, spans []sdktrace.ReadOnlySpan) error {
+	for _, sent := range result.Traces {
+		if sent.Name != *cmd.Name {
+			continue
+		}
+		count := 0
+		for _, span := range spans {
+			if sent.TraceID.IsValid() && span.SpanContext().TraceID() == sent.TraceID {
+				count++
+			}
+		}
+		if count != *cmd.Spans {
+			return fmt.Errorf("got %d spans", count)
+		}
+		return nil
+	}
+	return fmt.Errorf("trace %s was not sent", *cmd.Name)
+}
+
+// expectSpan checks that every sent span matching the reference meets the expectation
+func expectSpan(cmd *ExpectCommand, result *telemetry.SendResult, spans []sdktrace.ReadOnlySpan) error {
+	matched := findSentSpans(*cmd.Name, result, spans)
+	if len(matched) == 0 {
+		return fmt.Errorf("span %s was not sent", *cmd.Name)
+	}
+
+	for _, span := range matched {
+		var err error
+		switch *cmd.Has {
+		case "attribute":
+			err = expectAttribute(span, *cmd.Key, cmd.Value)
+		case "event":
+			err = expectEvent(span, *cmd.Key)
+		case "parent":
+			err = expectParent(span, *cmd.Key, result, spans)
+		case "resource":
+			err = expectResource(span, *cmd.Key)
+		}
+		if err != nil {
+			if len(matched) > 1 {
+				return fmt.Errorf("%v (1 of %d spans named %s)", err, len(matched), span.Name())
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// findSentSpans returns the sent span with the handle, or the sent spans with the name
+func findSentSpans(ref string, result *telemetry.SendResult, spans []sdktrace.ReadOnlySpan) []sdktrace.ReadOnlySpan {
+	var matched []sdktrace.ReadOnlySpan
+	if sc, ok := result.SpanContexts[ref]; ok {
+		for _, span := range spans {
+			if span.SpanContext().SpanID() == sc.SpanID() {
+				matched = append(matched, span)
+			}
+		}
+		return matched
+	}
+	for _, span := range spans {
+		if span.Name() == ref {
+			matched = append(matched, span)
+		}
+	}
+	return matched
+}
+
+func expectAttribute(span sdktrace.ReadOnlySpan, key string, value *string) error {
+	for _, attr := range span.Attributes() {
+		if string(attr.Key) != key {
+			continue
+		}
+		if value != nil && attr.Value.Emit() != *value {
+			return fmt.Errorf("attribute %s is %s", key, attr.Value.Emit())
+		}
+		return nil
+	}
+	return fmt.Errorf("attribute %s not found", key)
+}
+
+func expectEvent(span sdktrace.ReadOnlySpan, name string) error {
+	for _, event := range span.Events() {
+		if event.Name == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("event %s not found", name)
+}
+
+func expectParent(span sdktrace.ReadOnlySpan, parentRef string, result *telemetry.SendResult, spans []sdktrace.ReadOnlySpan) error {
+	if !span.Parent().IsValid() {
+		return errors.New("span is a root span")
+	}
+	for _, parent := range findSentSpans(parentRef, result, spans) {
+		if parent.SpanContext().SpanID() == span.Parent().SpanID() {
+			return nil
+		}
+	}
+	for _, parent := range spans {
+		if parent.SpanContext().SpanID() == span.Parent().SpanID() {
+			return fmt.Errorf("parent is %s", parent.Name())
+		}
+	}
+	return fmt.Errorf("parent span %s not found", parentRef)
+}
+
+// expectResource checks the service.name of the resource, which is the resource name unless it is overridden
+func expectResource(span sdktrace.ReadOnlySpan, name string) error {
+	serviceName, ok := span.Resource().Set().Value(attribute.Key("service.name"))
+	if !ok {
+		return errors.New("span has no service.name")
+	}
+	if serviceName.Emit() != name {
+		return fmt.Errorf("resource is %s", serviceName.Emit())
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/otelgen/telemetry"
+)
+
+func setupExpectSend(t *testing.T) {
+	t.Helper()
+	assert.NoError(t, telemetry.InitTracerManager(telemetry.EnableMemoryExporter(), nil))
+	telemetry.InitStore()
+	captureOutput(func() {
+		Executor("create resource cart")
+		Executor("create event cache-miss")
+		Executor("create span checkout in trace t resource cart attributes http.status_code=500")
+		Executor("create span db with parent checkout attributes db.system=postgresql")
+		Executor("create span db with parent checkout")
+		Executor("add event checkout cache-miss")
+		Executor("send")
+	})
+}
+
+func TestHandleExpectCommand(t *testing.T) {
+	setupExpectSend(t)
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"expect trace t spans=3", "Expectation passed: trace t spans=3\n"},
+		{"expect trace t spans=5", "Expectation failed: trace t spans=5: got 3 spans\n"},
+		{"expect trace unknown spans=1", "Expectation failed: trace unknown spans=1: trace unknown was not sent\n"},
+		{"expect span checkout has attribute http.status_code=500", "Expectation passed: span checkout has attribute http.status_code=500\n"},
+		{"expect span checkout has attribute http.status_code=200", "Expectation failed: span checkout has attribute http.status_code=200: attribute http.status_code is 500\n"},
+		{"expect span checkout has attribute http.route", "Expectation failed: span checkout has attribute http.route: attribute http.route not found\n"},
+		{"expect span checkout has event cache-miss", "Expectation passed: span checkout has event cache-miss\n"},
+		{"expect span checkout has resource cart", "Expectation passed: span checkout has resource cart\n"},
+		{"expect span db has parent checkout", "Expectation passed: span db has parent checkout\n"},
+		{"expect span db has attribute db.system", "Expectation failed: span db has attribute db.system: attribute db.system not found (1 of 2 spans named db)\n"},
+		{"expect span t/db has attribute db.system=postgresql", "Expectation passed: span t/db has attribute db.system=postgresql\n"},
+		{"expect span checkout has parent db", "Expectation failed: span checkout has parent db: span is a root span\n"},
+		{"expect span unknown has event x", "Expectation failed: span unknown has event x: span unknown was not sent\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			output := captureOutput(func() {
+				Executor(tt.input)
+			})
+			assert.Equal(t, tt.want, output)
+		})
+	}
+}
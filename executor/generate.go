@@ -0,0 +1,63 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ymtdzzz/otelgen/generator"
+	"github.com/ymtdzzz/otelgen/telemetry"
+)
+
+func handleGenerateCommand(cmd *GenerateCommand) bool {
+	if err := cmd.Validate(); err != nil {
+		fmt.Printf("Error validating generate command: %v\n", err)
+		return false
+	}
+
+	topology, err := generator.LoadTopology(*cmd.Topology)
+	if err != nil {
+		fmt.Printf("Error loading topology: %v\n", err)
+		return false
+	}
+	// without a seed, the seed is shown so that the traces can be generated again
+	seed := time.Now().UnixNano()
+	if cmd.Seed != nil {
+		seed = *cmd.Seed
+	}
+	g, err := generator.New(topology, seed)
+	if err != nil {
+		fmt.Printf("Error loading topology: %v\n", err)
+		return false
+	}
+	count := 1
+	if cmd.Count != nil {
+		count = *cmd.Count
+	}
+
+	if cmd.Stream {
+		spans, err := g.Stream(context.Background(), telemetry.GetTracerManager(), count)
+		if err != nil {
+			fmt.Printf("Error sending generated traces: %v\n", err)
+			return false
+		}
+		fmt.Printf("Sent %d generated traces with %d spans (seed %d).\n", count, spans, seed)
+		return true
+	}
+
+	prefix := "generated"
+	if cmd.Prefix != nil {
+		prefix = *cmd.Prefix
+	}
+	traces, err := g.Generate(telemetry.DefaultStore(), prefix, count)
+	if err != nil {
+		fmt.Printf("Error generating traces: %v\n", err)
+		return false
+	}
+	if len(traces) == 1 {
+		fmt.Printf("Generated trace %s from %s (seed %d).\n", traces[0].Name, *cmd.Topology, seed)
+		return true
+	}
+	fmt.Printf("Generated %d traces %s to %s from %s (seed %d).\n", len(traces), traces[0].Name, traces[len(traces)-1].Name, *cmd.Topology, seed)
+	return true
+}
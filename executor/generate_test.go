@@ -0,0 +1,63 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/otelgen/telemetry"
+)
+
+const testTopology = `
+services:
+  - name: frontend
+    operations:
+      - name: GET /checkout
+        latency: 20ms
+        calls:
+          - service: cart
+            operation: GetCart
+            count: 1-3
+  - name: cart
+    operations:
+      - name: GetCart
+`
+
+func TestHandleGenerateCommand(t *testing.T) {
+	assert.NoError(t, telemetry.InitTracerManager(telemetry.EnableMemoryExporter(), nil))
+	telemetry.InitStore()
+	path := filepath.Join(t.TempDir(), "topology.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(testTopology), 0o644))
+
+	output := captureOutput(func() {
+		Executor("generate from \"" + path + "\" count 3 seed 42 as checkout")
+	})
+	assert.Equal(t, "Generated 3 traces checkout-1 to checkout-3 from "+path+" (seed 42).\n", output)
+	assert.Len(t, telemetry.GetTraces(), 3)
+	assert.Contains(t, telemetry.GetResources(), "cart")
+
+	output = captureOutput(func() {
+		Executor("generate from \"" + path + "\" seed 42")
+	})
+	assert.Equal(t, "Generated trace generated-1 from "+path+" (seed 42).\n", output)
+
+	output = captureOutput(func() {
+		Executor("undo")
+	})
+	assert.Equal(t, "Undone: generate from \""+path+"\" seed 42\n", output)
+	assert.Len(t, telemetry.GetTraces(), 3)
+
+	output = captureOutput(func() {
+		Executor("generate from \"" + path + "\" count 2 seed 1 stream")
+	})
+	spans := len(telemetry.GetMemoryExporter().Spans())
+	assert.Equal(t, fmt.Sprintf("Sent 2 generated traces with %d spans (seed 1).\n", spans), output)
+	assert.Len(t, telemetry.GetTraces(), 3, "Streamed traces should not be kept")
+
+	output = captureOutput(func() {
+		Executor("generate from missing.yaml")
+	})
+	assert.Equal(t, "Error loading topology: open missing.yaml: no such file or directory\n", output)
+}
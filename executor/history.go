@@ -0,0 +1,27 @@
+package executor
+
+import (
+	"fmt"
+
+	"github.com/ymtdzzz/otelgen/telemetry"
+)
+
+func handleUndoCommand() bool {
+	label, err := telemetry.Undo()
+	if err != nil {
+		fmt.Printf("Error undoing: %v\n", err)
+		return false
+	}
+	fmt.Printf("Undone: %s\n", label)
+	return true
+}
+
+func handleRedoCommand() bool {
+	label, err := telemetry.Redo()
+	if err != nil {
+		fmt.Printf("Error redoing: %v\n", err)
+		return false
+	}
+	fmt.Printf("Redone: %s\n", label)
+	return true
+}
@@ -0,0 +1,38 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/otelgen/telemetry"
+)
+
+func TestHandleUndoRedo(t *testing.T) {
+	telemetry.InitStore()
+	telemetry.CreateTrace("my-trace")
+	telemetry.AddSpanToTrace("my-trace", "my-span", map[string]string{"key": "value", "other": "value"})
+
+	output := captureOutput(func() {
+		Executor("set span my-span attributes key=new")
+		Executor("undo")
+	})
+	assert.Equal(t, "Updated span\nUndone: set span my-span attributes key=new\n", output)
+	assert.Equal(t, map[string]string{"key": "value", "other": "value"}, telemetry.GetSpans()["my-trace/my-span"].Attributes)
+
+	output = captureOutput(func() {
+		Executor("redo")
+	})
+	assert.Equal(t, "Redone: set span my-span attributes key=new\n", output)
+	assert.Equal(t, map[string]string{"key": "new"}, telemetry.GetSpans()["my-trace/my-span"].Attributes)
+
+	output = captureOutput(func() {
+		Executor("redo")
+		Executor("list traces")
+		Executor("set span unknown name x")
+		Executor("undo")
+		Executor("undo")
+	})
+	assert.Contains(t, output, "Error redoing: nothing to redo\n")
+	assert.Contains(t, output, "Undone: set span my-span attributes key=new\n")
+	assert.Contains(t, output, "Error undoing: nothing to undo\n", "Read-only and failed commands should not be recorded")
+}
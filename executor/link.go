@@ -0,0 +1,72 @@
+package executor
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ymtdzzz/otelgen/telemetry"
+)
+
+// describeLinks returns the description of the links selected by the reference for the output
+func describeLinks(ref telemetry.LinkRef, count int) string {
+	if ref.Index != nil {
+		return fmt.Sprintf("link %d of '%s'", *ref.Index, ref.From)
+	}
+	return fmt.Sprintf("%d link(s) from '%s' to '%s'", count, ref.From, ref.To)
+}
+
+func listLinks(spanRef *string) {
+	var spans []*telemetry.Span
+	if spanRef != nil {
+		span, err := telemetry.LookupSpan(*spanRef)
+		if err != nil {
+			fmt.Printf("Error listing links: %v\n", err)
+			return
+		}
+		if len(span.Links) > 0 {
+			spans = append(spans, span)
+		}
+	} else {
+		spans = telemetry.SpansWithLinks()
+	}
+
+	if len(spans) == 0 {
+		fmt.Println("No links available.")
+		return
+	}
+
+	count := 0
+	for _, span := range spans {
+		count += len(span.Links)
+	}
+	fmt.Printf("Available links: %d\n", count)
+	fmt.Println("----------------------------------------")
+
+	// Sort spans by handle for consistent output
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].Handle < spans[j].Handle
+	})
+
+	for _, span := range spans {
+		fmt.Printf("Span: %s\n", telemetry.SpanRef(span))
+		printLinks(span, "  ")
+		fmt.Println("----------------------------------------")
+	}
+}
+
+// printLinks prints the links of the span with their indexes which can be used
+// in set link and delete link commands
+func printLinks(span *telemetry.Span, indent string) {
+	for i, link := range span.Links {
+		fmt.Printf("%s[%d] -> %s\n", indent, i, telemetry.SpanRef(link.TargetSpan))
+		keys := make([]string, 0, len(link.Attributes))
+		for key := range link.Attributes {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			fmt.Printf("%s    %s: %s\n", indent, key, link.Attributes[key])
+		}
+	}
+}
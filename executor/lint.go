@@ -0,0 +1,29 @@
+package executor
+
+import (
+	"fmt"
+
+	"github.com/ymtdzzz/otelgen/telemetry"
+)
+
+// handleLintCommand prints the lint findings and reports whether none of them is an error
+func handleLintCommand() bool {
+	return reportLintFindings(telemetry.Lint())
+}
+
+// reportLintFindings prints the findings with a summary and reports whether none of them is an error
+func reportLintFindings(findings []telemetry.LintFinding) bool {
+	if len(findings) == 0 {
+		fmt.Println("Lint found no problems")
+		return true
+	}
+	errorCount := 0
+	for _, f := range findings {
+		fmt.Println(f)
+		if f.Severity == telemetry.SeverityError {
+			errorCount++
+		}
+	}
+	fmt.Printf("Lint found %d error(s) and %d warning(s)\n", errorCount, len(findings)-errorCount)
+	return errorCount == 0
+}
@@ -0,0 +1,72 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/otelgen/telemetry"
+)
+
+func TestHandleLintCommand(t *testing.T) {
+	telemetry.InitStore()
+	captureOutput(func() {
+		Executor("create resource api semconv v1.26.0")
+		Executor("create span checkout in trace t resource api attributes http.method=GET,http.response.status_code=OK")
+		Executor("create span cache with parent checkout attributes app.hit=true")
+	})
+
+	var ok bool
+	output := captureOutput(func() {
+		ok = execute("lint")
+	})
+
+	assert.False(t, ok)
+	assert.Equal(t, "warning: span 't/checkout': attribute http.method is deprecated in semconv v1.26.0, use http.request.method\n"+
+		"error: span 't/checkout': attribute http.response.status_code must be int but is 'OK'\n"+
+		"warning: span 't/cache': no resource, the span is sent without service.name (unknown_service)\n"+
+		"Lint found 1 error(s) and 2 warning(s)\n", output)
+
+	telemetry.InitStore()
+	captureOutput(func() {
+		Executor("create resource api")
+		Executor("create span in trace t resource api as http.server method=GET route=/users status=200")
+	})
+	output = captureOutput(func() {
+		ok = execute("lint")
+	})
+
+	assert.True(t, ok)
+	assert.Equal(t, "Lint found no problems\n", output)
+}
+
+func TestHandleSendCommand_Lint(t *testing.T) {
+	assert.NoError(t, telemetry.InitTracerManager(telemetry.EnableMemoryExporter(), nil))
+	telemetry.InitStore()
+	captureOutput(func() {
+		Executor("create span checkout in trace t attributes db.name=shop")
+	})
+
+	var ok bool
+	output := captureOutput(func() {
+		ok = execute("send --lint")
+	})
+
+	assert.False(t, ok)
+	assert.Equal(t, "warning: span 't/checkout': no resource, the span is sent without service.name (unknown_service)\n"+
+		"warning: span 't/checkout': attribute db.name is deprecated in semconv v1.26.0, use db.namespace\n"+
+		"error: span 't/checkout': missing required attribute db.system for the db attributes\n"+
+		"Lint found 1 error(s) and 2 warning(s)\n"+
+		"Nothing is sent, fix the lint errors first\n", output)
+	assert.Empty(t, telemetry.GetMemoryExporter().Spans())
+
+	captureOutput(func() {
+		Executor("set span checkout attributes db.system=postgresql,db.namespace=shop")
+	})
+	output = captureOutput(func() {
+		ok = execute("send --lint seed 42")
+	})
+
+	assert.True(t, ok)
+	assert.Contains(t, output, "Lint found 0 error(s) and 1 warning(s)\n")
+	assert.Len(t, telemetry.GetMemoryExporter().Spans(), 1)
+}
@@ -1,17 +1,24 @@
 package executor
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strings"
 
 	"github.com/ymtdzzz/otelgen/telemetry"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func handleListCommand(cmd *ListCommand) {
+func handleListCommand(cmd *ListCommand) bool {
+	if err := cmd.Validate(); err != nil {
+		fmt.Printf("Error validating list command: %v\n", err)
+		return false
+	}
+
 	if cmd.Type == nil {
 		fmt.Println("No target specified for list command.")
-		return
+		return false
 	}
 
 	switch *cmd.Type {
@@ -21,9 +28,13 @@ func handleListCommand(cmd *ListCommand) {
 		listResources()
 	case "events":
 		listEvents()
+	case "links":
+		listLinks(cmd.Span)
 	default:
 		fmt.Printf("Unknown target type for list command: %s\n", *cmd.Type)
+		return false
 	}
+	return true
 }
 
 func listTraces() {
@@ -36,21 +47,56 @@ func listTraces() {
 	fmt.Printf("Available traces: %d\n", len(traces))
 	fmt.Println("----------------------------------------")
 
-	for name, trace := range traces {
+	// Sort trace names for consistent output
+	names := make([]string, 0, len(traces))
+	for name := range traces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		trace := traces[name]
 		fmt.Printf("Trace: %s\n", name)
+		inherit := trace.ShouldInheritResource(telemetry.GetOptions())
+		if inherit {
+			fmt.Println("  Resource inheritance: on")
+		}
 		if trace.RootSpan == nil {
 			fmt.Println("  No spans in this trace")
 		} else {
-			printSpan(trace.RootSpan, 1)
+			printSpan(trace.RootSpan, 1, nil, inherit)
 		}
 		fmt.Println("----------------------------------------")
 	}
 }
 
-func printSpan(span *telemetry.Span, depth int) {
+// printSpan prints the span and its children. parentResource is the resolved resource
+// of the parent span, which is shown as inherited when inherit is true.
+func printSpan(span *telemetry.Span, depth int, parentResource *telemetry.Resource, inherit bool) {
 	indent := strings.Repeat("  ", depth)
 
-	fmt.Printf("%s- Span: %s\n", indent, span.Name)
+	if ref := telemetry.SpanRef(span); ref != span.Name {
+		fmt.Printf("%s- Span: %s (handle: %s)\n", indent, span.Name, ref)
+	} else {
+		fmt.Printf("%s- Span: %s\n", indent, span.Name)
+	}
+
+	if span.Kind != trace.SpanKindUnspecified {
+		fmt.Printf("%s  Kind: %s\n", indent, span.Kind)
+	}
+	if span.Timing != nil {
+		fmt.Printf("%s  Timing: offset %s, duration %s\n", indent, span.Timing.Offset, span.Timing.Duration)
+	}
+	if span.Duration != nil {
+		fmt.Printf("%s  Duration: %s\n", indent, span.Duration)
+	}
+	if span.Status != nil {
+		if span.Status.Description != "" {
+			fmt.Printf("%s  Status: %s (%s)\n", indent, span.Status.Code, span.Status.Description)
+		} else {
+			fmt.Printf("%s  Status: %s\n", indent, span.Status.Code)
+		}
+	}
 
 	if len(span.Attributes) > 0 {
 		fmt.Printf("%s  Attributes:\n", indent)
@@ -65,22 +111,39 @@ func printSpan(span *telemetry.Span, depth int) {
 		}
 	}
 
-	if span.Resource != nil {
-		fmt.Printf("%s  Resource:\n", indent)
-		fmt.Printf("%s    Name: %s\n", indent, span.Resource.Name)
-		keys := make([]string, 0, len(span.Resource.Attributes))
-		for key := range span.Resource.Attributes {
+	resource, inherited := telemetry.ResolveResource(span, parentResource, inherit)
+	if resource != nil {
+		if inherited {
+			fmt.Printf("%s  Resource (inherited):\n", indent)
+		} else {
+			fmt.Printf("%s  Resource:\n", indent)
+		}
+		fmt.Printf("%s    Name: %s\n", indent, resource.Name)
+		keys := make([]string, 0, len(resource.Attributes))
+		for key := range resource.Attributes {
 			keys = append(keys, key)
 		}
 		sort.Strings(keys)
 
 		for _, key := range keys {
-			fmt.Printf("%s    %s: %s\n", indent, key, span.Resource.Attributes[key])
+			fmt.Printf("%s    %s: %s\n", indent, key, resource.Attributes[key])
 		}
 	}
 
+	if len(span.Events) > 0 {
+		fmt.Printf("%s  Events:\n", indent)
+		for _, event := range span.Events {
+			fmt.Printf("%s    - %s\n", indent, event.Name)
+		}
+	}
+
+	if len(span.Links) > 0 {
+		fmt.Printf("%s  Links:\n", indent)
+		printLinks(span, indent+"    ")
+	}
+
 	for _, childSpan := range span.Children {
-		printSpan(childSpan, depth+1)
+		printSpan(childSpan, depth+1, resource, inherit)
 	}
 }
 
@@ -120,10 +183,27 @@ func listResources() {
 				fmt.Printf("    %s: %s\n", key, resource.Attributes[key])
 			}
 		}
+		printEffectiveResource(resource)
 		fmt.Println("----------------------------------------")
 	}
 }
 
+// printEffectiveResource prints the resource as it is attached to the exported spans
+func printEffectiveResource(resource *telemetry.Resource) {
+	fmt.Printf("  Semconv: %s, defaults: %s\n", resource.EffectiveSemconvVersion(), onOff(resource.MergeDefaults))
+
+	effective, err := resource.Build(context.Background())
+	if err != nil {
+		fmt.Printf("  Error building effective resource: %v\n", err)
+		return
+	}
+	fmt.Printf("  Effective resource (%s):\n", effective.SchemaURL())
+	// Attributes of the SDK resource are already sorted by key
+	for _, attr := range effective.Attributes() {
+		fmt.Printf("    %s: %s\n", attr.Key, attr.Value.Emit())
+	}
+}
+
 func listEvents() {
 	events := telemetry.GetEvents()
 	if len(events) == 0 {
@@ -102,6 +102,39 @@ Trace: test-trace
         environment: test
         service.name: resource-service
 ----------------------------------------
+`,
+		},
+		{
+			name:  "list traces with inherited resource",
+			input: "list traces",
+			setupFunc: func() {
+				telemetry.InitStore()
+
+				telemetry.CreateTrace("test-trace")
+				telemetry.AddSpanToTrace("test-trace", "root-span", map[string]string{})
+				telemetry.AddSpanToSpan("root-span", "child-span", map[string]string{})
+
+				telemetry.CreateResource("test-resource", map[string]string{
+					"environment": "test",
+				})
+				telemetry.SetResourceToSpan("root-span", "test-resource")
+
+				inherit := true
+				telemetry.SetTraceInheritResource("test-trace", &inherit)
+			},
+			want: `Available traces: 1
+----------------------------------------
+Trace: test-trace
+  Resource inheritance: on
+  - Span: root-span
+    Resource:
+      Name: test-resource
+      environment: test
+    - Span: child-span
+      Resource (inherited):
+        Name: test-resource
+        environment: test
+----------------------------------------
 `,
 		},
 		{
@@ -132,6 +165,56 @@ Trace: trace2
     Attributes:
       trace: 2
 ----------------------------------------
+`,
+		},
+		{
+			name:  "list traces with events and links",
+			input: "list traces",
+			setupFunc: func() {
+				telemetry.InitStore()
+
+				telemetry.CreateTrace("test-trace")
+				telemetry.AddSpanToTrace("test-trace", "root-span", map[string]string{})
+				telemetry.AddSpanToSpan("root-span", "child-span", map[string]string{})
+				telemetry.CreateEvent("retry", map[string]string{})
+				telemetry.AddEventToSpan("child-span", "retry")
+				telemetry.AddLinkToSpan("child-span", "root-span", map[string]string{"reason": "follows"})
+			},
+			want: `Available traces: 1
+----------------------------------------
+Trace: test-trace
+  - Span: root-span
+    - Span: child-span
+      Events:
+        - retry
+      Links:
+        [0] -> root-span
+            reason: follows
+----------------------------------------
+`,
+		},
+		{
+			name:  "list traces with duplicate span names",
+			input: "list traces",
+			setupFunc: func() {
+				telemetry.InitStore()
+
+				telemetry.CreateTrace("checkout")
+				telemetry.AddSpanToTrace("checkout", "GET /users", map[string]string{})
+				telemetry.AddSpanToSpan("checkout/GET__users", "db", map[string]string{})
+
+				telemetry.CreateTrace("search")
+				telemetry.AddSpanToTrace("search", "GET /users", map[string]string{})
+			},
+			want: `Available traces: 2
+----------------------------------------
+Trace: checkout
+  - Span: GET /users (handle: checkout/GET__users)
+    - Span: db
+----------------------------------------
+Trace: search
+  - Span: GET /users (handle: search/GET__users)
+----------------------------------------
 `,
 		},
 	}
@@ -149,6 +232,79 @@ Trace: trace2
 	}
 }
 
+func TestListLinks(t *testing.T) {
+	setup := func() {
+		telemetry.InitStore()
+		telemetry.CreateTrace("my-trace")
+		telemetry.AddSpanToTrace("my-trace", "root-span", map[string]string{})
+		telemetry.AddSpanToSpan("root-span", "child-span", map[string]string{})
+		telemetry.AddSpanToSpan("root-span", "other-span", map[string]string{})
+		telemetry.AddLinkToSpan("child-span", "root-span", map[string]string{"b": "2", "a": "1"})
+		telemetry.AddLinkToSpan("child-span", "other-span", map[string]string{})
+		telemetry.AddLinkToSpan("other-span", "child-span", map[string]string{})
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		setup func()
+		want  string
+	}{
+		{
+			name:  "no links",
+			input: "list links",
+			setup: telemetry.InitStore,
+			want:  "No links available.\n",
+		},
+		{
+			name:  "all links",
+			input: "list links",
+			setup: setup,
+			want: `Available links: 3
+----------------------------------------
+Span: child-span
+  [0] -> root-span
+      a: 1
+      b: 2
+  [1] -> other-span
+----------------------------------------
+Span: other-span
+  [0] -> child-span
+----------------------------------------
+`,
+		},
+		{
+			name:  "links of span",
+			input: "list links other-span",
+			setup: setup,
+			want: `Available links: 1
+----------------------------------------
+Span: other-span
+  [0] -> child-span
+----------------------------------------
+`,
+		},
+		{
+			name:  "span for other type",
+			input: "list traces other-span",
+			setup: setup,
+			want:  "Error validating list command: span can only be specified for list links command\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			output := captureOutput(func() {
+				Executor(tt.input)
+			})
+
+			assert.Equal(t, tt.want, output)
+		})
+	}
+}
+
 func TestListResources(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -175,6 +331,9 @@ func TestListResources(t *testing.T) {
 ----------------------------------------
 Resource: empty-resource
   No attributes
+  Semconv: v1.26.0, defaults: off
+  Effective resource (https://opentelemetry.io/schemas/1.26.0):
+    service.name: empty-resource
 ----------------------------------------
 `,
 		},
@@ -196,6 +355,11 @@ Resource: test-resource
     environment: test
     service.name: test-service
     version: 1.0.0
+  Semconv: v1.26.0, defaults: off
+  Effective resource (https://opentelemetry.io/schemas/1.26.0):
+    environment: test
+    service.name: test-service
+    version: 1.0.0
 ----------------------------------------
 `,
 		},
@@ -223,14 +387,25 @@ Resource: resource1
   Attributes:
     environment: prod
     service.name: service1
+  Semconv: v1.26.0, defaults: off
+  Effective resource (https://opentelemetry.io/schemas/1.26.0):
+    environment: prod
+    service.name: service1
 ----------------------------------------
 Resource: resource2
   Attributes:
     environment: staging
     service.name: service2
+  Semconv: v1.26.0, defaults: off
+  Effective resource (https://opentelemetry.io/schemas/1.26.0):
+    environment: staging
+    service.name: service2
 ----------------------------------------
 Resource: resource3
   No attributes
+  Semconv: v1.26.0, defaults: off
+  Effective resource (https://opentelemetry.io/schemas/1.26.0):
+    service.name: resource3
 ----------------------------------------
 `,
 		},
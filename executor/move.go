@@ -0,0 +1,48 @@
+package executor
+
+import (
+	"fmt"
+
+	"github.com/ymtdzzz/otelgen/telemetry"
+)
+
+func handleMoveCommand(cmd *MoveCommand) bool {
+	if err := cmd.Validate(); err != nil {
+		fmt.Printf("Error validating move command: %v\n", err)
+		return false
+	}
+	if err := runMoveCommand(cmd); err != nil {
+		fmt.Printf("Error %v\n", err)
+		return false
+	}
+	return true
+}
+
+// runMoveCommand runs the validated move command and returns what failed
+func runMoveCommand(cmd *MoveCommand) error {
+	if cmd.Parent != nil {
+		if _, err := telemetry.MoveSpanUnder(*cmd.Name, *cmd.Parent); err != nil {
+			return fmt.Errorf("moving span: %w", err)
+		}
+		fmt.Printf("Moved span: %s under parent span: %s\n", *cmd.Name, *cmd.Parent)
+		return nil
+	}
+
+	// the trace is created together with the move so that a rejected move leaves no empty trace
+	_, exists := telemetry.GetTraces()[*cmd.Trace]
+	err := telemetry.Atomically(func() error {
+		if !exists {
+			telemetry.CreateTrace(*cmd.Trace)
+		}
+		_, err := telemetry.MoveSpanToTrace(*cmd.Name, *cmd.Trace)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("moving span: %w", err)
+	}
+	if !exists {
+		fmt.Printf("Created trace: %s\n", *cmd.Trace)
+	}
+	fmt.Printf("Moved span: %s to trace: %s as root span\n", *cmd.Name, *cmd.Trace)
+	return nil
+}
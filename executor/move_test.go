@@ -0,0 +1,70 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/otelgen/telemetry"
+)
+
+func TestHandleMoveSpan_Under(t *testing.T) {
+	telemetry.InitStore()
+	telemetry.CreateTrace("my-trace")
+	telemetry.AddSpanToTrace("my-trace", "root-span", map[string]string{})
+	telemetry.AddSpanToSpan("root-span", "child-span", map[string]string{})
+	telemetry.AddSpanToSpan("root-span", "other-span", map[string]string{})
+
+	output := captureOutput(func() {
+		Executor("move span child-span under other-span")
+	})
+
+	assert.Equal(t, "Moved span: child-span under parent span: other-span\n", output)
+	spans := telemetry.GetSpans()
+	assert.Equal(t, []*telemetry.Span{spans["my-trace/other-span"]}, spans["my-trace/root-span"].Children)
+	assert.Equal(t, []*telemetry.Span{spans["my-trace/child-span"]}, spans["my-trace/other-span"].Children)
+}
+
+func TestHandleMoveSpan_Cycle(t *testing.T) {
+	telemetry.InitStore()
+	telemetry.CreateTrace("my-trace")
+	telemetry.AddSpanToTrace("my-trace", "root-span", map[string]string{})
+	telemetry.AddSpanToSpan("root-span", "child-span", map[string]string{})
+
+	output := captureOutput(func() {
+		Executor("move span root-span under child-span")
+	})
+
+	assert.Equal(t, "Error moving span: cannot move span root-span under itself or its descendant child-span\n", output)
+}
+
+func TestHandleMoveSpan_ToTrace(t *testing.T) {
+	telemetry.InitStore()
+	telemetry.CreateTrace("my-trace")
+	telemetry.AddSpanToTrace("my-trace", "root-span", map[string]string{})
+	telemetry.AddSpanToSpan("root-span", "child-span", map[string]string{})
+
+	output := captureOutput(func() {
+		Executor("move span child-span to trace new-trace")
+	})
+
+	assert.Equal(t, "Created trace: new-trace\nMoved span: child-span to trace: new-trace as root span\n", output)
+	assert.Equal(t, telemetry.GetSpans()["new-trace/child-span"], telemetry.GetTraces()["new-trace"].RootSpan)
+	assert.Empty(t, telemetry.GetSpans()["my-trace/root-span"].Children)
+}
+
+func TestRunMoveCommand_ToTraceRejected(t *testing.T) {
+	telemetry.InitStore()
+	telemetry.CreateTrace("my-trace")
+	telemetry.AddSpanToTrace("my-trace", "root-span", map[string]string{})
+
+	// the span is deleted after the command is validated, e.g. by an earlier command of a template
+	cmd, err := ParseCommand("move span missing-span to trace new-trace")
+	assert.NoError(t, err)
+	output := captureOutput(func() {
+		err = runMoveCommand(cmd.Move)
+	})
+
+	assert.EqualError(t, err, "moving span: span missing-span not found")
+	assert.Empty(t, output)
+	assert.NotContains(t, telemetry.GetTraces(), "new-trace", "A rejected move should not leave the new trace")
+}
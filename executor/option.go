@@ -0,0 +1,47 @@
+package executor
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ymtdzzz/otelgen/telemetry"
+)
+
+func handleOptionCommand(cmd *OptionCommand) bool {
+	if err := cmd.Validate(); err != nil {
+		fmt.Printf("Error validating option command: %v\n", err)
+		return false
+	}
+
+	if cmd.Key == nil {
+		printOptions()
+		return true
+	}
+
+	switch *cmd.Key {
+	case "inherit-resource":
+		telemetry.SetInheritResource(*cmd.Value == "on")
+	case "jitter":
+		jitter, _ := strconv.ParseFloat(*cmd.Value, 64)
+		if err := telemetry.SetJitter(jitter); err != nil {
+			fmt.Printf("Error setting option: %v\n", err)
+			return false
+		}
+	}
+	fmt.Printf("Set option %s to %s\n", *cmd.Key, *cmd.Value)
+	return true
+}
+
+func printOptions() {
+	options := telemetry.GetOptions()
+	fmt.Println("Options:")
+	fmt.Printf("  inherit-resource: %s\n", onOff(options.InheritResource))
+	fmt.Printf("  jitter: %s\n", strconv.FormatFloat(options.Jitter, 'f', -1, 64))
+}
+
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
@@ -0,0 +1,112 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/otelgen/telemetry"
+)
+
+func TestHandleOption(t *testing.T) {
+	t.Cleanup(func() {
+		telemetry.SetInheritResource(false)
+		assert.NoError(t, telemetry.SetJitter(0))
+	})
+
+	tests := []struct {
+		input       string
+		want        string
+		wantInherit bool
+	}{
+		{
+			input:       "option inherit-resource on",
+			want:        "Set option inherit-resource to on\n",
+			wantInherit: true,
+		},
+		{
+			input:       "option",
+			want:        "Options:\n  inherit-resource: on\n  jitter: 0\n",
+			wantInherit: true,
+		},
+		{
+			input:       "option inherit-resource off",
+			want:        "Set option inherit-resource to off\n",
+			wantInherit: false,
+		},
+		{
+			input:       "option inherit-resource",
+			want:        "Error validating option command: value must be specified for option 'inherit-resource'\n",
+			wantInherit: false,
+		},
+		{
+			input:       "option inherit-resource yes",
+			want:        "Error validating option command: value of option 'inherit-resource' must be on or off\n",
+			wantInherit: false,
+		},
+		{
+			input:       "option unknown on",
+			want:        "Error validating option command: unknown option: unknown\n",
+			wantInherit: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			output := captureOutput(func() {
+				Executor(tt.input)
+			})
+
+			assert.Equal(t, tt.want, output)
+			assert.Equal(t, tt.wantInherit, telemetry.GetOptions().InheritResource)
+		})
+	}
+}
+
+func TestHandleOption_Jitter(t *testing.T) {
+	t.Cleanup(func() {
+		assert.NoError(t, telemetry.SetJitter(0))
+	})
+
+	tests := []struct {
+		input      string
+		want       string
+		wantJitter float64
+	}{
+		{
+			input:      "option jitter 0.2",
+			want:       "Set option jitter to 0.2\n",
+			wantJitter: 0.2,
+		},
+		{
+			input:      "option",
+			want:       "Options:\n  inherit-resource: off\n  jitter: 0.2\n",
+			wantJitter: 0.2,
+		},
+		{
+			input:      "option jitter 1.5",
+			want:       "Error validating option command: value of option 'jitter' must be a number between 0 and 1\n",
+			wantJitter: 0.2,
+		},
+		{
+			input:      "option jitter",
+			want:       "Error validating option command: value must be specified for option 'jitter'\n",
+			wantJitter: 0.2,
+		},
+		{
+			input:      "option jitter 0",
+			want:       "Set option jitter to 0\n",
+			wantJitter: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			output := captureOutput(func() {
+				Executor(tt.input)
+			})
+
+			assert.Equal(t, tt.want, output)
+			assert.Equal(t, tt.wantJitter, telemetry.GetOptions().Jitter)
+		})
+	}
+}
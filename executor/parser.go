@@ -3,6 +3,9 @@ package executor
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/alecthomas/participle/v2"
 	"github.com/alecthomas/participle/v2/lexer"
@@ -10,13 +13,34 @@ import (
 )
 
 type Command struct {
-	Create   *CreateCommand   `parser:"@@"`
-	Set      *SetCommand      `parser:"| @@"`
-	AddLink  *AddLinkCommand  `parser:"| @@"`
-	AddEvent *AddEventCommand `parser:"| @@"`
-	List     *ListCommand     `parser:"| @@"`
-	Send     *SendCommand     `parser:"| @@"`
-	Exit     *ExitCommand     `parser:"| @@"`
+	Create      *CreateCommand      `parser:"@@"`
+	SetLink     *SetLinkCommand     `parser:"| @@"`
+	SetSpans    *SetSpansCommand    `parser:"| @@"`
+	Set         *SetCommand         `parser:"| @@"`
+	AddLink     *AddLinkCommand     `parser:"| @@"`
+	AddEvent    *AddEventCommand    `parser:"| @@"`
+	DeleteSpans *DeleteSpansCommand `parser:"| @@"`
+	Delete      *DeleteCommand      `parser:"| @@"`
+	Move        *MoveCommand        `parser:"| @@"`
+	Clone       *CloneCommand       `parser:"| @@"`
+	List        *ListCommand        `parser:"| @@"`
+	Send        *SendCommand        `parser:"| @@"`
+	Option      *OptionCommand      `parser:"| @@"`
+	Undo        *UndoCommand        `parser:"| @@"`
+	Redo        *RedoCommand        `parser:"| @@"`
+	Expect      *ExpectCommand      `parser:"| @@"`
+	Received    *ReceivedCommand    `parser:"| @@"`
+	Load        *LoadCommand        `parser:"| @@"`
+	Save        *SaveCommand        `parser:"| @@"`
+	Generate    *GenerateCommand    `parser:"| @@"`
+	Let         *LetCommand         `parser:"| @@"`
+	Vars        *VarsCommand        `parser:"| @@"`
+	Define      *DefineCommand      `parser:"| @@"`
+	Instantiate *InstantiateCommand `parser:"| @@"`
+	TraceBlock  *TraceBlockCommand  `parser:"| @@"`
+	Workspace   *WorkspaceCommand   `parser:"| @@"`
+	Lint        *LintCommand        `parser:"| @@"`
+	Exit        *ExitCommand        `parser:"| @@"`
 }
 
 type ExitCommand struct {
@@ -26,6 +50,39 @@ type ExitCommand struct {
 type CreateSetArg struct {
 	Resource *string     `parser:"('resource' @Ident)"`
 	Attrs    []*KeyValue `parser:"| ('attributes' @@ { ',' @@ } )"`
+	Semconv  *string     `parser:"| ('semconv' @Ident)"`
+	Defaults *string     `parser:"| ('defaults' @('on' | 'off'))"`
+	// Duration is a distribution of the span duration, e.g. 100ms or "normal(100ms, 20ms)"
+	Duration *string `parser:"| ('duration' @(String | Duration))"`
+	// Kind is the span kind, e.g. server
+	Kind *string `parser:"| ('kind' @Ident)"`
+	// Convention fills the span from a semantic convention, e.g. as http.server method=GET
+	Convention *ConventionArg `parser:"| ('as' @@)"`
+}
+
+// ConventionArg is a span convention with its parameters, e.g. http.server method=GET route=/users
+type ConventionArg struct {
+	Name   string      `parser:"@Ident"`
+	Params []*KeyValue `parser:"@@*"`
+}
+
+// Validate checks that the convention exists and the parameters fit it
+func (arg *ConventionArg) Validate() error {
+	convention, err := telemetry.LookupSpanConvention(arg.Name)
+	if err != nil {
+		return err
+	}
+	if err := validateKeyValues(arg.Params); err != nil {
+		return err
+	}
+	for i, kv := range arg.Params {
+		for _, prev := range arg.Params[:i] {
+			if prev.Key == kv.Key {
+				return fmt.Errorf("parameter %s of span convention %s is specified more than once", kv.Key, arg.Name)
+			}
+		}
+	}
+	return convention.ValidateParams(convertKeyValuesToMap(arg.Params))
 }
 
 func (arg *CreateSetArg) Validate(t string) error {
@@ -34,6 +91,9 @@ func (arg *CreateSetArg) Validate(t string) error {
 	if arg.Resource != nil {
 		resource = *arg.Resource
 	}
+	if err := validateKeyValues(arg.Attrs); err != nil {
+		return err
+	}
 
 	switch t {
 	case "span":
@@ -44,11 +104,59 @@ func (arg *CreateSetArg) Validate(t string) error {
 		if resource != "" {
 			return errors.New("resource cannot be specified when the type is resource")
 		}
+		if arg.Semconv != nil && !telemetry.IsSemconvVersionSupported(*arg.Semconv) {
+			return fmt.Errorf("semconv version '%s' is not supported (supported: %s)", *arg.Semconv, strings.Join(telemetry.SupportedSemconvVersions(), ", "))
+		}
+	}
+
+	if arg.Duration != nil {
+		if t != "span" {
+			return errors.New("duration can only be specified when the type is span")
+		}
+		if _, err := telemetry.ParseDistribution(*arg.Duration); err != nil {
+			return err
+		}
+	}
+
+	if arg.Kind != nil {
+		if t != "span" {
+			return errors.New("kind can only be specified when the type is span")
+		}
+		if _, err := telemetry.ParseSpanKind(*arg.Kind); err != nil {
+			return err
+		}
+	}
+
+	if arg.Convention != nil {
+		if t != "span" {
+			return errors.New("as can only be specified when the type is span")
+		}
+		if err := arg.Convention.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if t != "resource" {
+		if arg.Semconv != nil {
+			return errors.New("semconv can only be specified when the type is resource")
+		}
+		if arg.Defaults != nil {
+			return errors.New("defaults can only be specified when the type is resource")
+		}
 	}
 
 	return nil
 }
 
+// mergeDefaults returns the value of the defaults argument, or nil if it is not specified
+func (arg *CreateSetArg) mergeDefaults() *bool {
+	if arg.Defaults == nil {
+		return nil
+	}
+	merge := *arg.Defaults == "on"
+	return &merge
+}
+
 func (arg *CreateSetArg) addOps(ops []string) []string {
 	if arg.Resource != nil {
 		ops = append(ops, "resource")
@@ -56,20 +164,36 @@ func (arg *CreateSetArg) addOps(ops []string) []string {
 	if len(arg.Attrs) > 0 {
 		ops = append(ops, "attributes")
 	}
+	if arg.Semconv != nil {
+		ops = append(ops, "semconv")
+	}
+	if arg.Defaults != nil {
+		ops = append(ops, "defaults")
+	}
+	if arg.Duration != nil {
+		ops = append(ops, "duration")
+	}
+	if arg.Kind != nil {
+		ops = append(ops, "kind")
+	}
+	if arg.Convention != nil {
+		ops = append(ops, "as")
+	}
 	return ops
 }
 
 type CreateCommand struct {
-	Create     string          `parser:"'create'"`
-	Type       *string         `parser:"[ @('resource'| 'span' | 'event') ]"`
-	Name       *string         `parser:"[ @Ident ]"`
+	Create string  `parser:"'create'"`
+	Type   *string `parser:"[ @('resource'| 'span' | 'event') ]"`
+	// Name can be omitted for a span created as a convention, which names the span
+	Name       *string         `parser:"[ (?! 'in' | 'with' | 'as') @(Ident | String) ]"`
 	Trace      *string         `parser:"[ 'in' 'trace' @Ident ]"`
-	ParentSpan *string         `parser:"[ 'with' 'parent' @Ident ]"`
+	ParentSpan *string         `parser:"[ 'with' 'parent' @(Ident | String) ]"`
 	Args       []*CreateSetArg `parser:"@@*"`
 }
 
 func (c *CreateCommand) Validate() error {
-	if c.Type == nil || c.Name == nil {
+	if c.Type == nil || (c.Name == nil && c.Convention() == nil) {
 		return fmt.Errorf("type and name must be specified for create command")
 	}
 
@@ -89,8 +213,8 @@ func (c *CreateCommand) Validate() error {
 			return fmt.Errorf("span cannot have both a trace and a parent span")
 		}
 		if c.ParentSpan != nil {
-			if !telemetry.IsSpanExists(*c.ParentSpan) {
-				return fmt.Errorf("parent span '%s' does not exist", *c.ParentSpan)
+			if err := validateSpanRef(*c.ParentSpan, "parent span"); err != nil {
+				return err
 			}
 		}
 	}
@@ -101,6 +225,34 @@ func (c *CreateCommand) Validate() error {
 		}
 	}
 
+	if convention := c.Convention(); convention != nil && c.Kind() != nil {
+		sc, _ := telemetry.LookupSpanConvention(convention.Name)
+		kind, _ := telemetry.ParseSpanKind(*c.Kind())
+		if err := sc.ValidateKind(kind); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Convention returns the convention which the span is created as, or nil
+func (c *CreateCommand) Convention() *ConventionArg {
+	for _, arg := range c.Args {
+		if arg.Convention != nil {
+			return arg.Convention
+		}
+	}
+	return nil
+}
+
+// Kind returns the kind argument, or nil
+func (c *CreateCommand) Kind() *string {
+	for _, arg := range c.Args {
+		if arg.Kind != nil {
+			return arg.Kind
+		}
+	}
 	return nil
 }
 
@@ -122,37 +274,138 @@ func (c *CreateCommand) HasArgResource() bool {
 	return false
 }
 
+func (c *CreateCommand) HasArgDuration() bool {
+	for _, arg := range c.Args {
+		if arg.Duration != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *CreateCommand) HasArgKind() bool {
+	for _, arg := range c.Args {
+		if arg.Kind != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *CreateCommand) HasArgSemconv() bool {
+	for _, arg := range c.Args {
+		if arg.Semconv != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *CreateCommand) HasArgDefaults() bool {
+	for _, arg := range c.Args {
+		if arg.Defaults != nil {
+			return true
+		}
+	}
+	return false
+}
+
 type SetOnlyArg struct {
-	Name *string `parser:"('name' @Ident)"`
+	Name            *string `parser:"('name' @(Ident | String))"`
+	InheritResource *string `parser:"| ('inherit-resource' @('on' | 'off' | 'default'))"`
+}
+
+func (arg *SetOnlyArg) Validate(t string) error {
+	if arg.Name != nil && t == "trace" {
+		return errors.New("name cannot be specified when the type is trace")
+	}
+
+	if arg.InheritResource != nil && t != "trace" {
+		return errors.New("inherit-resource can only be specified when the type is trace")
+	}
+
+	return nil
+}
+
+// inheritResource returns the value of the inherit-resource argument.
+// nil is returned when the argument is 'default' so that the global option is used.
+func (arg *SetOnlyArg) inheritResource() *bool {
+	if arg.InheritResource == nil || *arg.InheritResource == "default" {
+		return nil
+	}
+	inherit := *arg.InheritResource == "on"
+	return &inherit
 }
 
-func (arg *SetOnlyArg) Validate() error {
-	var name string
+// PatchAttrsArg updates attributes in place instead of replacing all of them
+type PatchAttrsArg struct {
+	AddAttrs    []*KeyValue `parser:"('add-attributes' @@ { ',' @@ } )"`
+	RemoveAttrs []string    `parser:"| ('remove-attributes' @(Ident | String) { ',' @(Ident | String) } )"`
+}
 
-	if arg.Name != nil {
-		name = *arg.Name
+func (arg *PatchAttrsArg) addOps(ops []string) []string {
+	if len(arg.AddAttrs) > 0 {
+		ops = append(ops, "add-attributes")
+	}
+	if len(arg.RemoveAttrs) > 0 {
+		ops = append(ops, "remove-attributes")
 	}
+	return ops
+}
 
-	if name != "" {
-		if _, exists := telemetry.GetSpans()[name]; exists {
-			return fmt.Errorf("span with name %s already exists", name)
+// collectPatchAttrs returns the attributes to add and the keys to remove specified by the args
+func collectPatchAttrs(args []*PatchAttrsArg) (map[string]string, []string) {
+	var (
+		add    map[string]string
+		remove []string
+	)
+	for _, arg := range args {
+		if arg == nil {
+			continue
+		}
+		if len(arg.AddAttrs) > 0 {
+			add = convertKeyValuesToMap(arg.AddAttrs)
 		}
+		remove = append(remove, arg.RemoveAttrs...)
 	}
+	return add, remove
+}
 
+// validatePatchAttrs checks that replacing and patching attributes are not combined
+// and that no attribute is added and removed at the same time
+func validatePatchAttrs(ops []string, args []*PatchAttrsArg) error {
+	if slices.Contains(ops, "attributes") && (slices.Contains(ops, "add-attributes") || slices.Contains(ops, "remove-attributes")) {
+		return errors.New("attributes cannot be combined with add-attributes or remove-attributes")
+	}
+	for _, arg := range args {
+		if err := validateKeyValues(arg.AddAttrs); err != nil {
+			return err
+		}
+	}
+	add, remove := collectPatchAttrs(args)
+	for _, key := range remove {
+		if _, ok := add[key]; ok {
+			return fmt.Errorf("attribute '%s' cannot be both added and removed", key)
+		}
+	}
 	return nil
 }
 
 type SetArg struct {
-	SetCreateArg *CreateSetArg `parser:"@@"`
-	SetOnlyArg   *SetOnlyArg   `parser:"| @@"`
+	SetCreateArg *CreateSetArg  `parser:"@@"`
+	SetOnlyArg   *SetOnlyArg    `parser:"| @@"`
+	PatchAttrs   *PatchAttrsArg `parser:"| @@"`
 }
 
 func (arg *SetArg) Validate(t string) error {
 	if arg.SetCreateArg != nil {
+		if arg.SetCreateArg.Convention != nil {
+			return errors.New("as can only be specified when a span is created")
+		}
 		return arg.SetCreateArg.Validate(t)
 	}
 	if arg.SetOnlyArg != nil {
-		return arg.SetOnlyArg.Validate()
+		return arg.SetOnlyArg.Validate(t)
 	}
 	return nil
 }
@@ -164,13 +417,19 @@ func (arg *SetArg) addOps(ops []string) []string {
 	if arg.SetOnlyArg != nil && arg.SetOnlyArg.Name != nil {
 		ops = append(ops, "name")
 	}
+	if arg.SetOnlyArg != nil && arg.SetOnlyArg.InheritResource != nil {
+		ops = append(ops, "inherit-resource")
+	}
+	if arg.PatchAttrs != nil {
+		ops = arg.PatchAttrs.addOps(ops)
+	}
 	return ops
 }
 
 type SetCommand struct {
 	Set  string    `parser:"'set'"`
-	Type *string   `parser:"[ @('resource' | 'span' | 'event') ]"`
-	Name *string   `parser:"[ @Ident ]"`
+	Type *string   `parser:"[ @('resource' | 'span' | 'event' | 'trace') ]"`
+	Name *string   `parser:"[ @(Ident | String) ]"`
 	Args []*SetArg `parser:"@@*"`
 }
 
@@ -185,8 +444,8 @@ func (s *SetCommand) Validate() error {
 
 	switch *s.Type {
 	case "span":
-		if _, exists := telemetry.GetSpans()[*s.Name]; !exists {
-			return fmt.Errorf("span '%s' does not exist", *s.Name)
+		if err := validateSpanRef(*s.Name, "span"); err != nil {
+			return err
 		}
 	case "resource":
 		if _, exists := telemetry.GetResources()[*s.Name]; !exists {
@@ -196,18 +455,35 @@ func (s *SetCommand) Validate() error {
 		if _, exists := telemetry.GetEvents()[*s.Name]; !exists {
 			return fmt.Errorf("event '%s' does not exist", *s.Name)
 		}
+	case "trace":
+		if !telemetry.IsTraceExists(*s.Name) {
+			return fmt.Errorf("trace '%s' does not exist", *s.Name)
+		}
+		for _, arg := range s.Args {
+			if arg.SetCreateArg != nil || arg.PatchAttrs != nil {
+				return errors.New("only inherit-resource can be specified when the type is trace")
+			}
+		}
 	}
 
+	return validateSetArgs(s.Args, *s.Type)
+}
+
+// validateSetArgs checks the arguments of a set command for the type
+func validateSetArgs(args []*SetArg, t string) error {
 	var ops []string
-	for _, arg := range s.Args {
+	for _, arg := range args {
 		ops = arg.addOps(ops)
 	}
 	if err := checkDuplicateOps(ops); err != nil {
 		return err
 	}
+	if err := validatePatchAttrs(ops, patchAttrsArgs(args)); err != nil {
+		return err
+	}
 
-	for _, arg := range s.Args {
-		if err := arg.Validate(*s.Type); err != nil {
+	for _, arg := range args {
+		if err := arg.Validate(t); err != nil {
 			return err
 		}
 	}
@@ -215,6 +491,39 @@ func (s *SetCommand) Validate() error {
 	return nil
 }
 
+func patchAttrsArgs(setArgs []*SetArg) []*PatchAttrsArg {
+	var args []*PatchAttrsArg
+	for _, arg := range setArgs {
+		if arg.PatchAttrs != nil {
+			args = append(args, arg.PatchAttrs)
+		}
+	}
+	return args
+}
+
+// PatchAttrs returns the attributes to add and the keys to remove
+func (s *SetCommand) PatchAttrs() (map[string]string, []string) {
+	return collectPatchAttrs(patchAttrsArgs(s.Args))
+}
+
+func (s *SetCommand) HasArgAddAttrs() bool {
+	for _, arg := range s.Args {
+		if arg.PatchAttrs != nil && len(arg.PatchAttrs.AddAttrs) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *SetCommand) HasArgRemoveAttrs() bool {
+	for _, arg := range s.Args {
+		if arg.PatchAttrs != nil && len(arg.PatchAttrs.RemoveAttrs) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *SetCommand) HasArgName() bool {
 	for _, arg := range s.Args {
 		if arg.SetOnlyArg != nil && arg.SetOnlyArg.Name != nil {
@@ -224,6 +533,15 @@ func (s *SetCommand) HasArgName() bool {
 	return false
 }
 
+func (s *SetCommand) HasArgInheritResource() bool {
+	for _, arg := range s.Args {
+		if arg.SetOnlyArg != nil && arg.SetOnlyArg.InheritResource != nil {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *SetCommand) HasArgResource() bool {
 	for _, arg := range s.Args {
 		if arg.SetCreateArg != nil && arg.SetCreateArg.Resource != nil {
@@ -233,6 +551,24 @@ func (s *SetCommand) HasArgResource() bool {
 	return false
 }
 
+func (s *SetCommand) HasArgDuration() bool {
+	for _, arg := range s.Args {
+		if arg.SetCreateArg != nil && arg.SetCreateArg.Duration != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *SetCommand) HasArgKind() bool {
+	for _, arg := range s.Args {
+		if arg.SetCreateArg != nil && arg.SetCreateArg.Kind != nil {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *SetCommand) HasArgAttrs() bool {
 	for _, arg := range s.Args {
 		if arg.SetCreateArg != nil && len(arg.SetCreateArg.Attrs) > 0 {
@@ -242,6 +578,24 @@ func (s *SetCommand) HasArgAttrs() bool {
 	return false
 }
 
+func (s *SetCommand) HasArgSemconv() bool {
+	for _, arg := range s.Args {
+		if arg.SetCreateArg != nil && arg.SetCreateArg.Semconv != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *SetCommand) HasArgDefaults() bool {
+	for _, arg := range s.Args {
+		if arg.SetCreateArg != nil && arg.SetCreateArg.Defaults != nil {
+			return true
+		}
+	}
+	return false
+}
+
 type AddLinkArg struct {
 	Attrs []*KeyValue `parser:"('attributes' @@ { ',' @@ } )"`
 }
@@ -253,33 +607,52 @@ func (arg *AddLinkArg) addOps(ops []string) []string {
 	return ops
 }
 
+// AddLinkCommand adds a link from the span, or from all the selected spans, e.g.
+// add link spans worker_* enqueue
 type AddLinkCommand struct {
-	Add  string        `parser:"'add'"`
-	Link string        `parser:"'link'"`
-	From *string       `parser:"[ @Ident ]"`
-	To   *string       `parser:"[ @Ident ]"`
-	Args []*AddLinkArg `parser:"@@*"`
+	Add    string        `parser:"'add'"`
+	Link   string        `parser:"'link'"`
+	Spans  *SpanSelector `parser:"[ @@"`
+	From   *string       `parser:"| @(Ident | String) ]"`
+	To     *string       `parser:"[ @(Ident | String) ]"`
+	Args   []*AddLinkArg `parser:"@@*"`
+	DryRun bool          `parser:"[ @'dry-run' ]"`
 }
 
 func (c *AddLinkCommand) Validate() error {
-	if c.From == nil || c.To == nil {
+	if c.Spans != nil {
+		if err := c.Spans.Validate(); err != nil {
+			return err
+		}
+		if c.To == nil {
+			return errors.New("span to link to must be specified for add link command")
+		}
+	} else if c.From == nil || c.To == nil {
 		return fmt.Errorf("both 'from' and 'to' must be specified for add link command")
 	}
+	if c.DryRun && c.Spans == nil {
+		return errors.New("dry-run can only be specified with spans")
+	}
 
 	var ops []string
 	for _, arg := range c.Args {
 		ops = arg.addOps(ops)
+		if err := validateKeyValues(arg.Attrs); err != nil {
+			return err
+		}
 	}
 	if err := checkDuplicateOps(ops); err != nil {
 		return err
 	}
 
-	if !telemetry.IsSpanExists(*c.From) {
-		return fmt.Errorf("span '%s' does not exist", *c.From)
+	if c.From != nil {
+		if err := validateSpanRef(*c.From, "span"); err != nil {
+			return err
+		}
 	}
 
-	if !telemetry.IsSpanExists(*c.To) {
-		return fmt.Errorf("span '%s' does not exist", *c.To)
+	if err := validateSpanRef(*c.To, "span"); err != nil {
+		return err
 	}
 
 	return nil
@@ -294,71 +667,932 @@ func (c *AddLinkCommand) HasArgAttrs() bool {
 	return false
 }
 
-type AddEventCommand struct {
-	Add       string  `parser:"'add'"`
-	Event     string  `parser:"'event'"`
-	SpanName  *string `parser:"[ @Ident ]"`
-	EventName *string `parser:"[ @Ident ]"`
+type SetLinkArg struct {
+	Attrs      []*KeyValue    `parser:"('attributes' @@ { ',' @@ } )"`
+	PatchAttrs *PatchAttrsArg `parser:"| @@"`
 }
 
-func (c *AddEventCommand) Validate() error {
-	if c.EventName == nil {
-		return fmt.Errorf("event name must be specified for add event command")
+func (arg *SetLinkArg) addOps(ops []string) []string {
+	if len(arg.Attrs) > 0 {
+		ops = append(ops, "attributes")
 	}
+	if arg.PatchAttrs != nil {
+		ops = arg.PatchAttrs.addOps(ops)
+	}
+	return ops
+}
+
+type SetLinkCommand struct {
+	Set   string        `parser:"'set'"`
+	Link  string        `parser:"'link'"`
+	From  *string       `parser:"[ @(Ident | String) ]"`
+	To    *string       `parser:"[ @(Ident | String) ]"`
+	Index *int          `parser:"[ @Number ]"`
+	Args  []*SetLinkArg `parser:"@@*"`
+}
 
-	if !telemetry.IsSpanExists(*c.SpanName) {
-		return fmt.Errorf("span '%s' does not exist", *c.SpanName)
+func (c *SetLinkCommand) Validate() error {
+	if err := validateLinkRef(c.From, c.To, c.Index, "set link"); err != nil {
+		return err
 	}
 
-	if !telemetry.IsEventExists(*c.EventName) {
-		return fmt.Errorf("event '%s' does not exist", *c.EventName)
+	if len(c.Args) == 0 {
+		return fmt.Errorf("operation (attributes, add-attributes or remove-attributes) must be specified for set link command")
+	}
+
+	var ops []string
+	for _, arg := range c.Args {
+		ops = arg.addOps(ops)
+		if err := validateKeyValues(arg.Attrs); err != nil {
+			return err
+		}
+	}
+	if err := checkDuplicateOps(ops); err != nil {
+		return err
+	}
+	if err := validatePatchAttrs(ops, c.patchAttrsArgs()); err != nil {
+		return err
 	}
 
 	return nil
 }
 
-type ListCommand struct {
-	List string  `parser:"'list'"`
-	Type *string `parser:"[ @('traces' | 'resources' | 'events') ]"`
+// LinkRef returns the links selected by the command
+func (c *SetLinkCommand) LinkRef() telemetry.LinkRef {
+	return newLinkRef(c.From, c.To, c.Index)
 }
 
-type SendCommand struct {
-	Send string `parser:"'send'"`
+func (c *SetLinkCommand) patchAttrsArgs() []*PatchAttrsArg {
+	var args []*PatchAttrsArg
+	for _, arg := range c.Args {
+		if arg.PatchAttrs != nil {
+			args = append(args, arg.PatchAttrs)
+		}
+	}
+	return args
 }
 
-type KeyValue struct {
-	Key   string `parser:"@Ident '='"`
-	Value string `parser:"@Ident"`
+// PatchAttrs returns the attributes to add and the keys to remove
+func (c *SetLinkCommand) PatchAttrs() (map[string]string, []string) {
+	return collectPatchAttrs(c.patchAttrsArgs())
 }
 
-func convertKeyValuesToMap(attrs []*KeyValue) map[string]string {
-	attrsMap := make(map[string]string)
-	for _, kv := range attrs {
-		if kv != nil {
-			attrsMap[kv.Key] = kv.Value
+func (c *SetLinkCommand) HasArgAttrs() bool {
+	for _, arg := range c.Args {
+		if len(arg.Attrs) > 0 {
+			return true
 		}
 	}
-	return attrsMap
+	return false
 }
 
-var (
-	commandLexer = lexer.MustSimple([]lexer.SimpleRule{
-		{Name: "Comment", Pattern: `#[^\n]*`},
-		{Name: "Whitespace", Pattern: `\s+`},
-		{Name: "String", Pattern: `"[^"]*"|'[^']*'`},
-		{Name: "Number", Pattern: `[-+]?\d+(\.\d+)?`},
-		{Name: "Ident", Pattern: `[a-zA-Z_][a-zA-Z0-9_\.\-]*`},
-		{Name: "Punct", Pattern: `[,=]`},
-	})
+func (c *SetLinkCommand) HasArgAddAttrs() bool {
+	for _, arg := range c.Args {
+		if arg.PatchAttrs != nil && len(arg.PatchAttrs.AddAttrs) > 0 {
+			return true
+		}
+	}
+	return false
+}
 
-	parser = participle.MustBuild[Command](
-		participle.Lexer(commandLexer),
-		participle.Elide("Comment", "Whitespace"),
-	)
-)
+func (c *SetLinkCommand) HasArgRemoveAttrs() bool {
+	for _, arg := range c.Args {
+		if arg.PatchAttrs != nil && len(arg.PatchAttrs.RemoveAttrs) > 0 {
+			return true
+		}
+	}
+	return false
+}
 
-func ParseCommand(input string) (*Command, error) {
-	return parser.ParseString("", input)
+// AddEventCommand adds the event to the span, or to all the selected spans, e.g.
+// add event spans db_* retry
+type AddEventCommand struct {
+	Add       string        `parser:"'add'"`
+	Event     string        `parser:"'event'"`
+	Spans     *SpanSelector `parser:"[ @@"`
+	SpanName  *string       `parser:"| @(Ident | String) ]"`
+	EventName *string       `parser:"[ @Ident ]"`
+	DryRun    bool          `parser:"[ @'dry-run' ]"`
+}
+
+func (c *AddEventCommand) Validate() error {
+	if c.EventName == nil {
+		return fmt.Errorf("event name must be specified for add event command")
+	}
+
+	if c.Spans != nil {
+		if err := c.Spans.Validate(); err != nil {
+			return err
+		}
+	} else {
+		if c.DryRun {
+			return errors.New("dry-run can only be specified with spans")
+		}
+		if err := validateSpanRef(*c.SpanName, "span"); err != nil {
+			return err
+		}
+	}
+
+	if !telemetry.IsEventExists(*c.EventName) {
+		return fmt.Errorf("event '%s' does not exist", *c.EventName)
+	}
+
+	return nil
+}
+
+type DeleteCommand struct {
+	Delete string  `parser:"'delete'"`
+	Type   *string `parser:"[ @('trace' | 'span' | 'resource' | 'event' | 'link') ]"`
+	Name   *string `parser:"[ @(Ident | String) ]"`
+	To     *string `parser:"[ @(Ident | String) ]"`
+	Index  *int    `parser:"[ @Number ]"`
+}
+
+func (c *DeleteCommand) Validate() error {
+	if c.Type == nil || c.Name == nil {
+		return fmt.Errorf("type and name must be specified for delete command")
+	}
+
+	if *c.Type != "link" && c.To != nil {
+		return fmt.Errorf("unexpected argument '%s' for delete %s command", *c.To, *c.Type)
+	}
+	if *c.Type != "link" && c.Index != nil {
+		return fmt.Errorf("unexpected argument '%d' for delete %s command", *c.Index, *c.Type)
+	}
+
+	switch *c.Type {
+	case "trace":
+		if !telemetry.IsTraceExists(*c.Name) {
+			return fmt.Errorf("trace '%s' does not exist", *c.Name)
+		}
+	case "span":
+		if err := validateSpanRef(*c.Name, "span"); err != nil {
+			return err
+		}
+	case "resource":
+		if !telemetry.IsResourceExists(*c.Name) {
+			return fmt.Errorf("resource '%s' does not exist", *c.Name)
+		}
+	case "event":
+		if !telemetry.IsEventExists(*c.Name) {
+			return fmt.Errorf("event '%s' does not exist", *c.Name)
+		}
+	case "link":
+		if err := validateLinkRef(c.Name, c.To, c.Index, "delete link"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LinkRef returns the links selected by the delete link command
+func (c *DeleteCommand) LinkRef() telemetry.LinkRef {
+	return newLinkRef(c.Name, c.To, c.Index)
+}
+
+// SpanSelector selects the spans changed at once by a glob on the names and handles, e.g.
+// spans db_* where resource=db and attr db.system=postgresql in trace checkout.
+// Without the pattern, the conditions select among all the spans, e.g. spans where resource=db
+type SpanSelector struct {
+	Pattern    *string          `parser:"'spans' [ (?! 'where' | 'in') @Ident | @String ]"`
+	Conditions []*SpanCondition `parser:"[ 'where' @@ { 'and' @@ } ]"`
+	Trace      *string          `parser:"[ 'in' 'trace' @Ident ]"`
+}
+
+type SpanCondition struct {
+	Resource  *string   `parser:"'resource' '=' @Ident"`
+	Attribute *KeyValue `parser:"| 'attr' @@"`
+}
+
+func (sel *SpanSelector) Validate() error {
+	if sel.Pattern == nil && len(sel.Conditions) == 0 && sel.Trace == nil {
+		return errors.New("span name pattern or conditions must be specified after spans (e.g. spans db_*, spans * or spans where resource=db)")
+	}
+	attrs := make(map[string]bool)
+	resource := false
+	for _, c := range sel.Conditions {
+		if c.Resource != nil {
+			if resource {
+				return errors.New("resource condition is specified more than once")
+			}
+			resource = true
+			if !telemetry.IsResourceExists(*c.Resource) {
+				return fmt.Errorf("resource '%s' does not exist", *c.Resource)
+			}
+		}
+		if c.Attribute != nil {
+			if attrs[c.Attribute.Key] {
+				return fmt.Errorf("attribute condition %s is specified more than once", c.Attribute.Key)
+			}
+			attrs[c.Attribute.Key] = true
+		}
+	}
+	if sel.Trace != nil && !telemetry.IsTraceExists(*sel.Trace) {
+		return fmt.Errorf("trace '%s' does not exist", *sel.Trace)
+	}
+	return nil
+}
+
+// Selector returns the selector of the store
+func (sel *SpanSelector) Selector() telemetry.SpanSelector {
+	s := telemetry.SpanSelector{Attributes: make(map[string]string)}
+	if sel.Pattern != nil {
+		s.Pattern = *sel.Pattern
+	}
+	for _, c := range sel.Conditions {
+		if c.Resource != nil {
+			s.Resource = *c.Resource
+		}
+		if c.Attribute != nil {
+			s.Attributes[c.Attribute.Key] = c.Attribute.Value
+		}
+	}
+	if sel.Trace != nil {
+		s.Trace = *sel.Trace
+	}
+	return s
+}
+
+// String returns the selector as it is typed, e.g. spans db_* where resource=db in trace t
+func (sel *SpanSelector) String() string {
+	var sb strings.Builder
+	sb.WriteString("spans")
+	if sel.Pattern != nil {
+		fmt.Fprintf(&sb, " %s", *sel.Pattern)
+	}
+	for i, c := range sel.Conditions {
+		if i == 0 {
+			sb.WriteString(" where ")
+		} else {
+			sb.WriteString(" and ")
+		}
+		if c.Resource != nil {
+			fmt.Fprintf(&sb, "resource=%s", *c.Resource)
+		}
+		if c.Attribute != nil {
+			fmt.Fprintf(&sb, "attr %s=%s", c.Attribute.Key, c.Attribute.Value)
+		}
+	}
+	if sel.Trace != nil {
+		fmt.Fprintf(&sb, " in trace %s", *sel.Trace)
+	}
+	return sb.String()
+}
+
+// SetSpansCommand sets the arguments to all the selected spans, e.g. set spans db_* resource postgres.
+// With dry-run, the selected spans are shown without changing them.
+type SetSpansCommand struct {
+	Set    string        `parser:"'set'"`
+	Spans  *SpanSelector `parser:"@@"`
+	Args   []*SetArg     `parser:"@@*"`
+	DryRun bool          `parser:"[ @'dry-run' ]"`
+}
+
+func (c *SetSpansCommand) Validate() error {
+	if err := c.Spans.Validate(); err != nil {
+		return err
+	}
+	if len(c.Args) == 0 {
+		return errors.New("operation (name, resource, attributes etc.) must be specified for set spans command")
+	}
+	return validateSetArgs(c.Args, "span")
+}
+
+// DeleteSpansCommand deletes all the selected spans with their descendants, e.g. delete spans cache_*
+type DeleteSpansCommand struct {
+	Delete string        `parser:"'delete'"`
+	Spans  *SpanSelector `parser:"@@"`
+	DryRun bool          `parser:"[ @'dry-run' ]"`
+}
+
+func (c *DeleteSpansCommand) Validate() error {
+	return c.Spans.Validate()
+}
+
+type MoveCommand struct {
+	Move   string  `parser:"'move'"`
+	Type   *string `parser:"[ @'span' ]"`
+	Name   *string `parser:"[ @(Ident | String) ]"`
+	Parent *string `parser:"[ 'under' @(Ident | String) ]"`
+	Trace  *string `parser:"[ 'to' 'trace' @Ident ]"`
+}
+
+func (c *MoveCommand) Validate() error {
+	if c.Type == nil || c.Name == nil {
+		return fmt.Errorf("type and name must be specified for move command")
+	}
+	if c.Parent == nil && c.Trace == nil {
+		return fmt.Errorf("span must be moved under a parent span or to a trace")
+	}
+	if c.Parent != nil && c.Trace != nil {
+		return fmt.Errorf("span cannot be moved both under a parent span and to a trace")
+	}
+	if err := validateSpanRef(*c.Name, "span"); err != nil {
+		return err
+	}
+	if c.Parent != nil {
+		if err := validateSpanRef(*c.Parent, "parent span"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type CloneCommand struct {
+	Clone  string  `parser:"'clone'"`
+	Type   *string `parser:"[ @'span' ]"`
+	Name   *string `parser:"[ @(Ident | String) ]"`
+	Prefix *string `parser:"[ 'as' @Ident ]"`
+	Count  *int    `parser:"[ 'count' @Number ]"`
+	Parent *string `parser:"[ 'under' @(Ident | String) ]"`
+}
+
+func (c *CloneCommand) Validate() error {
+	if c.Type == nil || c.Name == nil {
+		return fmt.Errorf("type and name must be specified for clone command")
+	}
+	if c.Prefix == nil {
+		return fmt.Errorf("prefix must be specified with 'as' for clone command")
+	}
+	if c.Count != nil && *c.Count < 1 {
+		return fmt.Errorf("count must be greater than 0")
+	}
+	if err := validateSpanRef(*c.Name, "span"); err != nil {
+		return err
+	}
+	if c.Parent != nil {
+		if err := validateSpanRef(*c.Parent, "parent span"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type ListCommand struct {
+	List string  `parser:"'list'"`
+	Type *string `parser:"[ @('traces' | 'resources' | 'events' | 'links') ]"`
+	Span *string `parser:"[ @(Ident | String) ]"`
+}
+
+func (c *ListCommand) Validate() error {
+	if c.Span == nil {
+		return nil
+	}
+	if c.Type == nil || *c.Type != "links" {
+		return fmt.Errorf("span can only be specified for list links command")
+	}
+	return validateSpanRef(*c.Span, "span")
+}
+
+type OptionCommand struct {
+	Option string  `parser:"'option'"`
+	Key    *string `parser:"[ @Ident ]"`
+	Value  *string `parser:"[ @(Ident | Number) ]"`
+}
+
+func (c *OptionCommand) Validate() error {
+	if c.Key == nil {
+		return nil
+	}
+
+	switch *c.Key {
+	case "inherit-resource":
+		if c.Value == nil {
+			return fmt.Errorf("value must be specified for option '%s'", *c.Key)
+		}
+		if *c.Value != "on" && *c.Value != "off" {
+			return fmt.Errorf("value of option '%s' must be on or off", *c.Key)
+		}
+	case "jitter":
+		if c.Value == nil {
+			return fmt.Errorf("value must be specified for option '%s'", *c.Key)
+		}
+		jitter, err := strconv.ParseFloat(*c.Value, 64)
+		if err != nil || jitter < 0 || jitter > 1 {
+			return fmt.Errorf("value of option '%s' must be a number between 0 and 1", *c.Key)
+		}
+	default:
+		return fmt.Errorf("unknown option: %s", *c.Key)
+	}
+
+	return nil
+}
+
+type UndoCommand struct {
+	Undo string `parser:"@'undo'"`
+}
+
+type RedoCommand struct {
+	Redo string `parser:"@'redo'"`
+}
+
+// LetCommand defines a variable which is referred to as ${name} in the following commands, e.g. let env = staging
+type LetCommand struct {
+	Let   string  `parser:"'let'"`
+	Name  *string `parser:"[ @Ident ]"`
+	Value *string `parser:"[ '=' @(Ident | String | Duration | Number | Generator) ]"`
+}
+
+func (c *LetCommand) Validate() error {
+	if c.Name == nil {
+		return errors.New("variable name must be specified for let command")
+	}
+	if !variableNamePattern.MatchString(*c.Name) {
+		return fmt.Errorf("invalid variable name %s (letters, digits and underscores)", *c.Name)
+	}
+	if c.Value == nil {
+		return fmt.Errorf("value of variable %s must be specified with '='", *c.Name)
+	}
+	return nil
+}
+
+type VarsCommand struct {
+	Vars string `parser:"@'vars'"`
+}
+
+// TraceBlockCommand builds a whole trace at once from nested span blocks, e.g.
+// trace checkout { span "GET /cart" [resource=cart kind=server] { span "SELECT carts" [resource=db] } }
+type TraceBlockCommand struct {
+	Trace string      `parser:"'trace'"`
+	Name  *string     `parser:"[ @Ident ]"`
+	Block *TraceBlock `parser:"[ @@ ]"`
+}
+
+type TraceBlock struct {
+	Open  string       `parser:"@'{'"`
+	Spans []*SpanBlock `parser:"@@* '}'"`
+}
+
+// SpanBlock is a span with its properties in brackets and its children in braces. The properties
+// resource, kind and duration are set to the span, and the others are added as attributes.
+type SpanBlock struct {
+	Name       string       `parser:"'span' @(Ident | String)"`
+	Properties []*KeyValue  `parser:"[ '[' [ @@ { [ ',' ] @@ } ] ']' ]"`
+	Children   []*SpanBlock `parser:"[ '{' @@* '}' ]"`
+}
+
+func (c *TraceBlockCommand) Validate() error {
+	if c.Name == nil {
+		return errors.New("trace name must be specified for trace command")
+	}
+	if c.Block == nil {
+		return fmt.Errorf("spans of trace %s must be specified in { }", *c.Name)
+	}
+	if len(c.Block.Spans) > 1 {
+		return fmt.Errorf("trace %s must have only one root span", *c.Name)
+	}
+	for _, span := range c.Block.Spans {
+		if err := span.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *SpanBlock) Validate() error {
+	seen := make(map[string]bool)
+	for _, p := range b.Properties {
+		if seen[p.Key] {
+			return fmt.Errorf("property %s of span '%s' is specified more than once", p.Key, b.Name)
+		}
+		seen[p.Key] = true
+
+		var err error
+		switch p.Key {
+		case "resource":
+		case "kind":
+			_, err = telemetry.ParseSpanKind(p.Value)
+		case "duration":
+			_, err = telemetry.ParseDistribution(p.Value)
+		default:
+			if err := validateKeyValues([]*KeyValue{p}); err != nil {
+				return err
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("invalid %s of span '%s': %w", p.Key, b.Name, err)
+		}
+	}
+	for _, child := range b.Children {
+		if err := child.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DefineCommand starts the definition of a template, whose commands follow on the next lines
+// until a line with only }, e.g. define template http_handler(svc, route) {
+type DefineCommand struct {
+	Define string   `parser:"'define' 'template'"`
+	Name   *string  `parser:"[ @Ident ]"`
+	Params []string `parser:"[ '(' [ @Ident { ',' @Ident } ] ')' ]"`
+	Open   bool     `parser:"[ @'{' ]"`
+}
+
+func (c *DefineCommand) Validate() error {
+	if c.Name == nil {
+		return errors.New("template name must be specified for define command")
+	}
+	for _, p := range c.Params {
+		if !variableNamePattern.MatchString(p) {
+			return fmt.Errorf("invalid parameter name %s of template %s", p, *c.Name)
+		}
+		if p == templateParentParam {
+			return fmt.Errorf("parameter name %s is reserved for the span given with 'under'", p)
+		}
+	}
+	if !c.Open {
+		return fmt.Errorf("'{' must follow the parameters of template %s", *c.Name)
+	}
+	return nil
+}
+
+// InstantiateCommand expands a template with the parameters, e.g. instantiate http_handler under gateway svc=cart route=/cart
+type InstantiateCommand struct {
+	Instantiate string      `parser:"'instantiate'"`
+	Name        *string     `parser:"[ @Ident ]"`
+	Parent      *string     `parser:"[ 'under' @(Ident | String) ]"`
+	Args        []*KeyValue `parser:"@@*"`
+}
+
+func (c *InstantiateCommand) Validate() error {
+	if c.Name == nil {
+		return errors.New("template name must be specified for instantiate command")
+	}
+	t, ok := telemetry.GetTemplates()[*c.Name]
+	if !ok {
+		return fmt.Errorf("template '%s' does not exist", *c.Name)
+	}
+	if c.Parent != nil {
+		if err := validateSpanRef(*c.Parent, "parent span"); err != nil {
+			return err
+		}
+	} else if templateUsesParent(t) {
+		return fmt.Errorf("parent span must be specified with 'under' for template %s", t.Name)
+	}
+
+	seen := make(map[string]bool)
+	for _, arg := range c.Args {
+		if !slices.Contains(t.Params, arg.Key) {
+			return fmt.Errorf("template %s has no parameter %s", t.Name, arg.Key)
+		}
+		if seen[arg.Key] {
+			return fmt.Errorf("parameter %s is specified more than once", arg.Key)
+		}
+		seen[arg.Key] = true
+	}
+	for _, p := range t.Params {
+		if !seen[p] {
+			return fmt.Errorf("parameter %s of template %s must be specified", p, t.Name)
+		}
+	}
+	return nil
+}
+
+// WorkspaceCommand manages the workspaces, each of which has its own traces, spans, resources
+// and events, e.g. workspace new checkout or workspace copy checkout checkout-errors
+type WorkspaceCommand struct {
+	Workspace string  `parser:"'workspace'"`
+	Action    *string `parser:"[ @('new' | 'switch' | 'list' | 'delete' | 'copy') ]"`
+	Name      *string `parser:"[ @Ident ]"`
+	To        *string `parser:"[ @Ident ]"`
+}
+
+func (c *WorkspaceCommand) Validate() error {
+	if c.Action == nil {
+		return errors.New("action (new, switch, list, delete or copy) must be specified for workspace command")
+	}
+
+	switch *c.Action {
+	case "list":
+		if c.Name != nil {
+			return fmt.Errorf("unexpected argument '%s' for workspace list command", *c.Name)
+		}
+		return nil
+	case "copy":
+		if c.Name == nil || c.To == nil {
+			return errors.New("source and new workspace names must be specified for workspace copy command")
+		}
+		if !telemetry.IsWorkspaceExists(*c.Name) {
+			return fmt.Errorf("workspace '%s' does not exist", *c.Name)
+		}
+		if telemetry.IsWorkspaceExists(*c.To) {
+			return fmt.Errorf("workspace '%s' already exists", *c.To)
+		}
+		return nil
+	}
+
+	if c.Name == nil {
+		return fmt.Errorf("workspace name must be specified for workspace %s command", *c.Action)
+	}
+	if c.To != nil {
+		return fmt.Errorf("unexpected argument '%s' for workspace %s command", *c.To, *c.Action)
+	}
+	switch *c.Action {
+	case "new":
+		if telemetry.IsWorkspaceExists(*c.Name) {
+			return fmt.Errorf("workspace '%s' already exists", *c.Name)
+		}
+	case "switch", "delete":
+		if !telemetry.IsWorkspaceExists(*c.Name) {
+			return fmt.Errorf("workspace '%s' does not exist", *c.Name)
+		}
+		if *c.Action == "delete" && *c.Name == telemetry.Workspace() {
+			return fmt.Errorf("cannot delete the current workspace '%s', switch to another workspace first", *c.Name)
+		}
+	}
+	return nil
+}
+
+// SendCommand sends all the traces. The seed reproduces the random values of a previous send,
+// and the errors fail a percentage of the spans, e.g. send seed 42 errors 5% for resource cart propagate.
+// With --lint, nothing is sent when the lint finds errors.
+type SendCommand struct {
+	Send   string           `parser:"'send'"`
+	Lint   bool             `parser:"[ @'--lint' ]"`
+	Seed   *int64           `parser:"[ 'seed' @Number ]"`
+	Errors []*SendErrorsArg `parser:"@@*"`
+}
+
+func (c *SendCommand) Validate() error {
+	for _, arg := range c.Errors {
+		if err := arg.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SendErrorsArg is an error injection of the send command
+type SendErrorsArg struct {
+	Rate      float64   `parser:"'errors' @Number '%'"`
+	Resource  *string   `parser:"[ 'for' ( 'resource' @Ident"`
+	SpanName  *string   `parser:"| 'span' @(Ident | String) ) ]"`
+	Attribute *KeyValue `parser:"[ 'attribute' @@ ]"`
+	Propagate bool      `parser:"[ @'propagate' ]"`
+}
+
+func (arg *SendErrorsArg) Validate() error {
+	if arg.Rate <= 0 || arg.Rate > 100 {
+		return fmt.Errorf("error rate must be greater than 0%% and at most 100%%")
+	}
+	if arg.Resource != nil && !telemetry.IsResourceExists(*arg.Resource) {
+		return fmt.Errorf("resource '%s' does not exist", *arg.Resource)
+	}
+	if arg.Attribute != nil {
+		return validateKeyValues([]*KeyValue{arg.Attribute})
+	}
+	return nil
+}
+
+// injection returns the error injection of the argument
+func (arg *SendErrorsArg) injection() telemetry.ErrorInjection {
+	inj := telemetry.ErrorInjection{
+		Rate:      arg.Rate / 100,
+		Propagate: arg.Propagate,
+	}
+	if arg.Resource != nil {
+		inj.Resource = *arg.Resource
+	}
+	if arg.SpanName != nil {
+		inj.SpanName = *arg.SpanName
+	}
+	if arg.Attribute != nil {
+		inj.Attribute = [2]string{arg.Attribute.Key, arg.Attribute.Value}
+	}
+	return inj
+}
+
+// LintCommand checks the signals of the current workspace against the semantic conventions
+type LintCommand struct {
+	Lint string `parser:"@'lint'"`
+}
+
+// LoadCommand replaces the traces, spans, resources and events with a scenario file,
+// e.g. one written by otelgen record
+type LoadCommand struct {
+	Load string  `parser:"'load'"`
+	Path *string `parser:"[ @(Ident | String) ]"`
+}
+
+func (c *LoadCommand) Validate() error {
+	if c.Path == nil {
+		return errors.New("file path must be specified for load command")
+	}
+	return nil
+}
+
+// GenerateCommand generates random traces from a topology file into the store, or sends them
+// one by one without keeping them with stream, e.g. generate from topology.yaml count 100 seed 42 as checkout
+type GenerateCommand struct {
+	Generate string  `parser:"'generate'"`
+	Topology *string `parser:"[ 'from' @(Ident | String) ]"`
+	Count    *int    `parser:"[ 'count' @Number ]"`
+	Seed     *int64  `parser:"[ 'seed' @Number ]"`
+	Prefix   *string `parser:"[ 'as' @Ident ]"`
+	Stream   bool    `parser:"[ @'stream' ]"`
+}
+
+func (c *GenerateCommand) Validate() error {
+	if c.Topology == nil {
+		return errors.New("topology file must be specified with 'from' for generate command")
+	}
+	if c.Count != nil && *c.Count < 1 {
+		return errors.New("count must be greater than 0")
+	}
+	if c.Stream && c.Prefix != nil {
+		return errors.New("prefix cannot be specified with stream because the traces are not kept")
+	}
+	return nil
+}
+
+// SaveCommand writes the traces, spans, resources and events to a scenario file
+type SaveCommand struct {
+	Save string  `parser:"'save'"`
+	Path *string `parser:"[ @(Ident | String) ]"`
+}
+
+func (c *SaveCommand) Validate() error {
+	if c.Path == nil {
+		return errors.New("file path must be specified for save command")
+	}
+	return nil
+}
+
+// ExpectCommand checks the spans produced by the last send, e.g.
+// expect span checkout has attribute http.status_code=500 or expect trace t spans=5
+type ExpectCommand struct {
+	Expect string  `parser:"'expect'"`
+	Type   *string `parser:"[ @('span' | 'trace') ]"`
+	Name   *string `parser:"[ @(Ident | String) ]"`
+	Spans  *int    `parser:"[ 'spans' '=' @Number"`
+	Has    *string `parser:"| 'has' @('attribute' | 'event' | 'parent' | 'resource') ]"`
+	Key    *string `parser:"[ @(Ident | String) ]"`
+	Value  *string `parser:"[ '=' @(Ident | String | Number) ]"`
+}
+
+func (c *ExpectCommand) Validate() error {
+	if c.Type == nil || c.Name == nil {
+		return errors.New("type and name must be specified for expect command")
+	}
+
+	switch *c.Type {
+	case "trace":
+		if c.Has != nil {
+			return errors.New("'has' can only be specified for expect span command")
+		}
+		if c.Spans == nil {
+			return errors.New("span count must be specified for expect trace command (e.g. spans=5)")
+		}
+	case "span":
+		if c.Spans != nil {
+			return errors.New("span count can only be specified for expect trace command")
+		}
+		if c.Has == nil {
+			return errors.New("assertion (has attribute, has event, has parent or has resource) must be specified for expect span command")
+		}
+		if c.Key == nil {
+			return fmt.Errorf("%s must be specified after 'has %s'", *c.Has, *c.Has)
+		}
+		if c.Value != nil && *c.Has != "attribute" {
+			return fmt.Errorf("value can only be specified for attribute, not for %s", *c.Has)
+		}
+	}
+
+	return nil
+}
+
+// String returns the expectation without the expect keyword, e.g. span checkout has attribute key=value
+func (c *ExpectCommand) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s %s", *c.Type, *c.Name)
+	if c.Spans != nil {
+		fmt.Fprintf(&sb, " spans=%d", *c.Spans)
+	}
+	if c.Has != nil {
+		fmt.Fprintf(&sb, " has %s %s", *c.Has, *c.Key)
+		if c.Value != nil {
+			fmt.Fprintf(&sb, "=%s", *c.Value)
+		}
+	}
+	return sb.String()
+}
+
+// ReceivedCommand shows the spans received by the local OTLP receiver
+type ReceivedCommand struct {
+	Received string  `parser:"'received'"`
+	Action   *string `parser:"[ @('list' | 'show' | 'clear') ]"`
+	// Trace is the index in received list written as #N, or a prefix of the trace ID. Tokens are
+	// joined because a hex trace ID may be split into a number and an identifier by the lexer.
+	Trace *string `parser:"[ @Index | @(Number | Ident)+ ]"`
+}
+
+func (c *ReceivedCommand) Validate() error {
+	if c.Action == nil {
+		return errors.New("action (list, show or clear) must be specified for received command")
+	}
+	if *c.Action == "show" && c.Trace == nil {
+		return errors.New("trace ID or index must be specified for received show command")
+	}
+	if *c.Action != "show" && c.Trace != nil {
+		return fmt.Errorf("unexpected argument '%s' for received %s command", *c.Trace, *c.Action)
+	}
+	return nil
+}
+
+// KeyValue is an attribute. The value can be a generator expression evaluated on each send,
+// e.g. user.id={{uuid}} or http.route={{pick /a,/b}}
+type KeyValue struct {
+	Key   string `parser:"@Ident '='"`
+	Value string `parser:"@(Ident | String | Duration | Number | Generator)"`
+}
+
+// validateKeyValues checks the generator expressions in the values
+func validateKeyValues(attrs []*KeyValue) error {
+	for _, kv := range attrs {
+		if err := telemetry.ValidateValue(kv.Value); err != nil {
+			return fmt.Errorf("invalid value of attribute '%s': %w", kv.Key, err)
+		}
+	}
+	return nil
+}
+
+func convertKeyValuesToMap(attrs []*KeyValue) map[string]string {
+	attrsMap := make(map[string]string)
+	for _, kv := range attrs {
+		if kv != nil {
+			attrsMap[kv.Key] = kv.Value
+		}
+	}
+	return attrsMap
+}
+
+var (
+	commandLexer = lexer.MustSimple([]lexer.SimpleRule{
+		{Name: "Index", Pattern: `#\d+\b`},
+		{Name: "Comment", Pattern: `#[^\n]*`},
+		{Name: "Whitespace", Pattern: `\s+`},
+		{Name: "Generator", Pattern: `\{\{[^}]*\}\}`},
+		{Name: "String", Pattern: `"[^"]*"|'[^']*'`},
+		{Name: "Duration", Pattern: `(\d+(\.\d+)?(ns|us|µs|ms|s|m|h))+\b`},
+		{Name: "Number", Pattern: `[-+]?\d+(\.\d+)?`},
+		{Name: "Ident", Pattern: `[a-zA-Z_/*?][a-zA-Z0-9_\.\-/*?]*`},
+		{Name: "Flag", Pattern: `--[a-zA-Z][a-zA-Z0-9\-]*`},
+		{Name: "Punct", Pattern: `[,=%(){}\[\]]`},
+	})
+
+	parser = participle.MustBuild[Command](
+		participle.Lexer(commandLexer),
+		participle.Elide("Comment", "Whitespace"),
+		participle.Unquote("String"),
+	)
+)
+
+// ParseCommand parses the input after replacing the references to the variables with their values
+func ParseCommand(input string) (*Command, error) {
+	input, err := interpolate(input)
+	if err != nil {
+		return nil, err
+	}
+	return parser.ParseString("", input)
+}
+
+// validateLinkRef checks that the links are selected either by the target span or by the index
+func validateLinkRef(from, to *string, index *int, command string) error {
+	if from == nil || (to == nil && index == nil) {
+		return fmt.Errorf("'from' and either 'to' or a link index must be specified for %s command", command)
+	}
+	if to != nil && index != nil {
+		return fmt.Errorf("'to' and a link index cannot be specified together for %s command", command)
+	}
+	if err := validateSpanRef(*from, "span"); err != nil {
+		return err
+	}
+	if to != nil {
+		return validateSpanRef(*to, "span")
+	}
+	return nil
+}
+
+func newLinkRef(from, to *string, index *int) telemetry.LinkRef {
+	ref := telemetry.LinkRef{From: *from, Index: index}
+	if to != nil {
+		ref.To = *to
+	}
+	return ref
+}
+
+// validateSpanRef checks that the reference resolves to exactly one span.
+// kind is used in the error message when no span matches (e.g. "parent span").
+func validateSpanRef(ref, kind string) error {
+	_, err := telemetry.LookupSpan(ref)
+	var notFound *telemetry.SpanNotFoundError
+	if errors.As(err, &notFound) {
+		return fmt.Errorf("%s '%s' does not exist", kind, ref)
+	}
+	return err
 }
 
 func checkDuplicateOps(ops []string) error {
@@ -1,6 +1,7 @@
 package executor
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -49,6 +50,78 @@ func TestCreateCommandValidate(t *testing.T) {
 			input: "create span span1 in trace my-trace resource non_existing_resource",
 			want:  fmt.Errorf("resource 'non_existing_resource' does not exist"),
 		},
+		{
+			input: "create resource resource1 semconv v1.4.0 defaults on",
+			want:  nil,
+		},
+		{
+			input: "create resource resource1 semconv v0.1.0",
+			want:  fmt.Errorf("semconv version 'v0.1.0' is not supported (supported: v1.4.0, v1.12.0, v1.17.0, v1.20.0, v1.21.0, v1.24.0, v1.26.0, v1.30.0, v1.32.0)"),
+		},
+		{
+			input: "create span span1 in trace my-trace semconv v1.26.0",
+			want:  errors.New("semconv can only be specified when the type is resource"),
+		},
+		{
+			input: "create event event1 defaults on",
+			want:  errors.New("defaults can only be specified when the type is resource"),
+		},
+		{
+			input: "create span span1 in trace my-trace duration 1.5s",
+			want:  nil,
+		},
+		{
+			input: `create span span1 in trace my-trace duration "percentiles(p50=20ms, p99=300ms)"`,
+			want:  nil,
+		},
+		{
+			input: `create span span1 in trace my-trace duration "poisson(1s)"`,
+			want:  errors.New("unknown distribution poisson (fixed, uniform, normal, lognormal or percentiles)"),
+		},
+		{
+			input: "create resource resource1 duration 100ms",
+			want:  errors.New("duration can only be specified when the type is span"),
+		},
+		{
+			input: `create span span1 in trace my-trace attributes user.id={{uuid}},route={{pick /a,/b}},msg="order {{seq 100}}"`,
+			want:  nil,
+		},
+		{
+			input: "create span span1 in trace my-trace attributes amount={{rand 500 1}}",
+			want:  fmt.Errorf("invalid value of attribute 'amount': %w", errors.New("minimum 500 of rand is greater than maximum 1")),
+		},
+		{
+			input: "create span in trace my-trace as http.server method=GET route=/users status=200",
+			want:  nil,
+		},
+		{
+			input: "create span consume with parent my-span as messaging.kafka topic=orders kind consumer",
+			want:  nil,
+		},
+		{
+			input: "create span in trace my-trace as http",
+			want:  errors.New("unknown span convention http (db, http.client, http.server, messaging.kafka, rpc.grpc)"),
+		},
+		{
+			input: "create span in trace my-trace as rpc.grpc service=shop.Cart",
+			want:  errors.New("parameter method of span convention rpc.grpc must be specified"),
+		},
+		{
+			input: "create span in trace my-trace as db system=mysql system=redis",
+			want:  errors.New("parameter system of span convention db is specified more than once"),
+		},
+		{
+			input: "create span in trace my-trace as http.client method=GET kind server",
+			want:  errors.New("span convention http.client cannot be applied to a server span (client)"),
+		},
+		{
+			input: "create resource resource1 as db system=mysql",
+			want:  errors.New("as can only be specified when the type is span"),
+		},
+		{
+			input: "create span in trace my-trace",
+			want:  errors.New("type and name must be specified for create command"),
+		},
 	}
 
 	for _, tt := range tests {
@@ -81,6 +154,10 @@ func TestSetCommandValidate(t *testing.T) {
 			input: "set span my-span resource my-resource name new-my-span",
 			want:  nil,
 		},
+		{
+			input: "set span my-span add-attributes id={{nope}}",
+			want:  fmt.Errorf("invalid value of attribute 'id': %w", errors.New("unknown generator nope (uuid, seq, pick, rand, email, ipv4 or ipv6)")),
+		},
 		{
 			input: "set span my-span resource my-resource name new-my-span resource another-resource",
 			want:  fmt.Errorf("duplicated operation: resource"),
@@ -109,6 +186,50 @@ func TestSetCommandValidate(t *testing.T) {
 			input: "set event non-existing-event name new-event-name",
 			want:  fmt.Errorf("event 'non-existing-event' does not exist"),
 		},
+		{
+			input: "set trace my-trace inherit-resource on",
+			want:  nil,
+		},
+		{
+			input: "set trace non-existing-trace inherit-resource on",
+			want:  fmt.Errorf("trace 'non-existing-trace' does not exist"),
+		},
+		{
+			input: "set trace my-trace resource my-resource",
+			want:  errors.New("only inherit-resource can be specified when the type is trace"),
+		},
+		{
+			input: "set trace my-trace name new-trace",
+			want:  errors.New("name cannot be specified when the type is trace"),
+		},
+		{
+			input: "set span my-span inherit-resource off",
+			want:  errors.New("inherit-resource can only be specified when the type is trace"),
+		},
+		{
+			input: "set span my-span add-attributes a=x,b=y remove-attributes key",
+			want:  nil,
+		},
+		{
+			input: "set event my-event remove-attributes key,other",
+			want:  nil,
+		},
+		{
+			input: "set span my-span attributes a=x add-attributes b=y",
+			want:  errors.New("attributes cannot be combined with add-attributes or remove-attributes"),
+		},
+		{
+			input: "set resource my-resource add-attributes key=new remove-attributes key",
+			want:  errors.New("attribute 'key' cannot be both added and removed"),
+		},
+		{
+			input: "set span my-span remove-attributes a remove-attributes b",
+			want:  fmt.Errorf("duplicated operation: remove-attributes"),
+		},
+		{
+			input: "set trace my-trace add-attributes a=x",
+			want:  errors.New("only inherit-resource can be specified when the type is trace"),
+		},
 	}
 
 	for _, tt := range tests {
@@ -142,6 +263,10 @@ func TestAddLinkCommandValidate(t *testing.T) {
 			input: "add link my-span another-span attributes key=value",
 			want:  nil,
 		},
+		{
+			input: "add link my-span another-span attributes key={{uuid x}}",
+			want:  fmt.Errorf("invalid value of attribute 'key': %w", errors.New("uuid takes no arguments")),
+		},
 		{
 			input: "add link",
 			want:  fmt.Errorf("both 'from' and 'to' must be specified for add link command"),
@@ -176,6 +301,71 @@ func TestAddLinkCommandValidate(t *testing.T) {
 	}
 }
 
+func TestSetLinkCommandValidate(t *testing.T) {
+	tests := []struct {
+		input string
+		want  error
+	}{
+		{
+			input: "set link my-span another-span attributes key=value",
+			want:  nil,
+		},
+		{
+			input: "set link my-span another-span attributes key={{seq}}",
+			want:  nil,
+		},
+		{
+			input: "set link my-span another-span attributes key={{rand}}",
+			want:  fmt.Errorf("invalid value of attribute 'key': %w", errors.New("rand takes the minimum and the maximum")),
+		},
+		{
+			input: "set link my-span another-span add-attributes key=value remove-attributes other",
+			want:  nil,
+		},
+		{
+			input: "set link my-span",
+			want:  fmt.Errorf("'from' and either 'to' or a link index must be specified for set link command"),
+		},
+		{
+			input: "set link my-span another-span",
+			want:  fmt.Errorf("operation (attributes, add-attributes or remove-attributes) must be specified for set link command"),
+		},
+		{
+			input: "set link my-span another-span attributes a=x remove-attributes b",
+			want:  errors.New("attributes cannot be combined with add-attributes or remove-attributes"),
+		},
+		{
+			input: "set link my-span 0 attributes key=value",
+			want:  nil,
+		},
+		{
+			input: "set link my-span another-span 0 attributes key=value",
+			want:  fmt.Errorf("'to' and a link index cannot be specified together for set link command"),
+		},
+		{
+			input: "set link wrong-span another-span attributes a=x",
+			want:  fmt.Errorf("span 'wrong-span' does not exist"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			telemetry.InitStore()
+			telemetry.CreateTrace("my-trace")
+			telemetry.AddSpanToTrace("my-trace", "my-span", map[string]string{})
+			telemetry.CreateTrace("another-trace")
+			telemetry.AddSpanToTrace("another-trace", "another-span", map[string]string{})
+			telemetry.AddLinkToSpan("my-span", "another-span", map[string]string{})
+
+			gotCmd, err := ParseCommand(tt.input)
+			assert.Nil(t, err, "ParseCommand should not return an error for input: %s", tt.input)
+			assert.NotNil(t, gotCmd.SetLink, "SetLink command should not be nil for input: %s", tt.input)
+			gotErr := gotCmd.SetLink.Validate()
+			assert.Equal(t, tt.want, gotErr, "Validate should return %v for input: %s", tt.want, tt.input)
+		})
+	}
+}
+
 func TestAddEventCommandValidate(t *testing.T) {
 	tests := []struct {
 		input string
@@ -214,3 +404,659 @@ func TestAddEventCommandValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteCommandValidate(t *testing.T) {
+	tests := []struct {
+		input string
+		want  error
+	}{
+		{
+			input: "delete span my-span",
+			want:  nil,
+		},
+		{
+			input: "delete link my-span another-span",
+			want:  nil,
+		},
+		{
+			input: "delete span",
+			want:  fmt.Errorf("type and name must be specified for delete command"),
+		},
+		{
+			input: "delete trace wrong-trace",
+			want:  fmt.Errorf("trace 'wrong-trace' does not exist"),
+		},
+		{
+			input: "delete span wrong-span",
+			want:  fmt.Errorf("span 'wrong-span' does not exist"),
+		},
+		{
+			input: "delete resource wrong-resource",
+			want:  fmt.Errorf("resource 'wrong-resource' does not exist"),
+		},
+		{
+			input: "delete event wrong-event",
+			want:  fmt.Errorf("event 'wrong-event' does not exist"),
+		},
+		{
+			input: "delete span my-span another-span",
+			want:  fmt.Errorf("unexpected argument 'another-span' for delete span command"),
+		},
+		{
+			input: "delete link my-span",
+			want:  fmt.Errorf("'from' and either 'to' or a link index must be specified for delete link command"),
+		},
+		{
+			input: "delete link my-span wrong-span",
+			want:  fmt.Errorf("span 'wrong-span' does not exist"),
+		},
+		{
+			input: "delete link my-span 0",
+			want:  nil,
+		},
+		{
+			input: "delete span my-span 0",
+			want:  fmt.Errorf("unexpected argument '0' for delete span command"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			telemetry.InitStore()
+			telemetry.CreateTrace("my-trace")
+			telemetry.AddSpanToTrace("my-trace", "my-span", map[string]string{"key": "value"})
+			telemetry.CreateTrace("another-trace")
+			telemetry.AddSpanToTrace("another-trace", "another-span", map[string]string{"key": "value"})
+
+			gotCmd, err := ParseCommand(tt.input)
+			assert.Nil(t, err, "ParseCommand should not return an error for input: %s", tt.input)
+			assert.NotNil(t, gotCmd.Delete, "Delete command should not be nil for input: %s", tt.input)
+			gotErr := gotCmd.Delete.Validate()
+			assert.Equal(t, tt.want, gotErr, "Validate should return %v for input: %s", tt.want, tt.input)
+		})
+	}
+}
+
+func TestMoveCommandValidate(t *testing.T) {
+	tests := []struct {
+		input string
+		want  error
+	}{
+		{
+			input: "move span my-span under another-span",
+			want:  nil,
+		},
+		{
+			input: "move span my-span to trace new-trace",
+			want:  nil,
+		},
+		{
+			input: "move span",
+			want:  fmt.Errorf("type and name must be specified for move command"),
+		},
+		{
+			input: "move span my-span",
+			want:  fmt.Errorf("span must be moved under a parent span or to a trace"),
+		},
+		{
+			input: "move span my-span under another-span to trace new-trace",
+			want:  fmt.Errorf("span cannot be moved both under a parent span and to a trace"),
+		},
+		{
+			input: "move span wrong-span under another-span",
+			want:  fmt.Errorf("span 'wrong-span' does not exist"),
+		},
+		{
+			input: "move span my-span under wrong-span",
+			want:  fmt.Errorf("parent span 'wrong-span' does not exist"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			telemetry.InitStore()
+			telemetry.CreateTrace("my-trace")
+			telemetry.AddSpanToTrace("my-trace", "my-span", map[string]string{"key": "value"})
+			telemetry.CreateTrace("another-trace")
+			telemetry.AddSpanToTrace("another-trace", "another-span", map[string]string{"key": "value"})
+
+			gotCmd, err := ParseCommand(tt.input)
+			assert.Nil(t, err, "ParseCommand should not return an error for input: %s", tt.input)
+			assert.NotNil(t, gotCmd.Move, "Move command should not be nil for input: %s", tt.input)
+			gotErr := gotCmd.Move.Validate()
+			assert.Equal(t, tt.want, gotErr, "Validate should return %v for input: %s", tt.want, tt.input)
+		})
+	}
+}
+
+func TestExpectCommandValidate(t *testing.T) {
+	tests := []struct {
+		input string
+		want  error
+	}{
+		{
+			input: "expect span checkout has attribute http.status_code=500",
+			want:  nil,
+		},
+		{
+			input: "expect span checkout has attribute http.route",
+			want:  nil,
+		},
+		{
+			input: "expect span \"GET /users\" has parent checkout",
+			want:  nil,
+		},
+		{
+			input: "expect trace t spans=5",
+			want:  nil,
+		},
+		{
+			input: "expect span",
+			want:  errors.New("type and name must be specified for expect command"),
+		},
+		{
+			input: "expect trace t",
+			want:  errors.New("span count must be specified for expect trace command (e.g. spans=5)"),
+		},
+		{
+			input: "expect trace t has event x",
+			want:  errors.New("'has' can only be specified for expect span command"),
+		},
+		{
+			input: "expect span checkout spans=1",
+			want:  errors.New("span count can only be specified for expect trace command"),
+		},
+		{
+			input: "expect span checkout",
+			want:  errors.New("assertion (has attribute, has event, has parent or has resource) must be specified for expect span command"),
+		},
+		{
+			input: "expect span checkout has event",
+			want:  errors.New("event must be specified after 'has event'"),
+		},
+		{
+			input: "expect span checkout has resource cart=x",
+			want:  errors.New("value can only be specified for attribute, not for resource"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			gotCmd, err := ParseCommand(tt.input)
+			assert.Nil(t, err, "ParseCommand should not return an error for input: %s", tt.input)
+			assert.NotNil(t, gotCmd.Expect, "Expect command should not be nil for input: %s", tt.input)
+			gotErr := gotCmd.Expect.Validate()
+			assert.Equal(t, tt.want, gotErr, "Validate should return %v for input: %s", tt.want, tt.input)
+		})
+	}
+}
+
+func TestReceivedCommandValidate(t *testing.T) {
+	tests := []struct {
+		input     string
+		wantTrace string
+		want      error
+	}{
+		{
+			input: "received list",
+			want:  nil,
+		},
+		{
+			input:     "received show #2",
+			wantTrace: "#2",
+			want:      nil,
+		},
+		{
+			input:     "received show 12",
+			wantTrace: "12",
+			want:      nil,
+		},
+		{
+			input:     "received show 4bf92f3577b34da6",
+			wantTrace: "4bf92f3577b34da6",
+			want:      nil,
+		},
+		{
+			input: "received",
+			want:  errors.New("action (list, show or clear) must be specified for received command"),
+		},
+		{
+			input: "received show",
+			want:  errors.New("trace ID or index must be specified for received show command"),
+		},
+		{
+			input:     "received clear 1",
+			wantTrace: "1",
+			want:      errors.New("unexpected argument '1' for received clear command"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			gotCmd, err := ParseCommand(tt.input)
+			assert.Nil(t, err, "ParseCommand should not return an error for input: %s", tt.input)
+			assert.NotNil(t, gotCmd.Received, "Received command should not be nil for input: %s", tt.input)
+			if tt.wantTrace != "" {
+				assert.Equal(t, tt.wantTrace, *gotCmd.Received.Trace)
+			}
+			gotErr := gotCmd.Received.Validate()
+			assert.Equal(t, tt.want, gotErr, "Validate should return %v for input: %s", tt.want, tt.input)
+		})
+	}
+}
+
+func TestLoadSaveCommandValidate(t *testing.T) {
+	tests := []struct {
+		input string
+		want  error
+	}{
+		{
+			input: "load scenario.yaml",
+			want:  nil,
+		},
+		{
+			input: "save \"/tmp/my scenario.yaml\"",
+			want:  nil,
+		},
+		{
+			input: "load",
+			want:  errors.New("file path must be specified for load command"),
+		},
+		{
+			input: "save",
+			want:  errors.New("file path must be specified for save command"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			gotCmd, err := ParseCommand(tt.input)
+			assert.Nil(t, err, "ParseCommand should not return an error for input: %s", tt.input)
+			var gotErr error
+			switch {
+			case gotCmd.Load != nil:
+				gotErr = gotCmd.Load.Validate()
+			case gotCmd.Save != nil:
+				gotErr = gotCmd.Save.Validate()
+			default:
+				t.Fatalf("Load or Save command should not be nil for input: %s", tt.input)
+			}
+			assert.Equal(t, tt.want, gotErr, "Validate should return %v for input: %s", tt.want, tt.input)
+		})
+	}
+}
+
+func TestGenerateCommandValidate(t *testing.T) {
+	tests := []struct {
+		input string
+		want  error
+	}{
+		{
+			input: "generate from topology.yaml",
+			want:  nil,
+		},
+		{
+			input: "generate from \"my topology.yaml\" count 100 seed 42 as checkout",
+			want:  nil,
+		},
+		{
+			input: "generate from topology.yaml count 10 stream",
+			want:  nil,
+		},
+		{
+			input: "generate",
+			want:  errors.New("topology file must be specified with 'from' for generate command"),
+		},
+		{
+			input: "generate from topology.yaml count 0",
+			want:  errors.New("count must be greater than 0"),
+		},
+		{
+			input: "generate from topology.yaml as checkout stream",
+			want:  errors.New("prefix cannot be specified with stream because the traces are not kept"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			gotCmd, err := ParseCommand(tt.input)
+			assert.Nil(t, err, "ParseCommand should not return an error for input: %s", tt.input)
+			assert.NotNil(t, gotCmd.Generate, "Generate command should not be nil for input: %s", tt.input)
+			gotErr := gotCmd.Generate.Validate()
+			assert.Equal(t, tt.want, gotErr, "Validate should return %v for input: %s", tt.want, tt.input)
+		})
+	}
+}
+
+func TestSendCommandValidate(t *testing.T) {
+	tests := []struct {
+		input string
+		want  error
+	}{
+		{
+			input: "send",
+			want:  nil,
+		},
+		{
+			input: "send seed 42 errors 5% for resource my-resource attribute error.type=timeout propagate errors 1.5%",
+			want:  nil,
+		},
+		{
+			input: "send errors 5% for span my-span",
+			want:  nil,
+		},
+		{
+			input: "send --lint seed 42 errors 5%",
+			want:  nil,
+		},
+		{
+			input: "send errors 0%",
+			want:  errors.New("error rate must be greater than 0% and at most 100%"),
+		},
+		{
+			input: "send errors 150%",
+			want:  errors.New("error rate must be greater than 0% and at most 100%"),
+		},
+		{
+			input: "send errors 5% for resource unknown",
+			want:  errors.New("resource 'unknown' does not exist"),
+		},
+		{
+			input: "send errors 5% attribute error={{nope}}",
+			want:  fmt.Errorf("invalid value of attribute 'error': %w", errors.New("unknown generator nope (uuid, seq, pick, rand, email, ipv4 or ipv6)")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			telemetry.InitStore()
+			telemetry.CreateResource("my-resource", map[string]string{})
+
+			gotCmd, err := ParseCommand(tt.input)
+			assert.Nil(t, err, "ParseCommand should not return an error for input: %s", tt.input)
+			assert.NotNil(t, gotCmd.Send, "Send command should not be nil for input: %s", tt.input)
+			gotErr := gotCmd.Send.Validate()
+			assert.Equal(t, tt.want, gotErr, "Validate should return %v for input: %s", tt.want, tt.input)
+		})
+	}
+}
+
+func TestLetCommandValidate(t *testing.T) {
+	tests := []struct {
+		input string
+		want  error
+	}{
+		{
+			input: "let env = staging",
+			want:  nil,
+		},
+		{
+			input: `let route = "/cart"`,
+			want:  nil,
+		},
+		{
+			input: "let",
+			want:  errors.New("variable name must be specified for let command"),
+		},
+		{
+			input: "let env",
+			want:  errors.New("value of variable env must be specified with '='"),
+		},
+		{
+			input: "let my.env = staging",
+			want:  errors.New("invalid variable name my.env (letters, digits and underscores)"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			gotCmd, err := ParseCommand(tt.input)
+			assert.Nil(t, err, "ParseCommand should not return an error for input: %s", tt.input)
+			assert.NotNil(t, gotCmd.Let, "Let command should not be nil for input: %s", tt.input)
+			gotErr := gotCmd.Let.Validate()
+			assert.Equal(t, tt.want, gotErr, "Validate should return %v for input: %s", tt.want, tt.input)
+		})
+	}
+}
+
+func TestParseCommand_Interpolation(t *testing.T) {
+	t.Cleanup(resetVariables)
+	assert.NoError(t, SetVariable("env", "staging"))
+	assert.NoError(t, SetVariable("route", "/cart"))
+
+	got, err := ParseCommand(`create span "GET ${route}" in trace t-${env} attributes env=${env}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "GET /cart", *got.Create.Name)
+	assert.Equal(t, "t-staging", *got.Create.Trace)
+	assert.Equal(t, "staging", got.Create.Args[0].Attrs[0].Value)
+
+	_, err = ParseCommand("create span ${nope} in trace t")
+	assert.EqualError(t, err, "variable nope is not defined")
+}
+
+func TestDefineCommandValidate(t *testing.T) {
+	tests := []struct {
+		input string
+		want  error
+	}{
+		{
+			input: "define template http_handler(svc, route) {",
+			want:  nil,
+		},
+		{
+			input: "define template cache {",
+			want:  nil,
+		},
+		{
+			input: "define template",
+			want:  errors.New("template name must be specified for define command"),
+		},
+		{
+			input: "define template x(my.svc) {",
+			want:  errors.New("invalid parameter name my.svc of template x"),
+		},
+		{
+			input: "define template x(parent) {",
+			want:  errors.New("parameter name parent is reserved for the span given with 'under'"),
+		},
+		{
+			input: "define template x(svc)",
+			want:  errors.New("'{' must follow the parameters of template x"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			gotCmd, err := ParseCommand(tt.input)
+			assert.Nil(t, err, "ParseCommand should not return an error for input: %s", tt.input)
+			assert.NotNil(t, gotCmd.Define, "Define command should not be nil for input: %s", tt.input)
+			gotErr := gotCmd.Define.Validate()
+			assert.Equal(t, tt.want, gotErr, "Validate should return %v for input: %s", tt.want, tt.input)
+		})
+	}
+}
+
+func TestInstantiateCommandValidate(t *testing.T) {
+	tests := []struct {
+		input string
+		want  error
+	}{
+		{
+			input: "instantiate handler under my-span svc=cart route=/cart",
+			want:  nil,
+		},
+		{
+			input: "instantiate",
+			want:  errors.New("template name must be specified for instantiate command"),
+		},
+		{
+			input: "instantiate unknown",
+			want:  errors.New("template 'unknown' does not exist"),
+		},
+		{
+			input: "instantiate handler svc=cart route=/cart",
+			want:  errors.New("parent span must be specified with 'under' for template handler"),
+		},
+		{
+			input: "instantiate handler under unknown svc=cart route=/cart",
+			want:  errors.New("parent span 'unknown' does not exist"),
+		},
+		{
+			input: "instantiate handler under my-span svc=cart route=/cart db=postgres",
+			want:  errors.New("template handler has no parameter db"),
+		},
+		{
+			input: "instantiate handler under my-span svc=cart svc=user",
+			want:  errors.New("parameter svc is specified more than once"),
+		},
+		{
+			input: "instantiate handler under my-span svc=cart",
+			want:  errors.New("parameter route of template handler must be specified"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			telemetry.InitStore()
+			telemetry.CreateTrace("my-trace")
+			telemetry.AddSpanToTrace("my-trace", "my-span", map[string]string{})
+			assert.NoError(t, telemetry.DefineTemplate(telemetry.Template{
+				Name:     "handler",
+				Params:   []string{"svc", "route"},
+				Commands: []string{`create span "GET ${route}" with parent ${parent} resource ${svc}`},
+			}))
+
+			gotCmd, err := ParseCommand(tt.input)
+			assert.Nil(t, err, "ParseCommand should not return an error for input: %s", tt.input)
+			assert.NotNil(t, gotCmd.Instantiate, "Instantiate command should not be nil for input: %s", tt.input)
+			gotErr := gotCmd.Instantiate.Validate()
+			assert.Equal(t, tt.want, gotErr, "Validate should return %v for input: %s", tt.want, tt.input)
+		})
+	}
+}
+
+func TestTraceBlockCommandValidate(t *testing.T) {
+	tests := []struct {
+		input string
+		want  error
+	}{
+		{
+			input: `trace checkout { span "GET /cart" [resource=cart kind=server duration=100ms] { span db [db.system=postgres, id={{uuid}}] } }`,
+			want:  nil,
+		},
+		{
+			input: "trace empty { }",
+			want:  nil,
+		},
+		{
+			input: "trace",
+			want:  errors.New("trace name must be specified for trace command"),
+		},
+		{
+			input: "trace t { span a span b }",
+			want:  errors.New("trace t must have only one root span"),
+		},
+		{
+			input: "trace t { span a { span b [kind=server kind=client] } }",
+			want:  errors.New("property kind of span 'b' is specified more than once"),
+		},
+		{
+			input: "trace t { span a [duration=fast] }",
+			want:  fmt.Errorf("invalid duration of span 'a': %w", errors.New("invalid distribution fast")),
+		},
+		{
+			input: "trace t { span a [id={{nope}}] }",
+			want:  fmt.Errorf("invalid value of attribute 'id': %w", errors.New("unknown generator nope (uuid, seq, pick, rand, email, ipv4 or ipv6)")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			gotCmd, err := ParseCommand(tt.input)
+			assert.Nil(t, err, "ParseCommand should not return an error for input: %s", tt.input)
+			assert.NotNil(t, gotCmd.TraceBlock, "TraceBlock command should not be nil for input: %s", tt.input)
+			gotErr := gotCmd.TraceBlock.Validate()
+			assert.Equal(t, tt.want, gotErr, "Validate should return %v for input: %s", tt.want, tt.input)
+		})
+	}
+}
+
+func TestSpanSelectorValidate(t *testing.T) {
+	telemetry.InitStore()
+	telemetry.CreateResource("db", map[string]string{})
+	telemetry.CreateTrace("t")
+
+	tests := []struct {
+		input string
+		want  error
+	}{
+		{
+			input: `delete spans "GET /*" where resource=db and attr http.method=GET in trace t`,
+			want:  nil,
+		},
+		{
+			input: "delete spans",
+			want:  errors.New("span name pattern or conditions must be specified after spans (e.g. spans db_*, spans * or spans where resource=db)"),
+		},
+		{
+			input: "delete spans where resource=db and attr http.method=GET",
+			want:  nil,
+		},
+		{
+			input: "delete spans in trace t",
+			want:  nil,
+		},
+		{
+			input: `delete spans "where" in trace t`,
+			want:  nil,
+		},
+		{
+			input: "delete spans * where resource=nope",
+			want:  errors.New("resource 'nope' does not exist"),
+		},
+		{
+			input: "delete spans * in trace nope",
+			want:  errors.New("trace 'nope' does not exist"),
+		},
+		{
+			input: "delete spans * where resource=db and resource=db",
+			want:  errors.New("resource condition is specified more than once"),
+		},
+		{
+			input: "delete spans * where attr http.method=GET and attr http.method=POST",
+			want:  errors.New("attribute condition http.method is specified more than once"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			gotCmd, err := ParseCommand(tt.input)
+			assert.Nil(t, err, "ParseCommand should not return an error for input: %s", tt.input)
+			assert.NotNil(t, gotCmd.DeleteSpans, "DeleteSpans command should not be nil for input: %s", tt.input)
+			gotErr := gotCmd.DeleteSpans.Validate()
+			assert.Equal(t, tt.want, gotErr, "Validate should return %v for input: %s", tt.want, tt.input)
+		})
+	}
+}
+
+func TestSpanSelector_String(t *testing.T) {
+	cmd, err := ParseCommand(`set spans "GET *" where resource=db and attr http.method=GET in trace t name x dry-run`)
+	assert.NoError(t, err)
+	assert.Equal(t, "spans GET * where resource=db and attr http.method=GET in trace t", cmd.SetSpans.Spans.String())
+	assert.Equal(t, telemetry.SpanSelector{
+		Pattern:    "GET *",
+		Resource:   "db",
+		Attributes: map[string]string{"http.method": "GET"},
+		Trace:      "t",
+	}, cmd.SetSpans.Spans.Selector())
+	assert.True(t, cmd.SetSpans.DryRun)
+
+	cmd, err = ParseCommand("set spans where resource=db in trace t name x")
+	assert.NoError(t, err)
+	assert.Equal(t, "spans where resource=db in trace t", cmd.SetSpans.Spans.String())
+	assert.Nil(t, cmd.SetSpans.Spans.Pattern, "where should not be taken as the pattern")
+
+	cmd, err = ParseCommand(`delete spans "in" in trace t`)
+	assert.NoError(t, err)
+	assert.Equal(t, telemetry.SpanSelector{Pattern: "in", Attributes: map[string]string{}, Trace: "t"}, cmd.DeleteSpans.Spans.Selector())
+}
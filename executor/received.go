@@ -0,0 +1,168 @@
+package executor
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ymtdzzz/otelgen/receiver"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func handleReceivedCommand(cmd *ReceivedCommand) bool {
+	if err := cmd.Validate(); err != nil {
+		fmt.Printf("Error validating received command: %v\n", err)
+		return false
+	}
+
+	r := receiver.Default()
+	if r == nil {
+		fmt.Println("The receiver is not started (start otelgen with --receiver).")
+		return false
+	}
+
+	switch *cmd.Action {
+	case "list":
+		listReceived(r)
+	case "show":
+		if err := showReceived(r, *cmd.Trace); err != nil {
+			fmt.Printf("Error showing received trace: %v\n", err)
+			return false
+		}
+	case "clear":
+		r.Reset()
+		fmt.Println("Cleared received spans.")
+	}
+	return true
+}
+
+func listReceived(r *receiver.Receiver) {
+	traces := r.Traces()
+	if len(traces) == 0 {
+		fmt.Println("No traces received.")
+		return
+	}
+
+	fmt.Printf("Received traces: %d\n", len(traces))
+	fmt.Println("----------------------------------------")
+	for i, t := range traces {
+		roots := t.Roots()
+		if len(roots) == 0 {
+			fmt.Printf("#%d %s (no root span) %d spans\n", i+1, t.TraceID, len(t.Spans))
+			continue
+		}
+		root := roots[0]
+		fmt.Printf("#%d %s %s (%s) %d spans\n", i+1, t.TraceID, root.Name, root.Resource.ServiceName(), len(t.Spans))
+	}
+}
+
+func showReceived(r *receiver.Receiver, ref string) error {
+	t, err := findReceivedTrace(r.Traces(), ref)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Trace: %s\n", t.TraceID)
+	visited := make(map[trace.SpanID]bool, len(t.Spans))
+	for _, root := range t.Roots() {
+		printReceivedSpan(t, root, 1, visited)
+	}
+	if skipped := len(t.Spans) - len(visited); skipped > 0 {
+		fmt.Printf("  %d spans are not shown because their parents form a cycle or their span IDs are duplicated.\n", skipped)
+	}
+	return nil
+}
+
+// findReceivedTrace returns the trace at the 1-based index in received list when ref is #N,
+// or the trace with the ID prefix otherwise
+func findReceivedTrace(traces []*receiver.Trace, ref string) (*receiver.Trace, error) {
+	if index, ok := strings.CutPrefix(ref, "#"); ok {
+		i, err := strconv.Atoi(index)
+		if err != nil || i < 1 || i > len(traces) {
+			return nil, fmt.Errorf("no received trace %s (%d traces received)", ref, len(traces))
+		}
+		return traces[i-1], nil
+	}
+
+	ref = strings.ToLower(ref)
+	var matches []*receiver.Trace
+	for _, t := range traces {
+		if strings.HasPrefix(t.TraceID.String(), ref) {
+			matches = append(matches, t)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no received trace matches %s", ref)
+	case 1:
+		return matches[0], nil
+	}
+	return nil, fmt.Errorf("trace ID prefix %s matches %d traces", ref, len(matches))
+}
+
+// printReceivedSpan prints the span and its descendants. visited holds the span IDs already printed
+// so that a cycle in the parents of the received spans does not recurse forever.
+func printReceivedSpan(t *receiver.Trace, span *receiver.Span, depth int, visited map[trace.SpanID]bool) {
+	visited[span.SpanID] = true
+	indent := strings.Repeat("  ", depth)
+
+	fmt.Printf("%s- Span: %s (%s)\n", indent, span.Name, span.SpanID)
+	fmt.Printf("%s  Service: %s\n", indent, span.Resource.ServiceName())
+	if span.Kind != trace.SpanKindUnspecified && span.Kind != trace.SpanKindInternal {
+		fmt.Printf("%s  Kind: %s\n", indent, span.Kind)
+	}
+	fmt.Printf("%s  Duration: %s\n", indent, span.Duration())
+	if span.StatusCode != codes.Unset {
+		if span.StatusMessage != "" {
+			fmt.Printf("%s  Status: %s (%s)\n", indent, span.StatusCode, span.StatusMessage)
+		} else {
+			fmt.Printf("%s  Status: %s\n", indent, span.StatusCode)
+		}
+	}
+
+	printReceivedAttributes(span.Attributes, indent+"  ", "Attributes")
+
+	if len(span.Events) > 0 {
+		fmt.Printf("%s  Events:\n", indent)
+		for _, event := range span.Events {
+			fmt.Printf("%s    - %s\n", indent, event.Name)
+			printReceivedAttributes(event.Attributes, indent+"      ", "")
+		}
+	}
+
+	if len(span.Links) > 0 {
+		fmt.Printf("%s  Links:\n", indent)
+		for i, link := range span.Links {
+			fmt.Printf("%s    [%d] -> %s/%s\n", indent, i, link.TraceID, link.SpanID)
+			printReceivedAttributes(link.Attributes, indent+"        ", "")
+		}
+	}
+
+	for _, child := range t.Children(span) {
+		if visited[child.SpanID] {
+			continue
+		}
+		printReceivedSpan(t, child, depth+1, visited)
+	}
+}
+
+// printReceivedAttributes prints the attributes sorted by key under the title, or without a title when it is empty
+func printReceivedAttributes(attrs map[string]string, indent, title string) {
+	if len(attrs) == 0 {
+		return
+	}
+	if title != "" {
+		fmt.Printf("%s%s:\n", indent, title)
+		indent += "  "
+	}
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Printf("%s%s: %s\n", indent, key, attrs[key])
+	}
+}
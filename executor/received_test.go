@@ -0,0 +1,145 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/otelgen/receiver"
+	"github.com/ymtdzzz/otelgen/telemetry"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+func TestHandleReceivedCommand(t *testing.T) {
+	output := captureOutput(func() {
+		Executor("received list")
+	})
+	assert.Equal(t, "The receiver is not started (start otelgen with --receiver).\n", output)
+
+	r, err := receiver.Start("127.0.0.1:0", "")
+	if err != nil {
+		t.Fatalf("Failed to start receiver: %v", err)
+	}
+	receiver.SetDefault(r)
+	t.Cleanup(func() {
+		receiver.SetDefault(nil)
+		r.Shutdown(context.Background())
+	})
+
+	assert.NoError(t, telemetry.InitTracerManager(func() (sdktrace.SpanExporter, error) {
+		return otlptracegrpc.New(context.Background(),
+			otlptracegrpc.WithInsecure(),
+			otlptracegrpc.WithEndpoint(r.GRPCAddr()),
+		)
+	}, nil))
+	telemetry.InitStore()
+
+	output = captureOutput(func() {
+		Executor("received list")
+	})
+	assert.Equal(t, "No traces received.\n", output)
+
+	captureOutput(func() {
+		Executor("create resource cart")
+		Executor("create span checkout in trace t resource cart attributes http.status_code=500")
+		Executor("create span db with parent checkout")
+		Executor("send")
+	})
+
+	traces := r.Traces()
+	if !assert.Len(t, traces, 1) {
+		return
+	}
+	traceID := traces[0].TraceID.String()
+
+	output = captureOutput(func() {
+		Executor("received list")
+	})
+	assert.Equal(t, "Received traces: 1\n"+
+		"----------------------------------------\n"+
+		"#1 "+traceID+" checkout (cart) 2 spans\n", output)
+
+	for _, ref := range []string{"#1", traceID[:8]} {
+		output = captureOutput(func() {
+			Executor("received show " + ref)
+		})
+		assert.True(t, strings.HasPrefix(output, "Trace: "+traceID+"\n  - Span: checkout ("), output)
+		assert.Contains(t, output, "    Service: cart\n")
+		assert.Contains(t, output, "      http.status_code: 500\n")
+		assert.Contains(t, output, "    - Span: db (")
+	}
+
+	output = captureOutput(func() {
+		Executor("received show #2")
+	})
+	assert.Equal(t, "Error showing received trace: no received trace #2 (1 traces received)\n", output)
+
+	// a numeric ref is always a trace ID prefix, never an index
+	prefix := "1"
+	if strings.HasPrefix(traceID, prefix) {
+		prefix = "2"
+	}
+	output = captureOutput(func() {
+		Executor("received show " + prefix)
+	})
+	assert.Equal(t, "Error showing received trace: no received trace matches "+prefix+"\n", output)
+
+	output = captureOutput(func() {
+		Executor("received clear")
+	})
+	assert.Equal(t, "Cleared received spans.\n", output)
+	assert.Empty(t, r.Spans())
+}
+
+func TestHandleReceivedCommand_CyclicParents(t *testing.T) {
+	r, err := receiver.Start("127.0.0.1:0", "")
+	if err != nil {
+		t.Fatalf("Failed to start receiver: %v", err)
+	}
+	receiver.SetDefault(r)
+	t.Cleanup(func() {
+		receiver.SetDefault(nil)
+		r.Shutdown(context.Background())
+	})
+
+	selfID := bytes.Repeat([]byte{1}, 16)
+	mixedID := bytes.Repeat([]byte{2}, 16)
+	r.Export(&coltracepb.ExportTraceServiceRequest{
+		ResourceSpans: []*tracepb.ResourceSpans{{
+			ScopeSpans: []*tracepb.ScopeSpans{{
+				Spans: []*tracepb.Span{
+					{TraceId: selfID, SpanId: []byte{1, 1, 1, 1, 1, 1, 1, 1}, ParentSpanId: []byte{1, 1, 1, 1, 1, 1, 1, 1}, Name: "loop"},
+					{TraceId: mixedID, SpanId: []byte{2, 2, 2, 2, 2, 2, 2, 2}, Name: "root"},
+					{TraceId: mixedID, SpanId: []byte{3, 3, 3, 3, 3, 3, 3, 3}, ParentSpanId: []byte{3, 3, 3, 3, 3, 3, 3, 3}, Name: "loop"},
+				},
+			}},
+		}},
+	})
+
+	output := captureOutput(func() {
+		Executor("received list")
+	})
+	assert.Equal(t, "Received traces: 2\n"+
+		"----------------------------------------\n"+
+		"#1 "+trace.TraceID(selfID).String()+" (no root span) 1 spans\n"+
+		"#2 "+trace.TraceID(mixedID).String()+" root () 2 spans\n", output)
+
+	output = captureOutput(func() {
+		Executor("received show #1")
+	})
+	assert.Equal(t, "Trace: "+trace.TraceID(selfID).String()+"\n"+
+		"  1 spans are not shown because their parents form a cycle or their span IDs are duplicated.\n", output)
+
+	output = captureOutput(func() {
+		Executor("received show #2")
+	})
+	assert.Contains(t, output, "  - Span: root (")
+	assert.NotContains(t, output, "Span: loop")
+	assert.True(t, strings.HasSuffix(output, "  1 spans are not shown because their parents form a cycle or their span IDs are duplicated.\n"), output)
+}
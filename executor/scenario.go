@@ -0,0 +1,41 @@
+package executor
+
+import (
+	"fmt"
+
+	"github.com/ymtdzzz/otelgen/telemetry"
+)
+
+func handleLoadCommand(cmd *LoadCommand) bool {
+	if err := cmd.Validate(); err != nil {
+		fmt.Printf("Error validating load command: %v\n", err)
+		return false
+	}
+
+	sc, err := telemetry.ReadScenarioFile(*cmd.Path)
+	if err != nil {
+		fmt.Printf("Error loading scenario: %v\n", err)
+		return false
+	}
+	if err := telemetry.LoadScenario(sc); err != nil {
+		fmt.Printf("Error loading scenario: %v\n", err)
+		return false
+	}
+	fmt.Printf("Loaded %d traces from %s.\n", len(sc.Traces), *cmd.Path)
+	return true
+}
+
+func handleSaveCommand(cmd *SaveCommand) bool {
+	if err := cmd.Validate(); err != nil {
+		fmt.Printf("Error validating save command: %v\n", err)
+		return false
+	}
+
+	sc := telemetry.GetScenario()
+	if err := sc.WriteFile(*cmd.Path); err != nil {
+		fmt.Printf("Error saving scenario: %v\n", err)
+		return false
+	}
+	fmt.Printf("Saved %d traces to %s.\n", len(sc.Traces), *cmd.Path)
+	return true
+}
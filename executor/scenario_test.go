@@ -0,0 +1,49 @@
+package executor
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/otelgen/telemetry"
+)
+
+func TestHandleLoadSaveCommand(t *testing.T) {
+	telemetry.InitStore()
+	path := filepath.Join(t.TempDir(), "scenario.yaml")
+
+	captureOutput(func() {
+		Executor("create resource cart")
+		Executor("create span checkout in trace t resource cart attributes http.status_code=500")
+		Executor("create span db with parent checkout")
+	})
+
+	output := captureOutput(func() {
+		Executor("save \"" + path + "\"")
+	})
+	assert.Equal(t, "Saved 1 traces to "+path+".\n", output)
+	saved := telemetry.GetScenario()
+
+	telemetry.InitStore()
+	output = captureOutput(func() {
+		Executor("load \"" + path + "\"")
+	})
+	assert.Equal(t, "Loaded 1 traces from "+path+".\n", output)
+	assert.Equal(t, saved, telemetry.GetScenario())
+
+	output = captureOutput(func() {
+		Executor("undo")
+	})
+	assert.Equal(t, "Undone: load \""+path+"\"\n", output)
+	assert.Empty(t, telemetry.GetTraces())
+
+	output = captureOutput(func() {
+		Executor("load missing.yaml")
+	})
+	assert.Equal(t, "Error loading scenario: open missing.yaml: no such file or directory\n", output)
+
+	output = captureOutput(func() {
+		Executor("load")
+	})
+	assert.Equal(t, "Error validating load command: file path must be specified for load command\n", output)
+}
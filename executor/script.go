@@ -0,0 +1,47 @@
+package executor
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// RunScript executes the commands of the script line by line as if they were typed in the prompt.
+// Empty lines and lines starting with '#' are skipped, and the script stops at the exit command.
+// After running all the lines, it returns an error if any command failed, e.g. it could not be parsed,
+// creating or changing the telemetry failed, an expectation was not met or the lint found errors.
+func RunScript(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	failures := 0
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if cmd, err := ParseCommand(line); err == nil && cmd.Exit != nil {
+			break
+		}
+		fmt.Printf("otelgen> %s\n", line)
+		if !execute(line) {
+			failures++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if pendingTemplate != nil {
+		fmt.Printf("Error defining template: template %s is not closed with }\n", pendingTemplate.Name)
+		pendingTemplate = nil
+		failures++
+	}
+	if pendingBlock != nil {
+		fmt.Println("Error parsing command: trace block is not closed with }")
+		pendingBlock = nil
+		failures++
+	}
+	if failures > 0 {
+		return fmt.Errorf("%d command(s) failed", failures)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package executor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/otelgen/telemetry"
+)
+
+func TestRunScript(t *testing.T) {
+	assert.NoError(t, telemetry.InitTracerManager(telemetry.EnableMemoryExporter(), nil))
+
+	tests := []struct {
+		name    string
+		script  string
+		wantErr string
+	}{
+		{
+			name: "Passing expectations",
+			script: `# checkout flow
+create span checkout in trace t
+
+send
+expect trace t spans=1
+exit
+expect trace t spans=5
+`,
+		},
+		{
+			name: "Failures",
+			script: `create span checkout in trace t
+send
+expect trace t spans=5
+unknown command
+expect span checkout has attribute x
+`,
+			wantErr: "3 command(s) failed",
+		},
+		{
+			name: "Failing commands",
+			script: `create span checkout in trace t
+create span db with parent checkout
+create span cache in trace t resource missing
+set span unknown name x
+move span checkout under db
+delete span missing
+load testdata/missing.yaml
+send
+`,
+			wantErr: "5 command(s) failed",
+		},
+		{
+			name: "Unclosed template",
+			script: `create span checkout in trace t
+send
+define template handler() {
+create span db with parent ${parent}
+`,
+			wantErr: "1 command(s) failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			telemetry.InitStore()
+			var err error
+			output := captureOutput(func() {
+				err = RunScript(strings.NewReader(tt.script))
+			})
+			assert.Contains(t, output, "otelgen> send\n")
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotContains(t, output, "spans=5", "Lines after exit should not be run")
+		})
+	}
+}
@@ -1,7 +1,35 @@
 package executor
 
-import "github.com/ymtdzzz/otelgen/telemetry"
+import (
+	"fmt"
 
-func handleSendCommand() {
-	telemetry.SendAllTraces()
+	"github.com/ymtdzzz/otelgen/telemetry"
+)
+
+// handleSendCommand sends all the traces and reports false when --lint finds errors,
+// in which case nothing is sent
+func handleSendCommand(cmd *SendCommand) bool {
+	if err := cmd.Validate(); err != nil {
+		fmt.Printf("Error validating send command: %v\n", err)
+		return false
+	}
+
+	if cmd.Lint && !reportLintFindings(telemetry.Lint()) {
+		fmt.Println("Nothing is sent, fix the lint errors first")
+		return false
+	}
+
+	var opts []telemetry.SendOption
+	if cmd.Seed != nil {
+		opts = append(opts, telemetry.WithSeed(*cmd.Seed))
+	}
+	if len(cmd.Errors) > 0 {
+		injections := make([]telemetry.ErrorInjection, 0, len(cmd.Errors))
+		for _, arg := range cmd.Errors {
+			injections = append(injections, arg.injection())
+		}
+		opts = append(opts, telemetry.WithErrors(injections...))
+	}
+	telemetry.SendAllTraces(opts...)
+	return true
 }
@@ -6,38 +6,59 @@ import (
 	"github.com/ymtdzzz/otelgen/telemetry"
 )
 
-func handleSetCommand(cmd *SetCommand) {
+func handleSetCommand(cmd *SetCommand) bool {
 	if err := cmd.Validate(); err != nil {
 		fmt.Printf("Error validating set command: %v\n", err)
-		return
+		return false
 	}
+	if err := runSetCommand(cmd); err != nil {
+		fmt.Printf("Error %v\n", err)
+		return false
+	}
+	return true
+}
 
+// runSetCommand runs the validated set command and returns what failed
+func runSetCommand(cmd *SetCommand) error {
+	var err error
 	switch *cmd.Type {
 	case "span":
-		if err := handleSetSpan(cmd); err != nil {
-			fmt.Printf("Error setting span: %v\n", err)
-		}
+		err = handleSetSpan(cmd)
 	case "resource":
-		if err := handleSetResource(cmd); err != nil {
-			fmt.Printf("Error setting resource: %v\n", err)
-		}
+		err = handleSetResource(cmd)
 	case "event":
-		if err := handleSetEvent(cmd); err != nil {
-			fmt.Printf("Error setting event: %v\n", err)
-		}
+		err = handleSetEvent(cmd)
+	case "trace":
+		err = handleSetTrace(cmd)
 	default:
-		fmt.Printf("Unknown target type for set command: %s\n", *cmd.Type)
+		return fmt.Errorf("unknown target type for set command: %s", *cmd.Type)
+	}
+	if err != nil {
+		return fmt.Errorf("setting %s: %w", *cmd.Type, err)
 	}
+	return nil
 }
 
 func handleSetSpan(cmd *SetCommand) error {
+	if err := updateSpan(*cmd.Name, cmd.Args); err != nil {
+		return err
+	}
+	fmt.Printf("Updated span\n")
+
+	return nil
+}
+
+// updateSpan applies the arguments of a set command to the span
+func updateSpan(ref string, args []*SetArg) error {
 	var (
 		newName      string
 		resourceName string
 		attributes   map[string]string
+		duration     *string
+		kind         *string
 	)
 
-	for _, arg := range cmd.Args {
+	for _, arg := range args {
 		if arg.SetCreateArg != nil {
 			if arg.SetCreateArg.Resource != nil {
 				resourceName = *arg.SetCreateArg.Resource
@@ -45,6 +66,12 @@ func handleSetSpan(cmd *SetCommand) error {
 			if len(arg.SetCreateArg.Attrs) > 0 {
 				attributes = convertKeyValuesToMap(arg.SetCreateArg.Attrs)
 			}
+			if arg.SetCreateArg.Duration != nil {
+				duration = arg.SetCreateArg.Duration
+			}
+			if arg.SetCreateArg.Kind != nil {
+				kind = arg.SetCreateArg.Kind
+			}
 		}
 		if arg.SetOnlyArg != nil {
 			if arg.SetOnlyArg.Name != nil {
@@ -53,18 +80,35 @@ func handleSetSpan(cmd *SetCommand) error {
 		}
 	}
 
-	if _, err := telemetry.UpdateSpan(*cmd.Name, newName, resourceName, attributes); err != nil {
+	span, err := telemetry.UpdateSpan(ref, newName, resourceName, attributes)
+	if err != nil {
 		return err
 	}
-	fmt.Printf("Updated span\n")
+	if add, remove := collectPatchAttrs(patchAttrsArgs(args)); len(add) > 0 || len(remove) > 0 {
+		if _, err := telemetry.PatchSpanAttributes(span.Handle, add, remove); err != nil {
+			return err
+		}
+	}
+	if duration != nil {
+		if _, err := setSpanDuration(span.Handle, *duration); err != nil {
+			return err
+		}
+	}
+	if kind != nil {
+		if err := setSpanKind(span.Handle, *kind); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
 func handleSetResource(cmd *SetCommand) error {
 	var (
-		newName    string
-		attributes map[string]string
+		newName        string
+		attributes     map[string]string
+		semconvVersion string
+		mergeDefaults  *bool
 	)
 
 	for _, arg := range cmd.Args {
@@ -72,6 +116,12 @@ func handleSetResource(cmd *SetCommand) error {
 			if len(arg.SetCreateArg.Attrs) > 0 {
 				attributes = convertKeyValuesToMap(arg.SetCreateArg.Attrs)
 			}
+			if arg.SetCreateArg.Semconv != nil {
+				semconvVersion = *arg.SetCreateArg.Semconv
+			}
+			if arg.SetCreateArg.Defaults != nil {
+				mergeDefaults = arg.SetCreateArg.mergeDefaults()
+			}
 		}
 		if arg.SetOnlyArg != nil {
 			if arg.SetOnlyArg.Name != nil {
@@ -80,9 +130,18 @@ func handleSetResource(cmd *SetCommand) error {
 		}
 	}
 
-	if _, err := telemetry.UpdateResource(*cmd.Name, newName, attributes); err != nil {
+	resource, err := telemetry.UpdateResource(*cmd.Name, newName, attributes)
+	if err != nil {
 		return err
 	}
+	if _, err := telemetry.SetResourceSemantics(resource.Name, semconvVersion, mergeDefaults); err != nil {
+		return err
+	}
+	if add, remove := cmd.PatchAttrs(); len(add) > 0 || len(remove) > 0 {
+		if _, err := telemetry.PatchResourceAttributes(resource.Name, add, remove); err != nil {
+			return err
+		}
+	}
 	fmt.Printf("Updated resource: %s with new name: %s\n", *cmd.Name, newName)
 
 	return nil
@@ -107,10 +166,77 @@ func handleSetEvent(cmd *SetCommand) error {
 		}
 	}
 
-	if _, err := telemetry.UpdateEvent(*cmd.Name, newName, attributes); err != nil {
+	event, err := telemetry.UpdateEvent(*cmd.Name, newName, attributes)
+	if err != nil {
 		return err
 	}
+	if add, remove := cmd.PatchAttrs(); len(add) > 0 || len(remove) > 0 {
+		if _, err := telemetry.PatchEventAttributes(event.Name, add, remove); err != nil {
+			return err
+		}
+	}
 	fmt.Printf("Updated event: %s with new name: %s\n", *cmd.Name, newName)
 
 	return nil
 }
+
+func handleSetTrace(cmd *SetCommand) error {
+	var (
+		inherit    *bool
+		hasInherit bool
+	)
+
+	for _, arg := range cmd.Args {
+		if arg.SetOnlyArg != nil && arg.SetOnlyArg.InheritResource != nil {
+			inherit = arg.SetOnlyArg.inheritResource()
+			hasInherit = true
+		}
+	}
+
+	if !hasInherit {
+		return nil
+	}
+
+	trace, err := telemetry.SetTraceInheritResource(*cmd.Name, inherit)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Updated trace: %s with resource inheritance: %s\n", trace.Name, onOff(trace.ShouldInheritResource(telemetry.GetOptions())))
+
+	return nil
+}
+
+func handleSetLinkCommand(cmd *SetLinkCommand) bool {
+	if err := cmd.Validate(); err != nil {
+		fmt.Printf("Error validating set link command: %v\n", err)
+		return false
+	}
+	if err := runSetLinkCommand(cmd); err != nil {
+		fmt.Printf("Error %v\n", err)
+		return false
+	}
+	return true
+}
+
+// runSetLinkCommand runs the validated set link command and returns what failed
+func runSetLinkCommand(cmd *SetLinkCommand) error {
+	var (
+		updated int
+		err     error
+	)
+	if cmd.HasArgAttrs() {
+		for _, arg := range cmd.Args {
+			if len(arg.Attrs) > 0 {
+				updated, err = telemetry.SetLinkAttributes(cmd.LinkRef(), convertKeyValuesToMap(arg.Attrs))
+			}
+		}
+	} else {
+		add, remove := cmd.PatchAttrs()
+		updated, err = telemetry.PatchLinkAttributes(cmd.LinkRef(), add, remove)
+	}
+	if err != nil {
+		return fmt.Errorf("setting link: %w", err)
+	}
+	fmt.Printf("Updated %s\n", describeLinks(cmd.LinkRef(), updated))
+	return nil
+}
@@ -3,6 +3,7 @@ package executor
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/ymtdzzz/otelgen/telemetry"
@@ -20,16 +21,40 @@ func TestHandleSetSpan_OK(t *testing.T) {
 
 	handleSetCommand(cmd.Set)
 
-	_, exists := telemetry.GetSpans()["my-span"]
+	_, exists := telemetry.GetSpans()["my-trace/my-span"]
 	assert.False(t, exists)
 
-	span, exists := telemetry.GetSpans()["new-span-name"]
+	span, exists := telemetry.GetSpans()["my-trace/new-span-name"]
 	assert.True(t, exists)
 	assert.Equal(t, "new-span-name", span.Name, "Span name should match")
 	assert.Equal(t, "my-resource", span.Resource.Name, "Span resource should match")
 	assert.Equal(t, map[string]string{"key": "value", "http.method": "GET"}, span.Attributes, "Span attributes should match")
 }
 
+func TestHandleSetSpan_Duration(t *testing.T) {
+	telemetry.InitStore()
+	telemetry.CreateTrace("my-trace")
+	telemetry.AddSpanToTrace("my-trace", "my-span", map[string]string{})
+
+	output := captureOutput(func() {
+		Executor("set span my-span duration 250ms")
+	})
+
+	assert.Equal(t, "Updated span\n", output)
+	span := telemetry.GetSpans()["my-trace/my-span"]
+	assert.Equal(t, &telemetry.Distribution{Kind: "fixed", Params: []time.Duration{250 * time.Millisecond}}, span.Duration)
+
+	output = captureOutput(func() {
+		Executor("list traces")
+	})
+	assert.Contains(t, output, "  Duration: fixed(250ms)\n")
+
+	captureOutput(func() {
+		Executor("undo")
+	})
+	assert.Nil(t, telemetry.GetSpans()["my-trace/my-span"].Duration, "Undo should remove the duration")
+}
+
 func TestHandleSetSpan_NonExistingResource(t *testing.T) {
 	telemetry.InitStore()
 	telemetry.CreateResource("my-resource", map[string]string{})
@@ -66,6 +91,29 @@ func TestHandleSetResource_OK(t *testing.T) {
 	assert.Equal(t, map[string]string{"key": "value"}, resource.Attributes, "Resource attributes should match")
 }
 
+func TestHandleSetResource_Semantics(t *testing.T) {
+	telemetry.InitStore()
+	telemetry.CreateResource("my-resource", map[string]string{})
+
+	cmd, err := ParseCommand("set resource my-resource semconv v1.4.0 defaults on")
+	assert.Nil(t, err, "ParseCommand should not return an error")
+	assert.NotNil(t, cmd.Set, "Set command should not be nil")
+
+	handleSetCommand(cmd.Set)
+
+	resource := telemetry.GetResources()["my-resource"]
+	assert.Equal(t, "v1.4.0", resource.SemconvVersion)
+	assert.True(t, resource.MergeDefaults)
+
+	cmd, err = ParseCommand("set resource my-resource defaults off")
+	assert.Nil(t, err, "ParseCommand should not return an error")
+
+	handleSetCommand(cmd.Set)
+
+	assert.Equal(t, "v1.4.0", resource.SemconvVersion, "Semconv version should be kept")
+	assert.False(t, resource.MergeDefaults)
+}
+
 func TestHandleSetEvent_OK(t *testing.T) {
 	telemetry.InitStore()
 	telemetry.CreateEvent("my-event", map[string]string{})
@@ -85,6 +133,81 @@ func TestHandleSetEvent_OK(t *testing.T) {
 	assert.Equal(t, map[string]string{"key": "value"}, event.Attributes, "Event attributes should match")
 }
 
+func TestHandleSetTrace_InheritResource(t *testing.T) {
+	telemetry.InitStore()
+	trace := telemetry.CreateTrace("my-trace")
+
+	cmd, err := ParseCommand("set trace my-trace inherit-resource on")
+	assert.Nil(t, err, "ParseCommand should not return an error")
+	assert.NotNil(t, cmd.Set, "Set command should not be nil")
+
+	output := captureOutput(func() {
+		handleSetCommand(cmd.Set)
+	})
+
+	assert.Equal(t, "Updated trace: my-trace with resource inheritance: on\n", output)
+	assert.True(t, trace.ShouldInheritResource(telemetry.GetOptions()))
+
+	cmd, err = ParseCommand("set trace my-trace inherit-resource default")
+	assert.Nil(t, err, "ParseCommand should not return an error")
+
+	handleSetCommand(cmd.Set)
+
+	assert.Nil(t, trace.InheritResource, "Trace should follow the global option")
+}
+
+func TestHandleSetCommand_PatchAttributes(t *testing.T) {
+	telemetry.InitStore()
+	telemetry.CreateTrace("my-trace")
+	telemetry.AddSpanToTrace("my-trace", "my-span", map[string]string{"a": "1", "b": "2", "c": "3"})
+	telemetry.CreateResource("my-resource", map[string]string{"a": "1"})
+	telemetry.CreateEvent("my-event", map[string]string{"a": "1", "b": "2"})
+
+	captureOutput(func() {
+		Executor("set span my-span name new-span add-attributes b=bb,d=dd remove-attributes c")
+		Executor("set resource my-resource add-attributes b=bb")
+		Executor("set event my-event remove-attributes a,b")
+	})
+
+	span := telemetry.GetSpans()["my-trace/new-span"]
+	assert.Equal(t, map[string]string{"a": "1", "b": "bb", "d": "dd"}, span.Attributes)
+	assert.Equal(t, map[string]string{"a": "1", "b": "bb"}, telemetry.GetResources()["my-resource"].Attributes)
+	assert.Empty(t, telemetry.GetEvents()["my-event"].Attributes)
+}
+
+func TestHandleSetLink(t *testing.T) {
+	telemetry.InitStore()
+	telemetry.CreateTrace("my-trace")
+	telemetry.AddSpanToTrace("my-trace", "my-span", map[string]string{})
+	telemetry.AddSpanToSpan("my-span", "target-span", map[string]string{})
+	telemetry.AddLinkToSpan("my-span", "target-span", map[string]string{"a": "1", "b": "2"})
+
+	output := captureOutput(func() {
+		Executor("set link my-span target-span add-attributes c=cc remove-attributes a")
+	})
+	assert.Equal(t, "Updated 1 link(s) from 'my-span' to 'target-span'\n", output)
+	link := telemetry.GetSpans()["my-trace/my-span"].Links[0]
+	assert.Equal(t, map[string]string{"b": "2", "c": "cc"}, link.Attributes)
+
+	captureOutput(func() {
+		Executor("set link my-span target-span attributes x=xx")
+	})
+	assert.Equal(t, map[string]string{"x": "xx"}, link.Attributes)
+
+	telemetry.AddLinkToSpan("my-span", "target-span", map[string]string{})
+	output = captureOutput(func() {
+		Executor("set link my-span 1 add-attributes y=yy")
+	})
+	assert.Equal(t, "Updated link 1 of 'my-span'\n", output)
+	assert.Equal(t, map[string]string{"x": "xx"}, link.Attributes)
+	assert.Equal(t, map[string]string{"y": "yy"}, telemetry.GetSpans()["my-trace/my-span"].Links[1].Attributes)
+
+	output = captureOutput(func() {
+		Executor("set link target-span my-span attributes x=xx")
+	})
+	assert.Equal(t, "Error setting link: no link from span target-span to span my-span\n", output)
+}
+
 func TestHandleSetCommand_ValidateError(t *testing.T) {
 	telemetry.InitStore()
 
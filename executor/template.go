@@ -0,0 +1,230 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ymtdzzz/otelgen/telemetry"
+)
+
+// templateParentParam is the parameter set to the handle of the span given with 'under',
+// so that the commands of a template can create the spans under it, e.g.
+//
+//	define template http_handler(svc, route) {
+//	  create span "GET ${route}" with parent ${parent} resource ${svc}
+//	  create span auth with parent "GET ${route}"
+//	}
+//
+// The span names in the commands refer to the spans created by the same instantiation,
+// so a template can be instantiated many times even though the names are shared.
+const templateParentParam = "parent"
+
+// templateCommands are the commands which can be used in a template
+var templateCommands = []string{"create", "set", "add", "delete", "move", "clone"}
+
+// pendingTemplate is the template being defined, whose commands are read line by line until }
+var pendingTemplate *telemetry.Template
+
+// LivePrefix returns the prompt prefix while a template or a trace block continues on the next lines,
+// or the prefix with the current workspace when it is not the default one or there are other workspaces
+func LivePrefix() (string, bool) {
+	if pendingTemplate != nil || pendingBlock != nil {
+		return "... ", true
+	}
+	if telemetry.Workspace() != telemetry.DefaultWorkspace || len(telemetry.Workspaces()) > 1 {
+		return fmt.Sprintf("otelgen [%s]> ", telemetry.Workspace()), true
+	}
+	return "", false
+}
+
+func handleDefineCommand(cmd *DefineCommand) bool {
+	if err := cmd.Validate(); err != nil {
+		fmt.Printf("Error validating define command: %v\n", err)
+		return false
+	}
+	pendingTemplate = &telemetry.Template{Name: *cmd.Name, Params: cmd.Params}
+	return true
+}
+
+// defineTemplateLine adds the line to the template being defined, or defines the template at }
+func defineTemplateLine(line string) bool {
+	if strings.HasPrefix(line, "#") {
+		return true
+	}
+	if line == "}" {
+		t := *pendingTemplate
+		pendingTemplate = nil
+		if err := telemetry.DefineTemplate(t); err != nil {
+			fmt.Printf("Error defining template: %v\n", err)
+			return false
+		}
+		fmt.Printf("Defined template %s(%s) with %d commands\n", t.Name, strings.Join(t.Params, ", "), len(t.Commands))
+		return true
+	}
+
+	first, _, _ := strings.Cut(line, " ")
+	if !slices.Contains(templateCommands, first) {
+		fmt.Println("Error defining template: only create, set, add, delete, move and clone commands can be used in a template")
+		return false
+	}
+	pendingTemplate.Commands = append(pendingTemplate.Commands, line)
+	return true
+}
+
+func templateUsesParent(t *telemetry.Template) bool {
+	for _, c := range t.Commands {
+		for _, m := range variableRefPattern.FindAllStringSubmatch(c, -1) {
+			if m[1] == templateParentParam {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func handleInstantiateCommand(cmd *InstantiateCommand) bool {
+	if err := cmd.Validate(); err != nil {
+		fmt.Printf("Error validating instantiate command: %v\n", err)
+		return false
+	}
+
+	t := telemetry.GetTemplates()[*cmd.Name]
+	params := convertKeyValuesToMap(cmd.Args)
+	if cmd.Parent != nil {
+		parent, _ := telemetry.LookupSpan(*cmd.Parent)
+		params[templateParentParam] = parent.Handle
+	}
+
+	// check all the commands before running any of them
+	lines := make([]string, len(t.Commands))
+	for i, c := range t.Commands {
+		line, err := expandTemplateCommand(c, params)
+		if err == nil {
+			_, err = parser.ParseString("", line)
+		}
+		if err != nil {
+			fmt.Printf("Error expanding template %s: %s: %v\n", t.Name, c, err)
+			return false
+		}
+		lines[i] = line
+	}
+
+	// locals are the handles of the spans created by this instantiation by name
+	locals := make(map[string]string)
+	created := 0
+	err := telemetry.Atomically(func() error {
+		for _, line := range lines {
+			c, err := parser.ParseString("", line)
+			if err != nil {
+				return fmt.Errorf("%s: %w", line, err)
+			}
+			rewriteSpanRefs(c, locals)
+			before := telemetry.GetSpans()
+			if err := runTemplateCommand(c); err != nil {
+				return fmt.Errorf("%s: %w", line, err)
+			}
+			for handle, span := range telemetry.GetSpans() {
+				if _, exists := before[handle]; !exists {
+					locals[span.Name] = handle
+					created++
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("Error instantiating template %s, no spans are created: %v\n", t.Name, err)
+		return false
+	}
+	fmt.Printf("Instantiated template %s with %d spans\n", t.Name, created)
+	return true
+}
+
+// expandTemplateCommand replaces the parameters and then the variables in the command
+func expandTemplateCommand(command string, params map[string]string) (string, error) {
+	command = variableRefPattern.ReplaceAllStringFunc(command, func(ref string) string {
+		if value, ok := params[variableRefPattern.FindStringSubmatch(ref)[1]]; ok {
+			return value
+		}
+		return ref
+	})
+	return interpolate(command)
+}
+
+// rewriteSpanRefs replaces the span references of the command which refer to the spans
+// created by the same instantiation with their handles. Only the arguments which refer to
+// a span are rewritten, so the names of new spans, resources, events, traces and clone
+// prefixes are kept even when they are the same as a span name.
+func rewriteSpanRefs(cmd *Command, locals map[string]string) {
+	for _, ref := range spanRefs(cmd) {
+		if ref == nil {
+			continue
+		}
+		if handle, ok := locals[*ref]; ok {
+			*ref = handle
+		}
+	}
+}
+
+// spanRefs returns the arguments of the command which refer to a span
+func spanRefs(cmd *Command) []*string {
+	switch {
+	case cmd.Create != nil:
+		return []*string{cmd.Create.ParentSpan}
+	case cmd.SetLink != nil:
+		return []*string{cmd.SetLink.From, cmd.SetLink.To}
+	case cmd.Set != nil:
+		if cmd.Set.Type != nil && *cmd.Set.Type == "span" {
+			return []*string{cmd.Set.Name}
+		}
+	case cmd.AddLink != nil:
+		return []*string{cmd.AddLink.From, cmd.AddLink.To}
+	case cmd.AddEvent != nil:
+		return []*string{cmd.AddEvent.SpanName}
+	case cmd.Delete != nil:
+		if cmd.Delete.Type != nil && (*cmd.Delete.Type == "span" || *cmd.Delete.Type == "link") {
+			return []*string{cmd.Delete.Name, cmd.Delete.To}
+		}
+	case cmd.Move != nil:
+		return []*string{cmd.Move.Name, cmd.Move.Parent}
+	case cmd.Clone != nil:
+		return []*string{cmd.Clone.Name, cmd.Clone.Parent}
+	}
+	return nil
+}
+
+// runTemplateCommand validates and runs a command of a template. The commands are run
+// directly as the whole instantiation is tracked as one change, and the error is returned
+// so that the instantiation is rolled back.
+func runTemplateCommand(cmd *Command) error {
+	var (
+		v   interface{ Validate() error }
+		run func() error
+	)
+	switch {
+	case cmd.Create != nil:
+		v, run = cmd.Create, func() error { return runCreateCommand(cmd.Create) }
+	case cmd.SetLink != nil:
+		v, run = cmd.SetLink, func() error { return runSetLinkCommand(cmd.SetLink) }
+	case cmd.Set != nil:
+		v, run = cmd.Set, func() error { return runSetCommand(cmd.Set) }
+	case cmd.AddLink != nil:
+		v, run = cmd.AddLink, func() error { return runAddLinkCommand(cmd.AddLink) }
+	case cmd.AddEvent != nil:
+		v, run = cmd.AddEvent, func() error { return runAddEventCommand(cmd.AddEvent) }
+	case cmd.Delete != nil:
+		v, run = cmd.Delete, func() error { return runDeleteCommand(cmd.Delete) }
+	case cmd.Move != nil:
+		v, run = cmd.Move, func() error { return runMoveCommand(cmd.Move) }
+	case cmd.Clone != nil:
+		v, run = cmd.Clone, func() error { return runCloneCommand(cmd.Clone) }
+	default:
+		return errors.New("only create, set, add, delete, move and clone commands can be used in a template")
+	}
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	return run()
+}
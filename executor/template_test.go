@@ -0,0 +1,209 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/otelgen/telemetry"
+)
+
+func TestHandleDefineAndInstantiate(t *testing.T) {
+	telemetry.InitStore()
+	t.Cleanup(func() {
+		pendingTemplate = nil
+	})
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{
+			input: "define template http_handler(svc, route) {",
+			want:  "",
+		},
+		{
+			input: `create span "GET ${route}" with parent ${parent} resource ${svc}`,
+			want:  "",
+		},
+		{
+			input: "# the children of the handler",
+			want:  "",
+		},
+		{
+			input: `create span auth with parent "GET ${route}"`,
+			want:  "",
+		},
+		{
+			input: "send",
+			want:  "Error defining template: only create, set, add, delete, move and clone commands can be used in a template\n",
+		},
+		{
+			input: "}",
+			want:  "Defined template http_handler(svc, route) with 2 commands\n",
+		},
+		{
+			input: "create resource cart",
+			want:  "Created resource: cart with attributes: map[]\n",
+		},
+		{
+			input: "create span gateway in trace t",
+			want:  "Created trace: t\nCreated span: gateway in trace: t\n",
+		},
+		{
+			input: "instantiate http_handler under gateway svc=cart route=/cart",
+			want: "Created span: GET /cart with parent span: t/gateway\n" +
+				"Set resource cart to span GET /cart\n" +
+				"Created span: auth with parent span: t/GET__cart\n" +
+				"Instantiated template http_handler with 2 spans\n",
+		},
+		{
+			input: `instantiate http_handler under gateway svc=cart route="/cart"`,
+			want: "Created span: GET /cart with parent span: t/gateway\n" +
+				"Set resource cart to span GET /cart\n" +
+				"Created span: auth with parent span: t/GET__cart-2\n" +
+				"Instantiated template http_handler with 2 spans\n",
+		},
+		{
+			input: "undo",
+			want:  "Undone: instantiate http_handler under gateway svc=cart route=\"/cart\"\n",
+		},
+		{
+			input: "instantiate http_handler under gateway svc=unknown route=/cart",
+			want:  "Error instantiating template http_handler, no spans are created: create span \"GET /cart\" with parent t/gateway resource unknown: resource 'unknown' does not exist\n",
+		},
+		{
+			input: `instantiate http_handler under gateway svc="a b" route=/cart`,
+			want:  "Error expanding template http_handler: create span \"GET ${route}\" with parent ${parent} resource ${svc}: 1:58: unexpected token \"b\"\n",
+		},
+		{
+			input: "instantiate http_handler under gateway svc=cart route=${nope}",
+			want:  "Error parsing command: variable nope is not defined\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			output := captureOutput(func() {
+				Executor(tt.input)
+			})
+			assert.Equal(t, tt.want, output)
+		})
+	}
+
+	spans := telemetry.GetSpans()
+	assert.Len(t, spans, 3)
+	assert.Equal(t, "auth", spans["t/auth"].Name)
+	assert.Equal(t, "cart", spans["t/GET__cart"].Resource.Name)
+}
+
+func TestRewriteSpanRefs(t *testing.T) {
+	locals := map[string]string{"GET /cart": "t/GET__cart-2", "auth": "t/auth-2", "cart": "t/cart"}
+
+	tests := []struct {
+		line string
+		want string
+	}{
+		{
+			line: `create span auth with parent "GET /cart" attributes auth=auth`,
+			want: `create span auth with parent t/GET__cart-2 attributes auth=auth`,
+		},
+		{
+			line: "create span query with parent cart resource cart",
+			want: "create span query with parent t/cart resource cart",
+		},
+		{
+			line: "add link auth db attributes reason=retry",
+			want: "add link t/auth-2 db attributes reason=retry",
+		},
+		{
+			line: "add event auth cart",
+			want: "add event t/auth-2 cart",
+		},
+		{
+			line: "clone span auth as auth count 2 under auth",
+			want: "clone span t/auth-2 as auth count 2 under t/auth-2",
+		},
+		{
+			line: "set span auth name auth",
+			want: "set span t/auth-2 name auth",
+		},
+		{
+			line: "set resource cart name auth",
+			want: "set resource cart name auth",
+		},
+		{
+			line: "delete event cart",
+			want: "delete event cart",
+		},
+		{
+			line: "move span auth to trace cart",
+			want: "move span t/auth-2 to trace cart",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, err := parser.ParseString("", tt.line)
+			assert.NoError(t, err)
+			want, err := parser.ParseString("", tt.want)
+			assert.NoError(t, err)
+			rewriteSpanRefs(got, locals)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestHandleInstantiateCommand_RollsBackFailedCommand(t *testing.T) {
+	telemetry.InitStore()
+	t.Cleanup(func() {
+		pendingTemplate = nil
+	})
+
+	captureOutput(func() {
+		Executor("define template dup {")
+		Executor("create span a with parent ${parent}")
+		Executor("move span ${parent} under a")
+		Executor("}")
+		Executor("create span root in trace t")
+	})
+
+	output := captureOutput(func() {
+		Executor("instantiate dup under root")
+	})
+	assert.Equal(t, "Created span: a with parent span: t/root\n"+
+		"Error instantiating template dup, no spans are created: move span t/root under a: moving span: cannot move span t/root under itself or its descendant t/a\n", output)
+	spans := telemetry.GetSpans()
+	assert.Len(t, spans, 1, "Store should be unchanged")
+	assert.Empty(t, spans["t/root"].Children, "Store should be unchanged")
+
+	output = captureOutput(func() {
+		Executor("undo")
+	})
+	assert.Equal(t, "Undone: create span root in trace t\n", output, "Failed instantiation should not be recorded")
+}
+
+func TestHandleInstantiateCommand_SpanNamedAfterResource(t *testing.T) {
+	telemetry.InitStore()
+	t.Cleanup(func() {
+		pendingTemplate = nil
+	})
+
+	captureOutput(func() {
+		Executor("define template service(svc) {")
+		Executor("create span ${svc} with parent ${parent} resource ${svc}")
+		Executor("create span query with parent ${svc} resource ${svc}")
+		Executor("}")
+		Executor("create resource cart")
+		Executor("create span root in trace t")
+	})
+
+	output := captureOutput(func() {
+		Executor("instantiate service under root svc=cart")
+	})
+	assert.Equal(t, "Created span: cart with parent span: t/root\n"+
+		"Set resource cart to span cart\n"+
+		"Created span: query with parent span: t/cart\n"+
+		"Set resource cart to span query\n"+
+		"Instantiated template service with 2 spans\n", output)
+	assert.Equal(t, "cart", telemetry.GetSpans()["t/query"].Resource.Name)
+}
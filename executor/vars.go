@@ -0,0 +1,90 @@
+package executor
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// Variables are defined with let or -v of otelgen run and are interpolated into a command as
+// ${name} before it is parsed. The value is inserted as it is, so a value containing spaces
+// must be quoted where it is used, e.g. create span "${name}" in trace t.
+var (
+	variablesMu sync.RWMutex
+	variables   = make(map[string]string)
+
+	variableNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	variableRefPattern  = regexp.MustCompile(`\$\{([^}]*)\}`)
+)
+
+// SetVariable defines the variable or changes its value
+func SetVariable(name, value string) error {
+	if !variableNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid variable name %s (letters, digits and underscores)", name)
+	}
+	variablesMu.Lock()
+	defer variablesMu.Unlock()
+	variables[name] = value
+	return nil
+}
+
+// Variables returns a copy of the defined variables
+func Variables() map[string]string {
+	variablesMu.RLock()
+	defer variablesMu.RUnlock()
+	vars := make(map[string]string, len(variables))
+	for name, value := range variables {
+		vars[name] = value
+	}
+	return vars
+}
+
+// interpolate replaces the references to the variables in the input with their values
+func interpolate(input string) (string, error) {
+	variablesMu.RLock()
+	defer variablesMu.RUnlock()
+	var err error
+	result := variableRefPattern.ReplaceAllStringFunc(input, func(ref string) string {
+		name := variableRefPattern.FindStringSubmatch(ref)[1]
+		value, ok := variables[name]
+		if !ok && err == nil {
+			err = fmt.Errorf("variable %s is not defined", name)
+		}
+		return value
+	})
+	if err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+func handleLetCommand(cmd *LetCommand) bool {
+	if err := cmd.Validate(); err != nil {
+		fmt.Printf("Error validating let command: %v\n", err)
+		return false
+	}
+	if err := SetVariable(*cmd.Name, *cmd.Value); err != nil {
+		fmt.Printf("Error setting variable: %v\n", err)
+		return false
+	}
+	fmt.Printf("Set variable %s to %s\n", *cmd.Name, *cmd.Value)
+	return true
+}
+
+func handleVarsCommand() {
+	vars := Variables()
+	if len(vars) == 0 {
+		fmt.Println("No variables defined.")
+		return
+	}
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Println("Variables:")
+	for _, name := range names {
+		fmt.Printf("  %s = %s\n", name, vars[name])
+	}
+}
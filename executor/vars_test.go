@@ -0,0 +1,75 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/otelgen/telemetry"
+)
+
+func resetVariables() {
+	variablesMu.Lock()
+	defer variablesMu.Unlock()
+	variables = make(map[string]string)
+}
+
+func TestHandleLetAndVars(t *testing.T) {
+	t.Cleanup(resetVariables)
+	telemetry.InitStore()
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{
+			input: "vars",
+			want:  "No variables defined.\n",
+		},
+		{
+			input: "let service = cart",
+			want:  "Set variable service to cart\n",
+		},
+		{
+			input: `let route = "/cart/${service}"`,
+			want:  "Set variable route to /cart/cart\n",
+		},
+		{
+			input: "let 1st = x",
+			want:  "Error parsing command: 1:5: unexpected token \"1\"\n",
+		},
+		{
+			input: "let env",
+			want:  "Error validating let command: value of variable env must be specified with '='\n",
+		},
+		{
+			input: "vars",
+			want:  "Variables:\n  route = /cart/cart\n  service = cart\n",
+		},
+		{
+			input: `create span "GET ${route}" in trace ${service}`,
+			want:  "Created trace: cart\nCreated span: GET /cart/cart in trace: cart\n",
+		},
+		{
+			input: "create span ${nope} in trace t",
+			want:  "Error parsing command: variable nope is not defined\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			output := captureOutput(func() {
+				Executor(tt.input)
+			})
+			assert.Equal(t, tt.want, output)
+		})
+	}
+}
+
+func TestSetVariable(t *testing.T) {
+	t.Cleanup(resetVariables)
+
+	assert.NoError(t, SetVariable("env", "staging"))
+	assert.NoError(t, SetVariable("env", "prod"))
+	assert.EqualError(t, SetVariable("my-env", "x"), "invalid variable name my-env (letters, digits and underscores)")
+	assert.Equal(t, map[string]string{"env": "prod"}, Variables())
+}
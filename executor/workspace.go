@@ -0,0 +1,56 @@
+package executor
+
+import (
+	"fmt"
+
+	"github.com/ymtdzzz/otelgen/telemetry"
+)
+
+func handleWorkspaceCommand(cmd *WorkspaceCommand) bool {
+	if err := cmd.Validate(); err != nil {
+		fmt.Printf("Error validating workspace command: %v\n", err)
+		return false
+	}
+
+	var err error
+	switch *cmd.Action {
+	case "new":
+		err = telemetry.NewWorkspace(*cmd.Name)
+	case "switch":
+		err = telemetry.SwitchWorkspace(*cmd.Name)
+	case "delete":
+		err = telemetry.DeleteWorkspace(*cmd.Name)
+	case "copy":
+		err = telemetry.CopyWorkspace(*cmd.Name, *cmd.To)
+	case "list":
+		printWorkspaces()
+		return true
+	}
+	if err != nil {
+		fmt.Printf("Error running workspace %s: %v\n", *cmd.Action, err)
+		return false
+	}
+
+	switch *cmd.Action {
+	case "new":
+		fmt.Printf("Created workspace %s and switched to it\n", *cmd.Name)
+	case "switch":
+		fmt.Printf("Switched to workspace %s\n", *cmd.Name)
+	case "delete":
+		fmt.Printf("Deleted workspace %s\n", *cmd.Name)
+	case "copy":
+		fmt.Printf("Copied workspace %s to %s\n", *cmd.Name, *cmd.To)
+	}
+	return true
+}
+
+func printWorkspaces() {
+	fmt.Println("Workspaces:")
+	for _, ws := range telemetry.Workspaces() {
+		marker := " "
+		if ws.Current {
+			marker = "*"
+		}
+		fmt.Printf("%s %s (%d traces, %d spans)\n", marker, ws.Name, ws.Traces, ws.Spans)
+	}
+}
@@ -0,0 +1,129 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/otelgen/telemetry"
+)
+
+func TestHandleWorkspaceCommand(t *testing.T) {
+	telemetry.InitStore()
+	telemetry.CreateTrace("checkout")
+	t.Cleanup(func() {
+		_ = telemetry.SwitchWorkspace(telemetry.DefaultWorkspace)
+		for _, ws := range telemetry.Workspaces() {
+			_ = telemetry.DeleteWorkspace(ws.Name)
+		}
+	})
+
+	tests := []struct {
+		input      string
+		want       string
+		wantPrefix string
+	}{
+		{
+			input: "workspace list",
+			want:  "Workspaces:\n* default (1 traces, 0 spans)\n",
+		},
+		{
+			input:      "workspace new batch",
+			want:       "Created workspace batch and switched to it\n",
+			wantPrefix: "otelgen [batch]> ",
+		},
+		{
+			input:      "create span job in trace b",
+			want:       "Created trace: b\nCreated span: job in trace: b\n",
+			wantPrefix: "otelgen [batch]> ",
+		},
+		{
+			input:      "workspace copy batch batch2",
+			want:       "Copied workspace batch to batch2\n",
+			wantPrefix: "otelgen [batch]> ",
+		},
+		{
+			input:      "workspace list",
+			want:       "Workspaces:\n* batch (1 traces, 1 spans)\n  batch2 (1 traces, 1 spans)\n  default (1 traces, 0 spans)\n",
+			wantPrefix: "otelgen [batch]> ",
+		},
+		{
+			input:      "workspace delete batch",
+			want:       "Error validating workspace command: cannot delete the current workspace 'batch', switch to another workspace first\n",
+			wantPrefix: "otelgen [batch]> ",
+		},
+		{
+			input:      "workspace switch default",
+			want:       "Switched to workspace default\n",
+			wantPrefix: "otelgen [default]> ",
+		},
+		{
+			input:      "workspace delete batch2",
+			want:       "Deleted workspace batch2\n",
+			wantPrefix: "otelgen [default]> ",
+		},
+		{
+			input:      "workspace switch nope",
+			want:       "Error validating workspace command: workspace 'nope' does not exist\n",
+			wantPrefix: "otelgen [default]> ",
+		},
+		{
+			input:      "workspace new batch",
+			want:       "Error validating workspace command: workspace 'batch' already exists\n",
+			wantPrefix: "otelgen [default]> ",
+		},
+		{
+			input:      "workspace copy batch",
+			want:       "Error validating workspace command: source and new workspace names must be specified for workspace copy command\n",
+			wantPrefix: "otelgen [default]> ",
+		},
+		{
+			input:      "workspace list batch",
+			want:       "Error validating workspace command: unexpected argument 'batch' for workspace list command\n",
+			wantPrefix: "otelgen [default]> ",
+		},
+		{
+			input:      "workspace",
+			want:       "Error validating workspace command: action (new, switch, list, delete or copy) must be specified for workspace command\n",
+			wantPrefix: "otelgen [default]> ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			output := captureOutput(func() {
+				Executor(tt.input)
+			})
+			assert.Equal(t, tt.want, output)
+			prefix, _ := LivePrefix()
+			assert.Equal(t, tt.wantPrefix, prefix)
+		})
+	}
+
+	assert.Contains(t, telemetry.GetTraces(), "checkout")
+	assert.NotContains(t, telemetry.GetTraces(), "b", "Spans of another workspace should not be visible")
+}
+
+func TestLivePrefix_Workspace(t *testing.T) {
+	telemetry.InitStore()
+	t.Cleanup(func() {
+		if telemetry.Workspace() != telemetry.DefaultWorkspace {
+			_ = telemetry.NewWorkspace(telemetry.DefaultWorkspace)
+		}
+		for _, ws := range telemetry.Workspaces() {
+			_ = telemetry.DeleteWorkspace(ws.Name)
+		}
+	})
+
+	prefix, ok := LivePrefix()
+	assert.Equal(t, "", prefix)
+	assert.False(t, ok)
+
+	captureOutput(func() {
+		Executor("workspace new batch")
+		Executor("workspace delete default")
+	})
+	assert.Len(t, telemetry.Workspaces(), 1)
+	prefix, ok = LivePrefix()
+	assert.Equal(t, "otelgen [batch]> ", prefix, "Workspace should be shown when the only workspace is not the default one")
+	assert.True(t, ok)
+}
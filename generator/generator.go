@@ -0,0 +1,260 @@
+// Package generator synthesises random traces from a topology of services, operations and calls
+package generator
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/ymtdzzz/otelgen/telemetry"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// defaultLatency is the latency of operations without a latency
+var defaultLatency = telemetry.Distribution{Kind: "fixed", Params: []time.Duration{10 * time.Millisecond}}
+
+// Generator generates traces from a topology. The same topology and seed generate the same traces.
+type Generator struct {
+	rand        *rand.Rand
+	services    map[string]*Service
+	operations  map[string]*Operation
+	entrypoints []Entrypoint
+	totalWeight float64
+}
+
+// New validates the topology and returns a generator with the seed
+func New(t *Topology, seed int64) (*Generator, error) {
+	g := &Generator{
+		rand:       telemetry.NewRand(seed),
+		services:   make(map[string]*Service),
+		operations: make(map[string]*Operation),
+	}
+
+	for i := range t.Services {
+		svc := &t.Services[i]
+		if svc.Name == "" {
+			return nil, fmt.Errorf("service name must be specified")
+		}
+		if _, exists := g.services[svc.Name]; exists {
+			return nil, fmt.Errorf("service %s is defined more than once", svc.Name)
+		}
+		g.services[svc.Name] = svc
+		for j := range svc.Operations {
+			op := &svc.Operations[j]
+			key := operationKey(svc.Name, op.Name)
+			if _, exists := g.operations[key]; exists {
+				return nil, fmt.Errorf("operation %s is defined more than once", key)
+			}
+			if op.ErrorRate < 0 || op.ErrorRate > 1 {
+				return nil, fmt.Errorf("error rate of operation %s must be between 0 and 1", key)
+			}
+			g.operations[key] = op
+		}
+	}
+
+	// in the order of the topology so that the errors and the seed give the same results
+	var keys []string
+	var operations []Entrypoint
+	for _, svc := range t.Services {
+		for _, op := range svc.Operations {
+			keys = append(keys, operationKey(svc.Name, op.Name))
+			operations = append(operations, Entrypoint{Service: svc.Name, Operation: op.Name})
+		}
+	}
+
+	called := make(map[string]bool)
+	for _, key := range keys {
+		for _, call := range g.operations[key].Calls {
+			callee := operationKey(call.Service, call.Operation)
+			if _, ok := g.operations[callee]; !ok {
+				return nil, fmt.Errorf("operation %s called by %s is not defined", callee, key)
+			}
+			if call.Probability != nil && (*call.Probability < 0 || *call.Probability > 1) {
+				return nil, fmt.Errorf("probability of the call from %s to %s must be between 0 and 1", key, callee)
+			}
+			called[callee] = true
+		}
+	}
+	for _, key := range keys {
+		if err := g.checkCycle(key, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	g.entrypoints = t.Entrypoints
+	if len(g.entrypoints) == 0 {
+		for i, key := range keys {
+			if !called[key] {
+				g.entrypoints = append(g.entrypoints, operations[i])
+			}
+		}
+	}
+	if len(g.entrypoints) == 0 {
+		return nil, fmt.Errorf("no entrypoint is found, every operation is called by another operation")
+	}
+	for _, ep := range g.entrypoints {
+		key := operationKey(ep.Service, ep.Operation)
+		if _, ok := g.operations[key]; !ok {
+			return nil, fmt.Errorf("entrypoint %s is not defined", key)
+		}
+		if ep.Weight < 0 {
+			return nil, fmt.Errorf("weight of entrypoint %s must not be negative", key)
+		}
+		g.totalWeight += weight(ep)
+	}
+	return g, nil
+}
+
+// checkCycle returns an error if the operation calls itself through the path
+func (g *Generator) checkCycle(key string, path []string) error {
+	for i, k := range path {
+		if k == key {
+			return fmt.Errorf("call cycle is not allowed: %s", strings.Join(append(path[i:], key), " -> "))
+		}
+	}
+	path = append(path, key)
+	for _, call := range g.operations[key].Calls {
+		if err := g.checkCycle(operationKey(call.Service, call.Operation), path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Generate adds count traces named prefix-1, prefix-2... to the store, skipping the names which are
+// already used. The services are added as resources unless the store has resources with the names.
+func (g *Generator) Generate(s *telemetry.Store, prefix string, count int) ([]*telemetry.Trace, error) {
+	resources := make(map[string]*telemetry.Resource)
+	resourceOf := func(svc *Service) *telemetry.Resource {
+		if r, ok := resources[svc.Name]; ok {
+			return r
+		}
+		r, ok := s.GetResources()[svc.Name]
+		if !ok {
+			r = s.CreateResource(svc.Name, resourceAttributes(svc))
+		}
+		resources[svc.Name] = r
+		return r
+	}
+
+	traces := make([]*telemetry.Trace, 0, count)
+	next := 1
+	for range count {
+		name := fmt.Sprintf("%s-%d", prefix, next)
+		for s.IsTraceExists(name) {
+			next++
+			name = fmt.Sprintf("%s-%d", prefix, next)
+		}
+		next++
+
+		t, err := s.AddTrace(name, g.generateRoot(resourceOf))
+		if err != nil {
+			return traces, err
+		}
+		traces = append(traces, t)
+	}
+	return traces, nil
+}
+
+// Stream sends count traces through the tracer manager one by one without keeping them,
+// and returns the number of the sent spans
+func (g *Generator) Stream(ctx context.Context, tm *telemetry.TracerManager, count int) (int, error) {
+	spans := 0
+	for range count {
+		s := telemetry.NewStore()
+		resourceOf := func(svc *Service) *telemetry.Resource {
+			if r, ok := s.GetResources()[svc.Name]; ok {
+				return r
+			}
+			return s.CreateResource(svc.Name, resourceAttributes(svc))
+		}
+		if _, err := s.AddTrace("generated", g.generateRoot(resourceOf)); err != nil {
+			return spans, err
+		}
+		result, err := s.Send(ctx, tm)
+		if err != nil {
+			return spans, err
+		}
+		spans += result.Traces[0].Spans
+	}
+	return spans, nil
+}
+
+func (g *Generator) generateRoot(resourceOf func(*Service) *telemetry.Resource) *telemetry.Span {
+	ep := g.pickEntrypoint()
+	return g.generateSpan(ep.Service, ep.Operation, 0, resourceOf)
+}
+
+func (g *Generator) pickEntrypoint() Entrypoint {
+	n := g.rand.Float64() * g.totalWeight
+	for _, ep := range g.entrypoints {
+		n -= weight(ep)
+		if n < 0 {
+			return ep
+		}
+	}
+	return g.entrypoints[len(g.entrypoints)-1]
+}
+
+// generateSpan generates the span of the operation starting at the offset from its parent.
+// The calls are made one after another in the middle of the latency of the operation itself.
+func (g *Generator) generateSpan(service, operation string, offset time.Duration, resourceOf func(*Service) *telemetry.Resource) *telemetry.Span {
+	svc := g.services[service]
+	op := g.operations[operationKey(service, operation)]
+
+	span := &telemetry.Span{
+		Name:       op.Name,
+		Attributes: maps.Clone(op.Attributes),
+		Resource:   resourceOf(svc),
+	}
+	if span.Attributes == nil {
+		span.Attributes = map[string]string{}
+	}
+
+	latency := defaultLatency
+	if op.Latency.Kind != "" {
+		latency = op.Latency
+	}
+	self := latency.Sample(g.rand)
+	elapsed := self / 2
+	for _, call := range op.Calls {
+		if call.Probability != nil && g.rand.Float64() >= *call.Probability {
+			continue
+		}
+		count := 1
+		if call.Count != nil {
+			count = call.Count.Min + g.rand.IntN(call.Count.Max-call.Count.Min+1)
+		}
+		for range count {
+			child := g.generateSpan(call.Service, call.Operation, elapsed, resourceOf)
+			elapsed += child.Timing.Duration
+			span.AddChild(child)
+		}
+	}
+	span.Timing = &telemetry.Timing{Offset: offset, Duration: elapsed + self - self/2}
+
+	if op.ErrorRate > 0 && g.rand.Float64() < op.ErrorRate {
+		span.Status = &telemetry.Status{Code: codes.Error, Description: "generated error"}
+	}
+	return span
+}
+
+func resourceAttributes(svc *Service) map[string]string {
+	attrs := map[string]string{"service.name": svc.Name}
+	maps.Copy(attrs, svc.Attributes)
+	return attrs
+}
+
+func operationKey(service, operation string) string {
+	return service + "/" + operation
+}
+
+func weight(ep Entrypoint) float64 {
+	if ep.Weight == 0 {
+		return 1
+	}
+	return ep.Weight
+}
@@ -0,0 +1,224 @@
+package generator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/otelgen/telemetry"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const topologyYAML = `
+services:
+  - name: frontend
+    attributes:
+      deployment.environment: test
+    operations:
+      - name: GET /checkout
+        attributes:
+          http.method: GET
+        latency: fixed(20ms)
+        calls:
+          - service: cart
+            operation: GetCart
+          - service: cart
+            operation: GetItem
+            count: 2-4
+          - service: payment
+            operation: Charge
+            probability: 0
+  - name: cart
+    operations:
+      - name: GetCart
+        latency: uniform(5ms, 10ms)
+      - name: GetItem
+        latency: normal(3ms, 1ms)
+        error_rate: 1
+  - name: payment
+    operations:
+      - name: Charge
+`
+
+func loadTestTopology(t *testing.T) *Topology {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "topology.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(topologyYAML), 0o644))
+	topology, err := LoadTopology(path)
+	assert.NoError(t, err)
+	return topology
+}
+
+func TestGenerate(t *testing.T) {
+	g, err := New(loadTestTopology(t), 42)
+	assert.NoError(t, err)
+
+	s := telemetry.NewStore()
+	s.CreateTrace("checkout-2")
+	traces, err := g.Generate(s, "checkout", 3)
+	assert.NoError(t, err)
+
+	names := []string{}
+	for _, trace := range traces {
+		names = append(names, trace.Name)
+	}
+	assert.Equal(t, []string{"checkout-1", "checkout-3", "checkout-4"}, names, "Existing trace names should be skipped")
+	assert.Equal(t, map[string]string{"service.name": "frontend", "deployment.environment": "test"}, s.GetResources()["frontend"].Attributes)
+	assert.NotContains(t, s.GetResources(), "payment", "Service which is never called should not be added")
+
+	for _, trace := range traces {
+		root := trace.RootSpan
+		assert.Equal(t, "GET /checkout", root.Name)
+		assert.Equal(t, "frontend", root.Resource.Name)
+		assert.Nil(t, root.Status)
+		assert.Equal(t, "GetCart", root.Children[0].Name)
+		items := root.Children[1:]
+		assert.GreaterOrEqual(t, len(items), 2)
+		assert.LessOrEqual(t, len(items), 4)
+
+		// children are called one after another in the middle of the parent
+		offset := 10 * time.Millisecond
+		total := 20 * time.Millisecond
+		for _, child := range root.Children {
+			assert.Equal(t, offset, child.Timing.Offset)
+			offset += child.Timing.Duration
+			total += child.Timing.Duration
+		}
+		assert.Equal(t, total, root.Timing.Duration)
+		for _, item := range items {
+			assert.Equal(t, codes.Error, item.Status.Code)
+		}
+	}
+
+	other, err := New(loadTestTopology(t), 42)
+	assert.NoError(t, err)
+	s2 := telemetry.NewStore()
+	s2.CreateTrace("checkout-2")
+	_, err = other.Generate(s2, "checkout", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, s.Scenario(), s2.Scenario(), "Same seed should generate the same traces")
+}
+
+func TestStream(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tm, err := telemetry.NewTracerManager(func() (sdktrace.SpanExporter, error) {
+		return tracetest.NewNoopExporter(), nil
+	}, func() (sdktrace.SpanProcessor, error) {
+		return recorder, nil
+	})
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, tm.Shutdown(context.Background()))
+	})
+
+	g, err := New(loadTestTopology(t), 1)
+	assert.NoError(t, err)
+	spans, err := g.Stream(context.Background(), tm, 5)
+	assert.NoError(t, err)
+
+	assert.Len(t, recorder.Ended(), spans)
+	traceIDs := make(map[string]bool)
+	for _, span := range recorder.Ended() {
+		traceIDs[span.SpanContext().TraceID().String()] = true
+	}
+	assert.Len(t, traceIDs, 5)
+}
+
+func TestNew_Error(t *testing.T) {
+	one := 1.0
+	invalid := 1.5
+	tests := []struct {
+		name     string
+		topology Topology
+		want     string
+	}{
+		{
+			name: "Duplicate service",
+			topology: Topology{Services: []Service{
+				{Name: "cart", Operations: []Operation{{Name: "a"}}},
+				{Name: "cart"},
+			}},
+			want: "service cart is defined more than once",
+		},
+		{
+			name: "Unknown callee",
+			topology: Topology{Services: []Service{
+				{Name: "cart", Operations: []Operation{{Name: "a", Calls: []Call{{Service: "db", Operation: "query"}}}}},
+			}},
+			want: "operation db/query called by cart/a is not defined",
+		},
+		{
+			name: "Invalid probability",
+			topology: Topology{Services: []Service{
+				{Name: "cart", Operations: []Operation{{Name: "a", Calls: []Call{{Service: "cart", Operation: "b", Probability: &invalid}}}, {Name: "b"}}},
+			}},
+			want: "probability of the call from cart/a to cart/b must be between 0 and 1",
+		},
+		{
+			name: "Invalid error rate",
+			topology: Topology{Services: []Service{
+				{Name: "cart", Operations: []Operation{{Name: "a", ErrorRate: 2}}},
+			}},
+			want: "error rate of operation cart/a must be between 0 and 1",
+		},
+		{
+			name: "Cycle",
+			topology: Topology{
+				Services: []Service{
+					{Name: "cart", Operations: []Operation{
+						{Name: "a", Calls: []Call{{Service: "cart", Operation: "b", Probability: &one}}},
+						{Name: "b", Calls: []Call{{Service: "cart", Operation: "a"}}},
+					}},
+				},
+				Entrypoints: []Entrypoint{{Service: "cart", Operation: "a"}},
+			},
+			want: "call cycle is not allowed: cart/a -> cart/b -> cart/a",
+		},
+		{
+			name: "Unknown entrypoint",
+			topology: Topology{
+				Services:    []Service{{Name: "cart", Operations: []Operation{{Name: "a"}}}},
+				Entrypoints: []Entrypoint{{Service: "cart", Operation: "b"}},
+			},
+			want: "entrypoint cart/b is not defined",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(&tt.topology, 1)
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Range
+		wantErr bool
+	}{
+		{input: "3", want: Range{Min: 3, Max: 3}},
+		{input: "1-5", want: Range{Min: 1, Max: 5}},
+		{input: "0-0", want: Range{Min: 0, Max: 0}},
+		{input: "5-1", wantErr: true},
+		{input: "a", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseRange(tt.input)
+			if tt.wantErr {
+				assert.EqualError(t, err, "invalid count "+tt.input+" (e.g. 3 or 1-5)")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
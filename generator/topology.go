@@ -0,0 +1,115 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ymtdzzz/otelgen/telemetry"
+	"gopkg.in/yaml.v3"
+)
+
+// Topology describes the services, their operations and the calls between the operations
+type Topology struct {
+	Services []Service `yaml:"services"`
+	// Entrypoints are the operations which start traces. When empty, the operations
+	// which are not called by any other operation are used with the same weight.
+	Entrypoints []Entrypoint `yaml:"entrypoints,omitempty"`
+}
+
+// Service is sent as a resource named after the service
+type Service struct {
+	Name string `yaml:"name"`
+	// Attributes are the resource attributes. service.name is the name of the service unless it is specified.
+	Attributes map[string]string `yaml:"attributes,omitempty"`
+	Operations []Operation       `yaml:"operations"`
+}
+
+// Operation is sent as a span named after the operation
+type Operation struct {
+	Name       string            `yaml:"name"`
+	Attributes map[string]string `yaml:"attributes,omitempty"`
+	// Latency is the time spent in the operation itself, excluding its calls. It is 10ms when not specified.
+	Latency telemetry.Distribution `yaml:"latency,omitempty"`
+	// ErrorRate is the probability (0 to 1) that the span has the error status
+	ErrorRate float64 `yaml:"error_rate,omitempty"`
+	Calls     []Call  `yaml:"calls,omitempty"`
+}
+
+// Call is a call from an operation to another operation, which becomes a child span
+type Call struct {
+	Service   string `yaml:"service"`
+	Operation string `yaml:"operation"`
+	// Probability is the probability (0 to 1) that the call is made. It is 1 when not specified.
+	Probability *float64 `yaml:"probability,omitempty"`
+	// Count is the number of times the call is made, e.g. 3 or 1-5. It is 1 when not specified.
+	Count *Range `yaml:"count,omitempty"`
+}
+
+// Entrypoint is an operation which starts traces
+type Entrypoint struct {
+	Service   string `yaml:"service"`
+	Operation string `yaml:"operation"`
+	// Weight is the relative frequency of the entrypoint. It is 1 when not specified.
+	Weight float64 `yaml:"weight,omitempty"`
+}
+
+// Range is an inclusive range of counts written as 3 or 1-5
+type Range struct {
+	Min int
+	Max int
+}
+
+func (r *Range) UnmarshalYAML(value *yaml.Node) error {
+	parsed, err := ParseRange(value.Value)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+func (r Range) MarshalYAML() (any, error) {
+	if r.Min == r.Max {
+		return r.Min, nil
+	}
+	return fmt.Sprintf("%d-%d", r.Min, r.Max), nil
+}
+
+// ParseRange parses the range, e.g. 3 or 1-5
+func ParseRange(s string) (Range, error) {
+	minStr, maxStr, isRange := strings.Cut(s, "-")
+	if !isRange {
+		maxStr = minStr
+	}
+	min, err := strconv.Atoi(strings.TrimSpace(minStr))
+	if err != nil {
+		return Range{}, fmt.Errorf("invalid count %s (e.g. 3 or 1-5)", s)
+	}
+	max, err := strconv.Atoi(strings.TrimSpace(maxStr))
+	if err != nil {
+		return Range{}, fmt.Errorf("invalid count %s (e.g. 3 or 1-5)", s)
+	}
+	if min < 0 || max < min {
+		return Range{}, fmt.Errorf("invalid count %s (e.g. 3 or 1-5)", s)
+	}
+	return Range{Min: min, Max: max}, nil
+}
+
+// LoadTopology reads the topology from the YAML file
+func LoadTopology(path string) (*Topology, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var t Topology
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(&t); err != nil {
+		return nil, fmt.Errorf("failed to parse topology %s: %w", path, err)
+	}
+	return &t, nil
+}
@@ -2,22 +2,75 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
 	prompt "github.com/c-bata/go-prompt"
 	"github.com/ymtdzzz/otelgen/completer"
 	"github.com/ymtdzzz/otelgen/executor"
+	"github.com/ymtdzzz/otelgen/receiver"
+	"github.com/ymtdzzz/otelgen/recorder"
 	"github.com/ymtdzzz/otelgen/telemetry"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
 )
 
 func main() {
-	exporterFn := func() (sdktrace.SpanExporter, error) {
-		return otlptracegrpc.New(context.Background(),
-			otlptracegrpc.WithInsecure(),
-			otlptracegrpc.WithEndpoint("localhost:4317"),
-		)
+	os.Exit(run())
+}
+
+func run() int {
+	exporter := flag.String("exporter", "otlp", "exporter to send traces with: otlp or memory (for expect)")
+	endpoint := flag.String("endpoint", "localhost:4317", "OTLP/gRPC endpoint of the otlp exporter")
+	startReceiver := flag.Bool("receiver", false, "start a local OTLP receiver and send traces to it (see the received command)")
+	receiverGRPC := flag.String("receiver-grpc", "127.0.0.1:0", "OTLP/gRPC address of the local receiver (port 0 picks a free port)")
+	receiverHTTP := flag.String("receiver-http", "127.0.0.1:0", "OTLP/HTTP address of the local receiver, empty to disable")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: otelgen [flags] [run <script> [-v name=value]...]\n       otelgen record [record flags]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.Arg(0) == "record" {
+		return runRecord(flag.Args()[1:])
+	}
+
+	if *startReceiver {
+		r, err := receiver.Start(*receiverGRPC, *receiverHTTP)
+		if err != nil {
+			fmt.Printf("Error starting receiver: %v\n", err)
+			return 1
+		}
+		defer r.Shutdown(context.Background())
+		receiver.SetDefault(r)
+		fmt.Printf("Receiving OTLP/gRPC on %s", r.GRPCAddr())
+		if r.HTTPAddr() != "" {
+			fmt.Printf(" and OTLP/HTTP on %s", r.HTTPAddr())
+		}
+		fmt.Println()
+		*endpoint = r.GRPCAddr()
+	}
+
+	var exporterFn func() (sdktrace.SpanExporter, error)
+	switch *exporter {
+	case "otlp":
+		exporterFn = func() (sdktrace.SpanExporter, error) {
+			return otlptracegrpc.New(context.Background(),
+				otlptracegrpc.WithInsecure(),
+				otlptracegrpc.WithEndpoint(*endpoint),
+			)
+		}
+	case "memory":
+		exporterFn = telemetry.EnableMemoryExporter()
+	default:
+		fmt.Printf("Unknown exporter: %s\n", *exporter)
+		flag.Usage()
+		return 2
 	}
 
 	telemetry.InitTracerManager(exporterFn, nil)
@@ -29,7 +82,126 @@ func main() {
 
 	telemetry.InitStore()
 
+	if flag.NArg() > 0 {
+		if flag.Arg(0) != "run" {
+			flag.Usage()
+			return 2
+		}
+		return runScript(flag.Args()[1:])
+	}
+
 	fmt.Println("OpenTelemetry CLI generator (type 'exit' to quit)")
-	p := prompt.New(executor.Executor, completer.Completer, prompt.OptionPrefix("otelgen> "))
+	p := prompt.New(executor.Executor, completer.Completer,
+		prompt.OptionPrefix("otelgen> "),
+		prompt.OptionLivePrefix(executor.LivePrefix),
+	)
 	p.Run()
+	return 0
+}
+
+// runScript runs the script with the variables given with -v, which can come before or after the script path
+func runScript(args []string) int {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.Func("v", "define a variable referred to as ${name} in the script, e.g. -v env=staging (repeatable)", func(s string) error {
+		name, value, ok := strings.Cut(s, "=")
+		if !ok {
+			return fmt.Errorf("variable must be name=value: %s", s)
+		}
+		return executor.SetVariable(name, value)
+	})
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: otelgen [flags] run <script> [-v name=value]...\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	path := fs.Arg(0)
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return 2
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		fmt.Printf("Error opening script: %v\n", err)
+		return 1
+	}
+	defer f.Close()
+
+	if err := executor.RunScript(f); err != nil {
+		fmt.Printf("Error running script: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runRecord receives OTLP traces until interrupted and writes them to a scenario file
+func runRecord(args []string) int {
+	fs := flag.NewFlagSet("record", flag.ContinueOnError)
+	listen := fs.String("listen", ":4317", "OTLP/gRPC address to receive traces on")
+	listenHTTP := fs.String("listen-http", "", "OTLP/HTTP address to receive traces on, empty to disable")
+	out := fs.String("out", "scenario.yaml", "scenario file to write the recorded traces to")
+	forward := fs.String("forward", "", "OTLP/gRPC endpoint to forward the received traces to, empty to disable")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: otelgen record [--listen :4317] [--out scenario.yaml] [--forward host:port]\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	var opts []receiver.Option
+	if *forward != "" {
+		f, err := recorder.NewForwarder(*forward)
+		if err != nil {
+			fmt.Printf("Error starting forwarder: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		opts = append(opts, receiver.WithForward(func(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) {
+			if err := f.Forward(ctx, req); err != nil {
+				fmt.Printf("Error forwarding traces to %s: %v\n", *forward, err)
+			}
+		}))
+	}
+
+	r, err := receiver.Start(*listen, *listenHTTP, opts...)
+	if err != nil {
+		fmt.Printf("Error starting receiver: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Recording OTLP/gRPC on %s", r.GRPCAddr())
+	if r.HTTPAddr() != "" {
+		fmt.Printf(" and OTLP/HTTP on %s", r.HTTPAddr())
+	}
+	if *forward != "" {
+		fmt.Printf(", forwarding to %s", *forward)
+	}
+	fmt.Println()
+	fmt.Printf("Press Ctrl+C to stop recording and write %s.\n", *out)
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	<-sig
+	signal.Stop(sig)
+	r.Shutdown(context.Background())
+
+	sc, warnings := recorder.Convert(r.Traces())
+	for _, warning := range warnings {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+	if err := sc.WriteFile(*out); err != nil {
+		fmt.Printf("Error writing scenario: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Recorded %d traces (%d spans) to %s.\n", len(sc.Traces), len(r.Spans()), *out)
+	return 0
 }
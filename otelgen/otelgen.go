@@ -0,0 +1,266 @@
+// Package otelgen builds traces with the otelgen model from Go code, e.g. in integration tests:
+//
+//	svc := otelgen.NewResource("cart", map[string]string{"service.version": "1.0.0"})
+//	spans, err := otelgen.NewTrace("checkout").
+//		Root("GET /cart").Resource(svc).
+//		Child(otelgen.Span("SELECT carts").Attr("db.system", "postgresql")).
+//		Send(ctx, exporter)
+package otelgen
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/ymtdzzz/otelgen/telemetry"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// Resource is the resource which spans are bound to
+type Resource = telemetry.Resource
+
+// NewResource returns a resource whose name is used as service.name unless the attributes override it
+func NewResource(name string, attributes map[string]string) *Resource {
+	return &Resource{
+		Name:       name,
+		Attributes: maps.Clone(attributes),
+	}
+}
+
+// TraceBuilder builds a trace with a root span
+type TraceBuilder struct {
+	name    string
+	root    *SpanBuilder
+	inherit *bool
+}
+
+// NewTrace returns a builder for a trace with the name
+func NewTrace(name string) *TraceBuilder {
+	return &TraceBuilder{name: name}
+}
+
+// Root sets a new root span with the name and returns its builder
+func (t *TraceBuilder) Root(name string) *SpanBuilder {
+	t.root = Span(name)
+	t.root.trace = t
+	return t.root
+}
+
+// InheritResource makes spans without a resource use the resource of their parent span
+func (t *TraceBuilder) InheritResource(inherit bool) *TraceBuilder {
+	t.inherit = &inherit
+	return t
+}
+
+// Send sends the trace, see Send
+func (t *TraceBuilder) Send(ctx context.Context, exporter sdktrace.SpanExporter) ([]sdktrace.ReadOnlySpan, error) {
+	return Send(ctx, exporter, t)
+}
+
+type event struct {
+	name       string
+	attributes map[string]string
+}
+
+type link struct {
+	target     *SpanBuilder
+	attributes map[string]string
+}
+
+// SpanBuilder builds a span with its descendants. A span builder can be added to only one parent.
+type SpanBuilder struct {
+	trace      *TraceBuilder
+	parent     *SpanBuilder
+	name       string
+	attributes map[string]string
+	resource   *Resource
+	children   []*SpanBuilder
+	events     []event
+	links      []link
+}
+
+// Span returns a builder for a span with the name, which is added to a parent with Child
+func Span(name string) *SpanBuilder {
+	return &SpanBuilder{
+		name:       name,
+		attributes: make(map[string]string),
+	}
+}
+
+// Attr sets the attribute of the span
+func (s *SpanBuilder) Attr(key, value string) *SpanBuilder {
+	s.attributes[key] = value
+	return s
+}
+
+// Attrs sets the attributes of the span, keeping the other attributes
+func (s *SpanBuilder) Attrs(attributes map[string]string) *SpanBuilder {
+	maps.Copy(s.attributes, attributes)
+	return s
+}
+
+// Resource binds the span to the resource
+func (s *SpanBuilder) Resource(resource *Resource) *SpanBuilder {
+	s.resource = resource
+	return s
+}
+
+// Event adds an event with the attributes to the span
+func (s *SpanBuilder) Event(name string, attributes map[string]string) *SpanBuilder {
+	s.events = append(s.events, event{name: name, attributes: maps.Clone(attributes)})
+	return s
+}
+
+// Link adds a link with the attributes from the span to the target span, which must be
+// sent together with the span
+func (s *SpanBuilder) Link(target *SpanBuilder, attributes map[string]string) *SpanBuilder {
+	s.links = append(s.links, link{target: target, attributes: maps.Clone(attributes)})
+	return s
+}
+
+// Child adds the spans as children of the span and returns the span (not the children),
+// so that siblings can be chained
+func (s *SpanBuilder) Child(children ...*SpanBuilder) *SpanBuilder {
+	for _, child := range children {
+		child.parent = s
+		s.children = append(s.children, child)
+	}
+	return s
+}
+
+// Parent returns the builder of the parent span, or nil for a root span
+func (s *SpanBuilder) Parent() *SpanBuilder {
+	return s.parent
+}
+
+// Trace returns the builder of the trace which the span belongs to,
+// or nil if the span is not added to a trace yet
+func (s *SpanBuilder) Trace() *TraceBuilder {
+	root := s
+	for root.parent != nil {
+		root = root.parent
+	}
+	return root.trace
+}
+
+// Send sends the trace which the span belongs to, see Send
+func (s *SpanBuilder) Send(ctx context.Context, exporter sdktrace.SpanExporter) ([]sdktrace.ReadOnlySpan, error) {
+	t := s.Trace()
+	if t == nil {
+		return nil, fmt.Errorf("span %s is not added to a trace", s.name)
+	}
+	return Send(ctx, exporter, t)
+}
+
+// Send sends the traces to the exporter with the same timing as the send command, and returns
+// the emitted spans. The exporter is not shut down. When exporter is nil, the spans are only returned.
+func Send(ctx context.Context, exporter sdktrace.SpanExporter, traces ...*TraceBuilder) ([]sdktrace.ReadOnlySpan, error) {
+	store, err := build(traces)
+	if err != nil {
+		return nil, err
+	}
+
+	recorder := tracetest.NewSpanRecorder()
+	tm, err := telemetry.NewTracerManager(func() (sdktrace.SpanExporter, error) {
+		if exporter == nil {
+			return tracetest.NewNoopExporter(), nil
+		}
+		return unclosableExporter{exporter}, nil
+	}, func() (sdktrace.SpanProcessor, error) {
+		return recorder, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := store.Send(ctx, tm)
+	if shutdownErr := tm.Shutdown(ctx); err == nil {
+		err = shutdownErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Warnings) > 0 {
+		return nil, fmt.Errorf("failed to send traces: %s", result.Warnings[0])
+	}
+	return recorder.Ended(), nil
+}
+
+// build creates a store with the traces
+func build(traces []*TraceBuilder) (*telemetry.Store, error) {
+	store := telemetry.NewStore()
+	spans := make(map[*SpanBuilder]*telemetry.Span)
+	resources := make(map[string]*Resource)
+
+	var add func(b *SpanBuilder, span *telemetry.Span) error
+	add = func(b *SpanBuilder, span *telemetry.Span) error {
+		if _, exists := spans[b]; exists {
+			return fmt.Errorf("span %s is added more than once", b.name)
+		}
+		spans[b] = span
+		if b.resource != nil {
+			if r, exists := resources[b.resource.Name]; exists && r != b.resource {
+				return fmt.Errorf("resource name %s is used by different resources", b.resource.Name)
+			}
+			resources[b.resource.Name] = b.resource
+			span.Resource = b.resource
+		}
+		for _, e := range b.events {
+			span.AddEvent(&telemetry.Event{Name: e.name, Attributes: e.attributes})
+		}
+		for _, child := range b.children {
+			childSpan, err := store.AddSpanToSpan(span.Handle, child.name, maps.Clone(child.attributes))
+			if err != nil {
+				return err
+			}
+			if err := add(child, childSpan); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, t := range traces {
+		if store.IsTraceExists(t.name) {
+			return nil, fmt.Errorf("trace %s is sent more than once", t.name)
+		}
+		store.CreateTrace(t.name)
+		if _, err := store.SetTraceInheritResource(t.name, t.inherit); err != nil {
+			return nil, err
+		}
+		if t.root == nil {
+			continue
+		}
+		root, err := store.AddSpanToTrace(t.name, t.root.name, maps.Clone(t.root.attributes))
+		if err != nil {
+			return nil, err
+		}
+		if err := add(t.root, root); err != nil {
+			return nil, err
+		}
+	}
+
+	for b, span := range spans {
+		for _, l := range b.links {
+			target, ok := spans[l.target]
+			if !ok {
+				return nil, fmt.Errorf("link target of span %s is not in the sent traces", b.name)
+			}
+			if _, err := store.AddLinkToSpan(span.Handle, target.Handle, l.attributes); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return store, nil
+}
+
+// unclosableExporter keeps the exporter of the caller open when the tracer providers are shut down
+type unclosableExporter struct {
+	sdktrace.SpanExporter
+}
+
+func (unclosableExporter) Shutdown(context.Context) error {
+	return nil
+}
@@ -0,0 +1,125 @@
+package otelgen
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func spansByName(spans []sdktrace.ReadOnlySpan) map[string]sdktrace.ReadOnlySpan {
+	result := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range spans {
+		result[span.Name()] = span
+	}
+	return result
+}
+
+func TestSend(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	svc := NewResource("cart", map[string]string{"service.version": "1.0.0"})
+
+	db := Span("SELECT carts").Attr("db.system", "postgresql")
+	spans, err := NewTrace("checkout").
+		Root("GET /cart").Resource(svc).Attr("http.method", "GET").
+		Event("cache miss", map[string]string{"key": "cart"}).
+		Child(db, Span("render").Link(db, map[string]string{"reason": "rendered"})).
+		Send(context.Background(), exporter)
+
+	assert.NoError(t, err)
+	assert.Len(t, spans, 3)
+	assert.Len(t, exporter.GetSpans(), 3, "Spans should be exported and the exporter kept open")
+
+	got := spansByName(spans)
+	root := got["GET /cart"]
+	assert.False(t, root.Parent().HasSpanID())
+	assert.Contains(t, root.Attributes(), attribute.String("http.method", "GET"))
+	assert.Contains(t, root.Resource().Attributes(), attribute.String("service.name", "cart"))
+	if assert.Len(t, root.Events(), 1) {
+		assert.Equal(t, "cache miss", root.Events()[0].Name)
+	}
+
+	assert.Equal(t, root.SpanContext().SpanID(), got["SELECT carts"].Parent().SpanID())
+	assert.Equal(t, root.SpanContext().TraceID(), got["render"].SpanContext().TraceID())
+	if assert.Len(t, got["render"].Links(), 1) {
+		assert.Equal(t, got["SELECT carts"].SpanContext().SpanID(), got["render"].Links()[0].SpanContext.SpanID())
+	}
+}
+
+func TestSend_InheritResource(t *testing.T) {
+	svc := NewResource("cart", nil)
+	trace := NewTrace("checkout").InheritResource(true)
+	trace.Root("GET /cart").Resource(svc).Child(Span("db"))
+
+	spans, err := trace.Send(context.Background(), nil)
+
+	assert.NoError(t, err)
+	assert.Contains(t, spansByName(spans)["db"].Resource().Attributes(), attribute.String("service.name", "cart"))
+}
+
+func TestSend_MultipleTraces(t *testing.T) {
+	checkout := NewTrace("checkout")
+	checkoutRoot := checkout.Root("GET /cart")
+	search := NewTrace("search")
+	search.Root("GET /search").Link(checkoutRoot, nil)
+
+	spans, err := Send(context.Background(), nil, checkout, search)
+
+	assert.NoError(t, err)
+	got := spansByName(spans)
+	assert.NotEqual(t, got["GET /cart"].SpanContext().TraceID(), got["GET /search"].SpanContext().TraceID())
+	assert.Len(t, got["GET /search"].Links(), 1)
+}
+
+func TestSend_Error(t *testing.T) {
+	tests := []struct {
+		name    string
+		send    func() error
+		wantErr string
+	}{
+		{
+			name: "Span not in trace",
+			send: func() error {
+				_, err := Span("orphan").Send(context.Background(), nil)
+				return err
+			},
+			wantErr: "span orphan is not added to a trace",
+		},
+		{
+			name: "Link target not sent",
+			send: func() error {
+				_, err := NewTrace("checkout").Root("root").Link(Span("other"), nil).Send(context.Background(), nil)
+				return err
+			},
+			wantErr: "link target of span root is not in the sent traces",
+		},
+		{
+			name: "Span added twice",
+			send: func() error {
+				db := Span("db")
+				_, err := NewTrace("checkout").Root("root").Child(db, db).Send(context.Background(), nil)
+				return err
+			},
+			wantErr: "span db is added more than once",
+		},
+		{
+			name: "Conflicting resources",
+			send: func() error {
+				_, err := NewTrace("checkout").Root("root").Resource(NewResource("cart", nil)).
+					Child(Span("db").Resource(NewResource("cart", nil))).
+					Send(context.Background(), nil)
+				return err
+			},
+			wantErr: "resource name cart is used by different resources",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.EqualError(t, tt.send(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,273 @@
+// Package receiver implements a minimal in-process OTLP trace receiver over gRPC and HTTP
+// which keeps the received spans in memory
+package receiver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultMaxSpans is the number of the received spans kept by default
+const DefaultMaxSpans = 10000
+
+// Receiver accepts OTLP trace exports and keeps the received spans.
+// Its methods are safe for concurrent use.
+type Receiver struct {
+	mu       sync.RWMutex
+	spans    []*Span
+	maxSpans int
+
+	grpcServer   *grpc.Server
+	grpcListener net.Listener
+	httpServer   *http.Server
+	httpListener net.Listener
+
+	forward func(context.Context, *coltracepb.ExportTraceServiceRequest)
+}
+
+// Option configures a receiver
+type Option func(*Receiver)
+
+// WithForward calls fn with each export request after the spans are stored,
+// e.g. to forward the request to an upstream collector
+func WithForward(fn func(context.Context, *coltracepb.ExportTraceServiceRequest)) Option {
+	return func(r *Receiver) {
+		r.forward = fn
+	}
+}
+
+// WithMaxSpans sets the number of the received spans kept by the receiver. When more spans are
+// received, the oldest ones are dropped. Zero or less keeps all the spans.
+func WithMaxSpans(n int) Option {
+	return func(r *Receiver) {
+		r.maxSpans = n
+	}
+}
+
+// Start starts the receiver with the gRPC server on grpcAddr and the HTTP server (POST /v1/traces)
+// on httpAddr. An empty address disables the server, and port 0 picks a free port.
+func Start(grpcAddr, httpAddr string, opts ...Option) (*Receiver, error) {
+	r := &Receiver{maxSpans: DefaultMaxSpans}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if grpcAddr != "" {
+		lis, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen for OTLP/gRPC: %w", err)
+		}
+		r.grpcListener = lis
+		r.grpcServer = grpc.NewServer()
+		coltracepb.RegisterTraceServiceServer(r.grpcServer, &traceService{receiver: r})
+		go r.grpcServer.Serve(lis)
+	}
+
+	if httpAddr != "" {
+		lis, err := net.Listen("tcp", httpAddr)
+		if err != nil {
+			r.Shutdown(context.Background())
+			return nil, fmt.Errorf("failed to listen for OTLP/HTTP: %w", err)
+		}
+		r.httpListener = lis
+		mux := http.NewServeMux()
+		mux.HandleFunc("/v1/traces", r.handleHTTP)
+		r.httpServer = &http.Server{Handler: mux}
+		go r.httpServer.Serve(lis)
+	}
+
+	return r, nil
+}
+
+// GRPCAddr returns the address of the gRPC server, or an empty string if it is disabled
+func (r *Receiver) GRPCAddr() string {
+	if r.grpcListener == nil {
+		return ""
+	}
+	return r.grpcListener.Addr().String()
+}
+
+// HTTPAddr returns the address of the HTTP server, or an empty string if it is disabled
+func (r *Receiver) HTTPAddr() string {
+	if r.httpListener == nil {
+		return ""
+	}
+	return r.httpListener.Addr().String()
+}
+
+// Shutdown stops the servers
+func (r *Receiver) Shutdown(ctx context.Context) error {
+	var err error
+	if r.grpcServer != nil {
+		r.grpcServer.GracefulStop()
+	}
+	if r.httpServer != nil {
+		err = r.httpServer.Shutdown(ctx)
+	}
+	return err
+}
+
+// Spans returns the received spans in the order they were received.
+// Only the latest spans up to the limit set by WithMaxSpans are kept.
+func (r *Receiver) Spans() []*Span {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]*Span(nil), r.spans...)
+}
+
+// Traces returns the received spans grouped by trace in the order the traces were first received
+func (r *Receiver) Traces() []*Trace {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return groupTraces(r.spans)
+}
+
+// Reset removes the received spans
+func (r *Receiver) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = nil
+}
+
+// Export stores the spans in the request dropping the oldest spans over the limit.
+// It is called for both gRPC and HTTP exports.
+func (r *Receiver) Export(req *coltracepb.ExportTraceServiceRequest) {
+	spans := convertResourceSpans(req.GetResourceSpans())
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = append(r.spans, spans...)
+	if r.maxSpans > 0 && len(r.spans) > r.maxSpans {
+		// copy the kept spans so that the dropped ones are not retained by the backing array
+		r.spans = append([]*Span(nil), r.spans[len(r.spans)-r.maxSpans:]...)
+	}
+}
+
+// receive stores the spans of a request received by the servers and forwards it
+func (r *Receiver) receive(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) {
+	r.Export(req)
+	if r.forward != nil {
+		r.forward(ctx, req)
+	}
+}
+
+type traceService struct {
+	coltracepb.UnimplementedTraceServiceServer
+	receiver *Receiver
+}
+
+func (s *traceService) Export(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	s.receiver.receive(ctx, req)
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+func (r *Receiver) handleHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := readBody(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// the media type may come with parameters, e.g. application/json; charset=utf-8
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	isJSON := mediaType == "application/json"
+	exportReq := &coltracepb.ExportTraceServiceRequest{}
+	if isJSON {
+		err = protojson.Unmarshal(body, exportReq)
+	} else {
+		err = proto.Unmarshal(body, exportReq)
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	r.receive(req.Context(), exportReq)
+
+	var resp []byte
+	if isJSON {
+		w.Header().Set("Content-Type", "application/json")
+		resp, err = protojson.Marshal(&coltracepb.ExportTraceServiceResponse{})
+	} else {
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		resp, err = proto.Marshal(&coltracepb.ExportTraceServiceResponse{})
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Write(resp)
+}
+
+func readBody(req *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch req.Header.Get("Content-Encoding") {
+	case "", "identity":
+		return body, nil
+	case "gzip":
+		gr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		defer gr.Close()
+		return io.ReadAll(gr)
+	default:
+		return nil, errors.New("unsupported content encoding: " + req.Header.Get("Content-Encoding"))
+	}
+}
+
+// groupTraces groups the spans by trace ID keeping the order of the first span of each trace.
+// The spans of a trace are sorted by start time.
+func groupTraces(spans []*Span) []*Trace {
+	var traces []*Trace
+	byID := make(map[string]*Trace)
+	for _, span := range spans {
+		id := span.TraceID.String()
+		t, ok := byID[id]
+		if !ok {
+			t = &Trace{TraceID: span.TraceID}
+			byID[id] = t
+			traces = append(traces, t)
+		}
+		t.Spans = append(t.Spans, span)
+	}
+	for _, t := range traces {
+		sort.SliceStable(t.Spans, func(i, j int) bool {
+			return t.Spans[i].StartTime.Before(t.Spans[j].StartTime)
+		})
+	}
+	return traces
+}
+
+// defaultReceiver is the receiver started for the session
+var defaultReceiver *Receiver
+
+// SetDefault sets the receiver of the session, which is shown by the received command
+func SetDefault(r *Receiver) {
+	defaultReceiver = r
+}
+
+// Default returns the receiver of the session, or nil if it is not started
+func Default() *Receiver {
+	return defaultReceiver
+}
@@ -0,0 +1,210 @@
+package receiver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func startReceiver(t *testing.T) *Receiver {
+	t.Helper()
+	r, err := Start("127.0.0.1:0", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to start receiver: %v", err)
+	}
+	t.Cleanup(func() {
+		r.Shutdown(context.Background())
+	})
+	return r
+}
+
+func TestReceiver_GRPC(t *testing.T) {
+	r := startReceiver(t)
+
+	exporter, err := otlptracegrpc.New(context.Background(),
+		otlptracegrpc.WithInsecure(),
+		otlptracegrpc.WithEndpoint(r.GRPCAddr()),
+	)
+	assert.NoError(t, err)
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSyncer(exporter),
+		sdktrace.WithResource(sdkresource.NewSchemaless(attribute.String("service.name", "cart"))),
+	)
+	tracer := tp.Tracer("test")
+
+	ctx, root := tracer.Start(context.Background(), "GET /cart", trace.WithSpanKind(trace.SpanKindServer))
+	_, child := tracer.Start(ctx, "SELECT carts", trace.WithAttributes(attribute.Int("db.rows", 3)))
+	child.AddEvent("cache miss")
+	child.SetStatus(codes.Error, "timeout")
+	child.End()
+	root.End()
+	assert.NoError(t, tp.Shutdown(context.Background()))
+
+	traces := r.Traces()
+	if assert.Len(t, traces, 1) {
+		got := traces[0]
+		assert.Equal(t, root.SpanContext().TraceID(), got.TraceID)
+		assert.Len(t, got.Spans, 2)
+
+		roots := got.Roots()
+		if assert.Len(t, roots, 1) {
+			assert.Equal(t, "GET /cart", roots[0].Name)
+			assert.Equal(t, trace.SpanKindServer, roots[0].Kind)
+			assert.Equal(t, "cart", roots[0].Resource.ServiceName())
+		}
+		children := got.Children(roots[0])
+		if assert.Len(t, children, 1) {
+			assert.Equal(t, "3", children[0].Attributes["db.rows"])
+			assert.Equal(t, codes.Error, children[0].StatusCode)
+			assert.Equal(t, "timeout", children[0].StatusMessage)
+			assert.Equal(t, "cache miss", children[0].Events[0].Name)
+		}
+	}
+
+	r.Reset()
+	assert.Empty(t, r.Spans())
+}
+
+func exportRequest() *coltracepb.ExportTraceServiceRequest {
+	return &coltracepb.ExportTraceServiceRequest{
+		ResourceSpans: []*tracepb.ResourceSpans{{
+			Resource: &resourcepb.Resource{Attributes: []*commonpb.KeyValue{
+				{Key: "service.name", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: "cart"}}},
+			}},
+			ScopeSpans: []*tracepb.ScopeSpans{{
+				Spans: []*tracepb.Span{{
+					TraceId: bytes.Repeat([]byte{1}, 16),
+					SpanId:  bytes.Repeat([]byte{2}, 8),
+					Name:    "GET /cart",
+					Attributes: []*commonpb.KeyValue{
+						{Key: "tags", Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{ArrayValue: &commonpb.ArrayValue{Values: []*commonpb.AnyValue{
+							{Value: &commonpb.AnyValue_StringValue{StringValue: "a"}},
+							{Value: &commonpb.AnyValue_IntValue{IntValue: 1}},
+						}}}}},
+					},
+				}},
+			}},
+		}},
+	}
+}
+
+func TestReceiver_HTTP(t *testing.T) {
+	r := startReceiver(t)
+	url := "http://" + r.HTTPAddr() + "/v1/traces"
+
+	protoBody, err := proto.Marshal(exportRequest())
+	assert.NoError(t, err)
+	jsonBody, err := protojson.Marshal(exportRequest())
+	assert.NoError(t, err)
+	var gzipBody bytes.Buffer
+	gw := gzip.NewWriter(&gzipBody)
+	gw.Write(protoBody)
+	gw.Close()
+
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		encoding    string
+		body        []byte
+		wantStatus  int
+		wantSpans   int
+	}{
+		{"Protobuf", http.MethodPost, "application/x-protobuf", "", protoBody, http.StatusOK, 1},
+		{"JSON", http.MethodPost, "application/json", "", jsonBody, http.StatusOK, 1},
+		{"JSON with charset", http.MethodPost, "application/json; charset=utf-8", "", jsonBody, http.StatusOK, 1},
+		{"JSON in upper case", http.MethodPost, "Application/JSON", "", jsonBody, http.StatusOK, 1},
+		{"Gzip", http.MethodPost, "application/x-protobuf", "gzip", gzipBody.Bytes(), http.StatusOK, 1},
+		{"Invalid body", http.MethodPost, "application/json", "", []byte("{"), http.StatusBadRequest, 0},
+		{"Unsupported encoding", http.MethodPost, "application/x-protobuf", "br", protoBody, http.StatusBadRequest, 0},
+		{"Wrong method", http.MethodGet, "", "", nil, http.StatusMethodNotAllowed, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r.Reset()
+			req, err := http.NewRequest(tt.method, url, bytes.NewReader(tt.body))
+			assert.NoError(t, err)
+			req.Header.Set("Content-Type", tt.contentType)
+			if tt.encoding != "" {
+				req.Header.Set("Content-Encoding", tt.encoding)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if !assert.NoError(t, err) {
+				return
+			}
+			resp.Body.Close()
+
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			spans := r.Spans()
+			assert.Len(t, spans, tt.wantSpans)
+			if tt.wantSpans > 0 {
+				assert.Equal(t, "cart", spans[0].Resource.ServiceName())
+				assert.Equal(t, `["a",1]`, spans[0].Attributes["tags"])
+				assert.False(t, spans[0].ParentSpanID.IsValid())
+			}
+		})
+	}
+}
+
+func TestReceiver_WithForward(t *testing.T) {
+	forwarded := make(chan *coltracepb.ExportTraceServiceRequest, 1)
+	r, err := Start("", "127.0.0.1:0", WithForward(func(_ context.Context, req *coltracepb.ExportTraceServiceRequest) {
+		forwarded <- req
+	}))
+	if err != nil {
+		t.Fatalf("Failed to start receiver: %v", err)
+	}
+	t.Cleanup(func() {
+		r.Shutdown(context.Background())
+	})
+	assert.Equal(t, "", r.GRPCAddr())
+
+	body, err := proto.Marshal(exportRequest())
+	assert.NoError(t, err)
+	resp, err := http.Post("http://"+r.HTTPAddr()+"/v1/traces", "application/x-protobuf", bytes.NewReader(body))
+	if !assert.NoError(t, err) {
+		return
+	}
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, r.Spans(), 1, "Spans should be stored before forwarding")
+	req := <-forwarded
+	assert.Equal(t, "GET /cart", req.GetResourceSpans()[0].GetScopeSpans()[0].GetSpans()[0].GetName())
+}
+
+func TestReceiver_WithMaxSpans(t *testing.T) {
+	r, err := Start("", "", WithMaxSpans(2))
+	if err != nil {
+		t.Fatalf("Failed to start receiver: %v", err)
+	}
+
+	for _, name := range []string{"first", "second", "third"} {
+		req := exportRequest()
+		req.GetResourceSpans()[0].GetScopeSpans()[0].GetSpans()[0].Name = name
+		r.Export(req)
+	}
+
+	spans := r.Spans()
+	if assert.Len(t, spans, 2, "The oldest span should be dropped") {
+		assert.Equal(t, "second", spans[0].Name)
+		assert.Equal(t, "third", spans[1].Name)
+	}
+}
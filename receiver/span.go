@@ -0,0 +1,228 @@
+package receiver
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+// Resource is the resource of received spans
+type Resource struct {
+	Attributes map[string]string
+	SchemaURL  string
+}
+
+// ServiceName returns the service.name attribute of the resource
+func (r *Resource) ServiceName() string {
+	return r.Attributes["service.name"]
+}
+
+// Event is an event of a received span
+type Event struct {
+	Name       string
+	Time       time.Time
+	Attributes map[string]string
+}
+
+// Link is a link of a received span
+type Link struct {
+	TraceID    trace.TraceID
+	SpanID     trace.SpanID
+	Attributes map[string]string
+}
+
+// Span is a received span. Attribute values are converted to strings.
+type Span struct {
+	TraceID       trace.TraceID
+	SpanID        trace.SpanID
+	ParentSpanID  trace.SpanID
+	Name          string
+	Kind          trace.SpanKind
+	StartTime     time.Time
+	EndTime       time.Time
+	Attributes    map[string]string
+	Events        []Event
+	Links         []Link
+	StatusCode    codes.Code
+	StatusMessage string
+	// Resource is shared by the spans exported together with the same resource
+	Resource *Resource
+	Scope    string
+}
+
+// Duration returns the duration of the span
+func (s *Span) Duration() time.Duration {
+	return s.EndTime.Sub(s.StartTime)
+}
+
+// Trace is the received spans with the same trace ID
+type Trace struct {
+	TraceID trace.TraceID
+	// Spans are sorted by start time
+	Spans []*Span
+}
+
+// Roots returns the spans whose parent is not received, usually the root span
+func (t *Trace) Roots() []*Span {
+	ids := make(map[trace.SpanID]bool, len(t.Spans))
+	for _, span := range t.Spans {
+		ids[span.SpanID] = true
+	}
+	var roots []*Span
+	for _, span := range t.Spans {
+		if !span.ParentSpanID.IsValid() || !ids[span.ParentSpanID] {
+			roots = append(roots, span)
+		}
+	}
+	return roots
+}
+
+// Children returns the received spans whose parent is the span
+func (t *Trace) Children(parent *Span) []*Span {
+	var children []*Span
+	for _, span := range t.Spans {
+		if span.ParentSpanID == parent.SpanID {
+			children = append(children, span)
+		}
+	}
+	return children
+}
+
+func convertResourceSpans(resourceSpans []*tracepb.ResourceSpans) []*Span {
+	var spans []*Span
+	for _, rs := range resourceSpans {
+		resource := &Resource{
+			Attributes: convertAttributes(rs.GetResource().GetAttributes()),
+			SchemaURL:  rs.GetSchemaUrl(),
+		}
+		for _, ss := range rs.GetScopeSpans() {
+			for _, s := range ss.GetSpans() {
+				spans = append(spans, convertSpan(s, resource, ss.GetScope().GetName()))
+			}
+		}
+	}
+	return spans
+}
+
+func convertSpan(s *tracepb.Span, resource *Resource, scope string) *Span {
+	span := &Span{
+		TraceID:       traceID(s.GetTraceId()),
+		SpanID:        spanID(s.GetSpanId()),
+		ParentSpanID:  spanID(s.GetParentSpanId()),
+		Name:          s.GetName(),
+		Kind:          trace.SpanKind(s.GetKind()),
+		StartTime:     time.Unix(0, int64(s.GetStartTimeUnixNano())),
+		EndTime:       time.Unix(0, int64(s.GetEndTimeUnixNano())),
+		Attributes:    convertAttributes(s.GetAttributes()),
+		StatusCode:    convertStatusCode(s.GetStatus().GetCode()),
+		StatusMessage: s.GetStatus().GetMessage(),
+		Resource:      resource,
+		Scope:         scope,
+	}
+	for _, e := range s.GetEvents() {
+		span.Events = append(span.Events, Event{
+			Name:       e.GetName(),
+			Time:       time.Unix(0, int64(e.GetTimeUnixNano())),
+			Attributes: convertAttributes(e.GetAttributes()),
+		})
+	}
+	for _, l := range s.GetLinks() {
+		span.Links = append(span.Links, Link{
+			TraceID:    traceID(l.GetTraceId()),
+			SpanID:     spanID(l.GetSpanId()),
+			Attributes: convertAttributes(l.GetAttributes()),
+		})
+	}
+	return span
+}
+
+// traceID converts the ID, leaving it invalid (all zero) when it is malformed
+func traceID(b []byte) trace.TraceID {
+	var id trace.TraceID
+	if len(b) == len(id) {
+		copy(id[:], b)
+	}
+	return id
+}
+
+// spanID converts the ID, leaving it invalid (all zero) when it is missing or malformed
+func spanID(b []byte) trace.SpanID {
+	var id trace.SpanID
+	if len(b) == len(id) {
+		copy(id[:], b)
+	}
+	return id
+}
+
+// convertStatusCode maps the OTLP status code, whose numbering differs from the API, to the API code
+func convertStatusCode(code tracepb.Status_StatusCode) codes.Code {
+	switch code {
+	case tracepb.Status_STATUS_CODE_OK:
+		return codes.Ok
+	case tracepb.Status_STATUS_CODE_ERROR:
+		return codes.Error
+	default:
+		return codes.Unset
+	}
+}
+
+func convertAttributes(attrs []*commonpb.KeyValue) map[string]string {
+	result := make(map[string]string, len(attrs))
+	for _, kv := range attrs {
+		result[kv.GetKey()] = anyValueString(kv.GetValue())
+	}
+	return result
+}
+
+// anyValueString returns the value as a string. Arrays and maps are encoded as JSON.
+func anyValueString(v *commonpb.AnyValue) string {
+	switch v.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return v.GetStringValue()
+	case *commonpb.AnyValue_BoolValue:
+		return strconv.FormatBool(v.GetBoolValue())
+	case *commonpb.AnyValue_IntValue:
+		return strconv.FormatInt(v.GetIntValue(), 10)
+	case *commonpb.AnyValue_DoubleValue:
+		return strconv.FormatFloat(v.GetDoubleValue(), 'f', -1, 64)
+	case *commonpb.AnyValue_BytesValue:
+		return base64.StdEncoding.EncodeToString(v.GetBytesValue())
+	case *commonpb.AnyValue_ArrayValue, *commonpb.AnyValue_KvlistValue:
+		b, err := json.Marshal(anyValueInterface(v))
+		if err != nil {
+			return ""
+		}
+		return string(b)
+	}
+	return ""
+}
+
+func anyValueInterface(v *commonpb.AnyValue) any {
+	switch v.GetValue().(type) {
+	case *commonpb.AnyValue_ArrayValue:
+		values := make([]any, 0, len(v.GetArrayValue().GetValues()))
+		for _, value := range v.GetArrayValue().GetValues() {
+			values = append(values, anyValueInterface(value))
+		}
+		return values
+	case *commonpb.AnyValue_KvlistValue:
+		values := make(map[string]any, len(v.GetKvlistValue().GetValues()))
+		for _, kv := range v.GetKvlistValue().GetValues() {
+			values[kv.GetKey()] = anyValueInterface(kv.GetValue())
+		}
+		return values
+	case *commonpb.AnyValue_BoolValue:
+		return v.GetBoolValue()
+	case *commonpb.AnyValue_IntValue:
+		return v.GetIntValue()
+	case *commonpb.AnyValue_DoubleValue:
+		return v.GetDoubleValue()
+	}
+	return anyValueString(v)
+}
@@ -0,0 +1,215 @@
+// Package recorder converts the spans received by the receiver into a scenario
+// which can be loaded into the store, edited and sent again
+package recorder
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ymtdzzz/otelgen/receiver"
+	"github.com/ymtdzzz/otelgen/telemetry"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+var unsafeNameChars = regexp.MustCompile(`[^a-zA-Z0-9_\.\-]`)
+
+// Convert converts the received traces into a scenario. Each received root span becomes a trace
+// named after it, and spans with the same resource attributes share a resource named after
+// the service. It also returns warnings about what could not be converted.
+func Convert(traces []*receiver.Trace) (*telemetry.Scenario, []string) {
+	c := &converter{
+		sc:            &telemetry.Scenario{},
+		traceNames:    make(map[string]bool),
+		resourceNames: make(map[string]bool),
+		resources:     make(map[string]string),
+		spans:         make(map[trace.SpanID]*telemetry.ScenarioSpan),
+	}
+	seen := make(map[trace.SpanID]bool)
+	for _, t := range traces {
+		t = c.dropDuplicateSpans(t, seen)
+		visited := make(map[trace.SpanID]bool, len(t.Spans))
+		for _, root := range t.Roots() {
+			c.sc.Traces = append(c.sc.Traces, telemetry.ScenarioTrace{
+				Name: uniqueName(c.traceNames, root.Name),
+				Root: c.convertSpan(t, root, nil, visited),
+			})
+		}
+		if skipped := len(t.Spans) - len(visited); skipped > 0 {
+			c.warnings = append(c.warnings, fmt.Sprintf("%d spans of trace %s are dropped because their parents form a cycle.", skipped, t.TraceID))
+		}
+	}
+	c.resolveLinks()
+	return c.sc, c.warnings
+}
+
+type converter struct {
+	sc       *telemetry.Scenario
+	warnings []string
+	// traceNames and resourceNames are the names in use, which must be unique
+	traceNames    map[string]bool
+	resourceNames map[string]bool
+	// resources are the resource names by their attributes
+	resources map[string]string
+	// spans are the converted spans by span ID for resolving links
+	spans map[trace.SpanID]*telemetry.ScenarioSpan
+	links []receivedLink
+}
+
+// dropDuplicateSpans returns the trace without the spans whose span ID is already seen,
+// which are received again when an exporter retries an export
+func (c *converter) dropDuplicateSpans(t *receiver.Trace, seen map[trace.SpanID]bool) *receiver.Trace {
+	unique := &receiver.Trace{TraceID: t.TraceID, Spans: make([]*receiver.Span, 0, len(t.Spans))}
+	for _, span := range t.Spans {
+		if seen[span.SpanID] {
+			c.warnings = append(c.warnings, fmt.Sprintf("Span '%s' (%s) is recorded once because it was received more than once.", span.Name, span.SpanID))
+			continue
+		}
+		seen[span.SpanID] = true
+		unique.Spans = append(unique.Spans, span)
+	}
+	return unique
+}
+
+// receivedLink is a link which is converted after all the spans are converted
+type receivedLink struct {
+	span   *receiver.Span
+	target *telemetry.ScenarioSpan
+	index  int
+}
+
+// convertSpan converts the span and its descendants. visited holds the span IDs already converted
+// in the trace so that a cycle in the parents of the received spans does not recurse forever.
+func (c *converter) convertSpan(t *receiver.Trace, span, parent *receiver.Span, visited map[trace.SpanID]bool) *telemetry.ScenarioSpan {
+	visited[span.SpanID] = true
+	ss := &telemetry.ScenarioSpan{
+		Name:       span.Name,
+		Resource:   c.resource(span.Resource),
+		Attributes: maps.Clone(span.Attributes),
+		Duration:   span.Duration().String(),
+	}
+	if span.Kind != trace.SpanKindUnspecified && span.Kind != trace.SpanKindInternal {
+		ss.Kind = span.Kind.String()
+	}
+	if parent != nil {
+		ss.Offset = span.StartTime.Sub(parent.StartTime).String()
+	}
+	if span.StatusCode != codes.Unset {
+		ss.Status = strings.ToLower(span.StatusCode.String())
+		ss.StatusMessage = span.StatusMessage
+	}
+	for _, event := range span.Events {
+		ss.Events = append(ss.Events, telemetry.ScenarioEvent{
+			Name:       event.Name,
+			Attributes: maps.Clone(event.Attributes),
+		})
+	}
+	for i := range span.Links {
+		c.links = append(c.links, receivedLink{span: span, target: ss, index: i})
+	}
+	c.spans[span.SpanID] = ss
+
+	for _, child := range t.Children(span) {
+		if visited[child.SpanID] {
+			continue
+		}
+		ss.Children = append(ss.Children, c.convertSpan(t, child, span, visited))
+	}
+	return ss
+}
+
+// resolveLinks converts the links to the recorded spans, which are given an ID
+func (c *converter) resolveLinks() {
+	for _, l := range c.links {
+		link := l.span.Links[l.index]
+		target, ok := c.spans[link.SpanID]
+		if !ok {
+			c.warnings = append(c.warnings, fmt.Sprintf("Link from span '%s' to %s/%s is dropped because the linked span was not recorded.", l.span.Name, link.TraceID, link.SpanID))
+			continue
+		}
+		target.ID = link.SpanID.String()
+		l.target.Links = append(l.target.Links, telemetry.ScenarioLink{
+			Span:       target.ID,
+			Attributes: maps.Clone(link.Attributes),
+		})
+	}
+}
+
+// resource returns the name of the scenario resource with the attributes, adding it if it is new
+func (c *converter) resource(r *receiver.Resource) string {
+	key := attributesKey(r.Attributes)
+	if name, ok := c.resources[key]; ok {
+		return name
+	}
+	base := r.ServiceName()
+	if base == "" {
+		base = "resource"
+	}
+	name := uniqueName(c.resourceNames, base)
+	c.resources[key] = name
+	c.sc.Resources = append(c.sc.Resources, telemetry.ScenarioResource{
+		Name:       name,
+		Attributes: maps.Clone(r.Attributes),
+	})
+	return name
+}
+
+// uniqueName returns the name which can be typed in commands, suffixed with -2, -3... if it is already used
+func uniqueName(names map[string]bool, name string) string {
+	base := unsafeNameChars.ReplaceAllString(name, "_")
+	if base == "" || !(base[0] == '_' || ('a' <= base[0] && base[0] <= 'z') || ('A' <= base[0] && base[0] <= 'Z')) {
+		base = "_" + base
+	}
+	unique := base
+	for i := 2; names[unique]; i++ {
+		unique = fmt.Sprintf("%s-%d", base, i)
+	}
+	names[unique] = true
+	return unique
+}
+
+func attributesKey(attrs map[string]string) string {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%q=%q;", k, attrs[k])
+	}
+	return b.String()
+}
+
+// Forwarder sends the export requests to an upstream OTLP/gRPC endpoint
+type Forwarder struct {
+	conn   *grpc.ClientConn
+	client coltracepb.TraceServiceClient
+}
+
+// NewForwarder returns a forwarder to the endpoint (e.g. localhost:4317) without TLS
+func NewForwarder(endpoint string) (*Forwarder, error) {
+	conn, err := grpc.NewClient(endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", endpoint, err)
+	}
+	return &Forwarder{conn: conn, client: coltracepb.NewTraceServiceClient(conn)}, nil
+}
+
+// Forward sends the request to the upstream endpoint
+func (f *Forwarder) Forward(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) error {
+	_, err := f.client.Export(ctx, req)
+	return err
+}
+
+// Close closes the connection to the upstream endpoint
+func (f *Forwarder) Close() error {
+	return f.conn.Close()
+}
@@ -0,0 +1,164 @@
+package recorder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/otelgen/receiver"
+	"github.com/ymtdzzz/otelgen/telemetry"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestConvert(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cart := &receiver.Resource{Attributes: map[string]string{"service.name": "cart"}}
+	cartV2 := &receiver.Resource{Attributes: map[string]string{"service.name": "cart", "service.version": "2"}}
+	traceID := trace.TraceID{1}
+	otherTraceID := trace.TraceID{2}
+
+	root := &receiver.Span{
+		TraceID: traceID, SpanID: trace.SpanID{1}, Name: "GET /cart", Kind: trace.SpanKindServer,
+		StartTime: start, EndTime: start.Add(100 * time.Millisecond),
+		Attributes: map[string]string{"http.method": "GET"}, Resource: cart,
+	}
+	child := &receiver.Span{
+		TraceID: traceID, SpanID: trace.SpanID{2}, ParentSpanID: trace.SpanID{1}, Name: "SELECT carts",
+		StartTime: start.Add(10 * time.Millisecond), EndTime: start.Add(40 * time.Millisecond),
+		Attributes: map[string]string{}, Resource: cartV2,
+		StatusCode: codes.Error, StatusMessage: "timeout",
+		Events: []receiver.Event{{Name: "retry", Attributes: map[string]string{"attempt": "2"}}},
+	}
+	linked := &receiver.Span{
+		TraceID: otherTraceID, SpanID: trace.SpanID{3}, Name: "GET /cart",
+		StartTime: start, EndTime: start.Add(time.Millisecond),
+		Attributes: map[string]string{}, Resource: cart,
+		Links: []receiver.Link{
+			{TraceID: traceID, SpanID: trace.SpanID{2}, Attributes: map[string]string{}},
+			{TraceID: otherTraceID, SpanID: trace.SpanID{9}, Attributes: map[string]string{}},
+		},
+	}
+
+	sc, warnings := Convert([]*receiver.Trace{
+		{TraceID: traceID, Spans: []*receiver.Span{root, child}},
+		{TraceID: otherTraceID, Spans: []*receiver.Span{linked}},
+	})
+
+	assert.Equal(t, &telemetry.Scenario{
+		Resources: []telemetry.ScenarioResource{
+			{Name: "cart", Attributes: map[string]string{"service.name": "cart"}},
+			{Name: "cart-2", Attributes: map[string]string{"service.name": "cart", "service.version": "2"}},
+		},
+		Traces: []telemetry.ScenarioTrace{
+			{
+				Name: "GET__cart",
+				Root: &telemetry.ScenarioSpan{
+					Name:       "GET /cart",
+					Resource:   "cart",
+					Attributes: map[string]string{"http.method": "GET"},
+					Kind:       "server",
+					Duration:   "100ms",
+					Children: []*telemetry.ScenarioSpan{
+						{
+							ID:            trace.SpanID{2}.String(),
+							Name:          "SELECT carts",
+							Resource:      "cart-2",
+							Attributes:    map[string]string{},
+							Offset:        "10ms",
+							Duration:      "30ms",
+							Status:        "error",
+							StatusMessage: "timeout",
+							Events: []telemetry.ScenarioEvent{
+								{Name: "retry", Attributes: map[string]string{"attempt": "2"}},
+							},
+						},
+					},
+				},
+			},
+			{
+				Name: "GET__cart-2",
+				Root: &telemetry.ScenarioSpan{
+					Name:       "GET /cart",
+					Resource:   "cart",
+					Attributes: map[string]string{},
+					Duration:   "1ms",
+					Links: []telemetry.ScenarioLink{
+						{Span: trace.SpanID{2}.String(), Attributes: map[string]string{}},
+					},
+				},
+			},
+		},
+	}, sc)
+	assert.Equal(t, []string{
+		"Link from span 'GET /cart' to " + otherTraceID.String() + "/" + trace.SpanID{9}.String() + " is dropped because the linked span was not recorded.",
+	}, warnings)
+
+	s := telemetry.NewStore()
+	assert.NoError(t, s.LoadScenario(sc), "Converted scenario should be loadable")
+}
+
+func TestConvert_CyclicParents(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cart := &receiver.Resource{Attributes: map[string]string{"service.name": "cart"}}
+	traceID := trace.TraceID{1}
+	otherTraceID := trace.TraceID{2}
+
+	span := func(traceID trace.TraceID, id, parent byte, name string) *receiver.Span {
+		return &receiver.Span{
+			TraceID: traceID, SpanID: trace.SpanID{id}, ParentSpanID: trace.SpanID{parent}, Name: name,
+			StartTime: start, EndTime: start.Add(time.Millisecond),
+			Attributes: map[string]string{}, Resource: cart,
+		}
+	}
+
+	sc, warnings := Convert([]*receiver.Trace{
+		{TraceID: traceID, Spans: []*receiver.Span{span(traceID, 1, 1, "loop")}},
+		{TraceID: otherTraceID, Spans: []*receiver.Span{
+			span(otherTraceID, 2, 0, "root"),
+			span(otherTraceID, 3, 4, "ping"),
+			span(otherTraceID, 4, 3, "pong"),
+		}},
+	})
+
+	if assert.Len(t, sc.Traces, 1) {
+		assert.Equal(t, "root", sc.Traces[0].Root.Name)
+		assert.Empty(t, sc.Traces[0].Root.Children)
+	}
+	assert.Equal(t, []string{
+		"1 spans of trace " + traceID.String() + " are dropped because their parents form a cycle.",
+		"2 spans of trace " + otherTraceID.String() + " are dropped because their parents form a cycle.",
+	}, warnings)
+}
+
+func TestConvert_DuplicateSpans(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cart := &receiver.Resource{Attributes: map[string]string{"service.name": "cart"}}
+	traceID := trace.TraceID{1}
+
+	root := &receiver.Span{
+		TraceID: traceID, SpanID: trace.SpanID{1}, Name: "GET /cart",
+		StartTime: start, EndTime: start.Add(100 * time.Millisecond),
+		Attributes: map[string]string{}, Resource: cart,
+	}
+	child := &receiver.Span{
+		TraceID: traceID, SpanID: trace.SpanID{2}, ParentSpanID: trace.SpanID{1}, Name: "SELECT carts",
+		StartTime: start, EndTime: start.Add(10 * time.Millisecond),
+		Attributes: map[string]string{}, Resource: cart,
+	}
+	// an exporter retry sends the same spans again
+	retriedRoot, retriedChild := *root, *child
+
+	sc, warnings := Convert([]*receiver.Trace{
+		{TraceID: traceID, Spans: []*receiver.Span{root, child, &retriedRoot, &retriedChild}},
+	})
+
+	if assert.Len(t, sc.Traces, 1) {
+		assert.Equal(t, "GET /cart", sc.Traces[0].Root.Name)
+		assert.Len(t, sc.Traces[0].Root.Children, 1)
+	}
+	assert.Equal(t, []string{
+		"Span 'GET /cart' (" + trace.SpanID{1}.String() + ") is recorded once because it was received more than once.",
+		"Span 'SELECT carts' (" + trace.SpanID{2}.String() + ") is recorded once because it was received more than once.",
+	}, warnings)
+}
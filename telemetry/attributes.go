@@ -0,0 +1,59 @@
+package telemetry
+
+import (
+	"fmt"
+	"maps"
+)
+
+// PatchSpanAttributes sets the attributes in add and removes the keys in remove,
+// keeping the other attributes of the span
+func (s *Store) PatchSpanAttributes(ref string, add map[string]string, remove []string) (*Span, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	span, err := s.lookupSpan(ref)
+	if err != nil {
+		return nil, err
+	}
+	s.changing()
+	span.Attributes = patchAttributes(span.Attributes, add, remove)
+	return span, nil
+}
+
+// PatchResourceAttributes sets the attributes in add and removes the keys in remove,
+// keeping the other attributes of the resource
+func (s *Store) PatchResourceAttributes(name string, add map[string]string, remove []string) (*Resource, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resource, ok := s.resources[name]
+	if !ok {
+		return nil, fmt.Errorf("resource %s not found", name)
+	}
+	s.changing()
+	resource.Attributes = patchAttributes(resource.Attributes, add, remove)
+	return resource, nil
+}
+
+// PatchEventAttributes sets the attributes in add and removes the keys in remove,
+// keeping the other attributes of the event
+func (s *Store) PatchEventAttributes(name string, add map[string]string, remove []string) (*Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	event, ok := s.events[name]
+	if !ok {
+		return nil, fmt.Errorf("event %s not found", name)
+	}
+	s.changing()
+	event.Attributes = patchAttributes(event.Attributes, add, remove)
+	return event, nil
+}
+
+// patchAttributes returns a copy of attrs with the attributes in add set and the keys in remove deleted
+func patchAttributes(attrs map[string]string, add map[string]string, remove []string) map[string]string {
+	patched := make(map[string]string, len(attrs)+len(add))
+	maps.Copy(patched, attrs)
+	maps.Copy(patched, add)
+	for _, key := range remove {
+		delete(patched, key)
+	}
+	return patched
+}
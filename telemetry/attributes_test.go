@@ -0,0 +1,46 @@
+package telemetry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatchAttributes(t *testing.T) {
+	t.Run("Span", func(t *testing.T) {
+		InitStore()
+		CreateTrace("trace1")
+		AddSpanToTrace("trace1", "root", map[string]string{"a": "1", "b": "2"})
+
+		span, err := PatchSpanAttributes("root", map[string]string{"b": "20", "c": "3"}, []string{"a", "unknown"})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"b": "20", "c": "3"}, span.Attributes)
+
+		_, err = PatchSpanAttributes("unknown", nil, nil)
+		assert.EqualError(t, err, "span unknown not found")
+	})
+
+	t.Run("Resource", func(t *testing.T) {
+		InitStore()
+		CreateResource("db", nil)
+
+		resource, err := PatchResourceAttributes("db", map[string]string{"a": "1"}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"a": "1"}, resource.Attributes)
+
+		_, err = PatchResourceAttributes("unknown", nil, nil)
+		assert.EqualError(t, err, "resource unknown not found")
+	})
+
+	t.Run("Event", func(t *testing.T) {
+		InitStore()
+		attrs := map[string]string{"a": "1", "b": "2"}
+		CreateEvent("retry", attrs)
+
+		event, err := PatchEventAttributes("retry", nil, []string{"b"})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"a": "1"}, event.Attributes)
+		assert.Equal(t, map[string]string{"a": "1", "b": "2"}, attrs, "Original map should not be modified")
+	})
+
+}
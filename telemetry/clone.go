@@ -0,0 +1,103 @@
+package telemetry
+
+import (
+	"fmt"
+	"maps"
+)
+
+// CloneSpan deep-copies the span with its descendants count times under the parent span.
+// The copies of the span are named <prefix>_1..<prefix>_N and their descendants
+// <name>_1..<name>_N. When parentRef is empty, the parent of the original span is used.
+// Resources and events are shared with the original, and links pointing inside the cloned
+// subtree are redirected to the corresponding copy.
+func (s *Store) CloneSpan(ref, prefix string, count int, parentRef string) ([]*Span, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	span, err := s.lookupSpan(ref)
+	if err != nil {
+		return nil, err
+	}
+	if count < 1 {
+		return nil, fmt.Errorf("count must be greater than 0")
+	}
+
+	var parent *Span
+	if parentRef != "" {
+		parent, err = s.lookupSpan(parentRef)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		parent = s.parentOf(span)
+		if parent == nil {
+			return nil, fmt.Errorf("span %s is a root span, parent span must be specified", ref)
+		}
+	}
+
+	// copy all the subtrees first so that cloning under a descendant doesn't copy the clones
+	clones := make([]*Span, 0, count)
+	copiesList := make([]map[*Span]*Span, 0, count)
+	for i := 1; i <= count; i++ {
+		copies := make(map[*Span]*Span)
+		clones = append(clones, copySpan(span, func(s *Span) string {
+			return cloneName(span, s, prefix, i)
+		}, copies))
+		copiesList = append(copiesList, copies)
+	}
+
+	s.changing()
+	traceName := s.traceNameOf(parent)
+	for i, clone := range clones {
+		copies := copiesList[i]
+		for original, copied := range copies {
+			for _, link := range original.Links {
+				target := link.TargetSpan
+				if t, ok := copies[target]; ok {
+					target = t
+				}
+				copied.AddLink(target, maps.Clone(link.Attributes))
+			}
+		}
+		parent.AddChild(clone)
+		walkSpan(clone, func(sp *Span) {
+			s.registerSpan(sp, traceName)
+		})
+	}
+
+	return clones, nil
+}
+
+func cloneName(root, s *Span, prefix string, i int) string {
+	if s == root {
+		return fmt.Sprintf("%s_%d", prefix, i)
+	}
+	return fmt.Sprintf("%s_%d", s.Name, i)
+}
+
+// copySpan deep-copies the span and its descendants except for links.
+// copies maps each original span to its copy.
+func copySpan(s *Span, nameFn func(*Span) string, copies map[*Span]*Span) *Span {
+	copied := &Span{
+		Name:       nameFn(s),
+		Attributes: maps.Clone(s.Attributes),
+		Resource:   s.Resource,
+		Events:     append([]*Event(nil), s.Events...),
+		Timing:     s.Timing,
+		Status:     s.Status,
+		Duration:   s.Duration,
+		Kind:       s.Kind,
+	}
+	copies[s] = copied
+	for _, child := range s.Children {
+		copied.AddChild(copySpan(child, nameFn, copies))
+	}
+	return copied
+}
+
+// walkSpan calls fn for the span and all its descendants in depth-first order
+func walkSpan(s *Span, fn func(*Span)) {
+	fn(s)
+	for _, child := range s.Children {
+		walkSpan(child, fn)
+	}
+}
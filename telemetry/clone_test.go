@@ -0,0 +1,84 @@
+package telemetry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupCloneStore() {
+	InitStore()
+	CreateTrace("trace1")
+	AddSpanToTrace("trace1", "handler", map[string]string{})
+	AddSpanToSpan("handler", "db_query", map[string]string{"db.system": "postgresql"})
+	AddSpanToSpan("db_query", "connect", map[string]string{})
+	AddSpanToSpan("handler", "other", map[string]string{})
+	AddLinkToSpan("db_query", "connect", map[string]string{"key": "value"})
+	AddLinkToSpan("db_query", "other", map[string]string{})
+
+	CreateResource("db", map[string]string{})
+	SetResourceToSpan("db_query", "db")
+	CreateEvent("retry", map[string]string{})
+	AddEventToSpan("db_query", "retry")
+}
+
+func TestCloneSpan(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		setupCloneStore()
+
+		clones, err := CloneSpan("db_query", "db", 3, "")
+		assert.NoError(t, err)
+		assert.Len(t, clones, 3)
+
+		spans := GetSpans()
+		handler := spans["trace1/handler"]
+		assert.Len(t, handler.Children, 5, "Clones should be added under the parent of the original")
+		assert.Len(t, spans, 10)
+
+		for i, name := range []string{"db_1", "db_2", "db_3"} {
+			clone := spans["trace1/"+name]
+			assert.Equal(t, clones[i], clone)
+			assert.Equal(t, map[string]string{"db.system": "postgresql"}, clone.Attributes)
+			assert.Equal(t, GetResources()["db"], clone.Resource, "Resource should be shared")
+			assert.Equal(t, GetEvents()["retry"], clone.Events[0], "Events should be shared")
+			assert.Len(t, clone.Children, 1)
+		}
+
+		clone := spans["trace1/db_2"]
+		assert.Equal(t, spans["trace1/connect_2"], clone.Children[0])
+		assert.Equal(t, spans["trace1/connect_2"], clone.Links[0].TargetSpan, "Link inside the subtree should point at the copy")
+		assert.Equal(t, "value", clone.Links[0].Attributes["key"])
+		assert.Equal(t, spans["trace1/other"], clone.Links[1].TargetSpan, "Link outside the subtree should be kept")
+
+		clone.Attributes["db.system"] = "mysql"
+		assert.Equal(t, "postgresql", spans["trace1/db_query"].Attributes["db.system"], "Attributes should be copied")
+	})
+
+	t.Run("Under", func(t *testing.T) {
+		setupCloneStore()
+
+		_, err := CloneSpan("db_query", "nested", 2, "connect")
+		assert.NoError(t, err)
+
+		connect := GetSpans()["trace1/connect"]
+		assert.Len(t, connect.Children, 2)
+		assert.Len(t, connect.Children[1].Children, 1, "Clones should not contain the other clones")
+	})
+
+	t.Run("RootSpan", func(t *testing.T) {
+		setupCloneStore()
+
+		_, err := CloneSpan("handler", "h", 1, "")
+		assert.EqualError(t, err, "span handler is a root span, parent span must be specified")
+	})
+
+	t.Run("DuplicateName", func(t *testing.T) {
+		setupCloneStore()
+		AddSpanToSpan("other", "connect_2", map[string]string{})
+
+		_, err := CloneSpan("db_query", "db", 3, "")
+		assert.NoError(t, err)
+		assert.Len(t, GetSpans(), 11)
+		assert.Equal(t, "connect_2", GetSpans()["trace1/connect_2-2"].Name, "Clone should get a unique handle")
+	})
+}
@@ -0,0 +1,345 @@
+package telemetry
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ConventionParam is a parameter of a span convention, e.g. the method of an HTTP request
+type ConventionParam struct {
+	Name        string
+	Required    bool
+	Description string
+}
+
+// SpanConvention fills the kind, the name and the attributes of a span following the
+// semantic conventions of an operation, e.g. an HTTP server request. The attribute keys
+// and the name format depend on the semantic conventions version.
+type SpanConvention struct {
+	Name        string
+	Description string
+	// Kinds are the span kinds which the convention can be applied to, the first one is the default
+	Kinds  []trace.SpanKind
+	Params []ConventionParam
+	// spanName returns the span name from the parameters
+	spanName func(c conventionContext) string
+	// attributes returns the attributes from the parameters
+	attributes func(c conventionContext) map[string]string
+}
+
+// ConventionSpan is the kind, the name and the attributes of a span filled by a convention
+type ConventionSpan struct {
+	Name       string
+	Kind       trace.SpanKind
+	Attributes map[string]string
+}
+
+// conventionContext is what a convention is applied with
+type conventionContext struct {
+	params  map[string]string
+	kind    trace.SpanKind
+	version string
+}
+
+// atLeast reports whether the semantic conventions version is the given one or later
+func (c conventionContext) atLeast(version string) bool {
+	return semconvIndex(c.version) >= semconvIndex(version)
+}
+
+// set sets the parameter to the attribute if the parameter is specified
+func (c conventionContext) set(attrs map[string]string, key, param string) {
+	if v, ok := c.params[param]; ok {
+		attrs[key] = v
+	}
+}
+
+// semconvIndex returns the position of the version in the supported versions, or -1
+func semconvIndex(version string) int {
+	for i, v := range semconvVersions {
+		if v.version == version {
+			return i
+		}
+	}
+	return -1
+}
+
+var spanConventions = []*SpanConvention{
+	{
+		Name:        "http.server",
+		Description: "HTTP request handled by a server",
+		Kinds:       []trace.SpanKind{trace.SpanKindServer},
+		Params: []ConventionParam{
+			{Name: "method", Required: true, Description: "HTTP request method, e.g. GET"},
+			{Name: "route", Description: "matched route, e.g. /users/:id"},
+			{Name: "path", Description: "path of the request URL, e.g. /users/42"},
+			{Name: "status", Description: "HTTP response status code, e.g. 200"},
+		},
+		spanName: func(c conventionContext) string {
+			if route, ok := c.params["route"]; ok {
+				return c.params["method"] + " " + route
+			}
+			return c.params["method"]
+		},
+		attributes: func(c conventionContext) map[string]string {
+			attrs := map[string]string{}
+			c.set(attrs, "http.route", "route")
+			if c.atLeast("v1.21.0") {
+				c.set(attrs, "http.request.method", "method")
+				c.set(attrs, "url.path", "path")
+				c.set(attrs, "http.response.status_code", "status")
+			} else {
+				c.set(attrs, "http.method", "method")
+				c.set(attrs, "http.target", "path")
+				c.set(attrs, "http.status_code", "status")
+			}
+			return attrs
+		},
+	},
+	{
+		Name:        "http.client",
+		Description: "HTTP request sent by a client",
+		Kinds:       []trace.SpanKind{trace.SpanKindClient},
+		Params: []ConventionParam{
+			{Name: "method", Required: true, Description: "HTTP request method, e.g. GET"},
+			{Name: "url", Description: "full request URL, e.g. http://api:8080/users"},
+			{Name: "status", Description: "HTTP response status code, e.g. 200"},
+		},
+		spanName: func(c conventionContext) string {
+			return c.params["method"]
+		},
+		attributes: func(c conventionContext) map[string]string {
+			attrs := map[string]string{}
+			var host string
+			if u, err := url.Parse(c.params["url"]); err == nil {
+				host = u.Hostname()
+			}
+			if c.atLeast("v1.21.0") {
+				c.set(attrs, "http.request.method", "method")
+				c.set(attrs, "url.full", "url")
+				c.set(attrs, "http.response.status_code", "status")
+				if host != "" {
+					attrs["server.address"] = host
+				}
+			} else {
+				c.set(attrs, "http.method", "method")
+				c.set(attrs, "http.url", "url")
+				c.set(attrs, "http.status_code", "status")
+				if host != "" {
+					attrs["net.peer.name"] = host
+				}
+			}
+			return attrs
+		},
+	},
+	{
+		Name:        "db",
+		Description: "database call made by a client",
+		Kinds:       []trace.SpanKind{trace.SpanKindClient},
+		Params: []ConventionParam{
+			{Name: "system", Required: true, Description: "database management system, e.g. postgresql"},
+			{Name: "name", Description: "database name, e.g. shop"},
+			{Name: "operation", Description: "operation, e.g. SELECT"},
+			{Name: "table", Description: "table or collection, e.g. users"},
+			{Name: "statement", Description: "query text, e.g. \"SELECT * FROM users\""},
+		},
+		spanName: func(c conventionContext) string {
+			operation, hasOperation := c.params["operation"]
+			target, hasTarget := c.params["table"]
+			if !hasTarget {
+				target, hasTarget = c.params["name"]
+			}
+			switch {
+			case hasOperation && hasTarget:
+				return operation + " " + target
+			case hasOperation:
+				return operation
+			case hasTarget:
+				return target
+			}
+			return c.params["system"]
+		},
+		attributes: func(c conventionContext) map[string]string {
+			attrs := map[string]string{}
+			if c.atLeast("v1.30.0") {
+				c.set(attrs, "db.system.name", "system")
+			} else {
+				c.set(attrs, "db.system", "system")
+			}
+			if c.atLeast("v1.26.0") {
+				c.set(attrs, "db.namespace", "name")
+				c.set(attrs, "db.operation.name", "operation")
+				c.set(attrs, "db.collection.name", "table")
+				c.set(attrs, "db.query.text", "statement")
+			} else {
+				c.set(attrs, "db.name", "name")
+				c.set(attrs, "db.operation", "operation")
+				c.set(attrs, "db.sql.table", "table")
+				c.set(attrs, "db.statement", "statement")
+			}
+			return attrs
+		},
+	},
+	{
+		Name:        "rpc.grpc",
+		Description: "gRPC call made by a client or handled by a server",
+		Kinds:       []trace.SpanKind{trace.SpanKindClient, trace.SpanKindServer},
+		Params: []ConventionParam{
+			{Name: "service", Required: true, Description: "full name of the service, e.g. shop.Cart"},
+			{Name: "method", Required: true, Description: "name of the method, e.g. AddItem"},
+			{Name: "status", Description: "numeric gRPC status code, e.g. 0"},
+		},
+		spanName: func(c conventionContext) string {
+			return c.params["service"] + "/" + c.params["method"]
+		},
+		attributes: func(c conventionContext) map[string]string {
+			attrs := map[string]string{"rpc.system": "grpc"}
+			c.set(attrs, "rpc.service", "service")
+			c.set(attrs, "rpc.method", "method")
+			c.set(attrs, "rpc.grpc.status_code", "status")
+			return attrs
+		},
+	},
+	{
+		Name:        "messaging.kafka",
+		Description: "Kafka message sent by a producer or received by a consumer",
+		Kinds:       []trace.SpanKind{trace.SpanKindProducer, trace.SpanKindConsumer},
+		Params: []ConventionParam{
+			{Name: "topic", Required: true, Description: "topic name, e.g. orders"},
+			{Name: "key", Description: "message key, e.g. order-42"},
+			{Name: "partition", Description: "partition number, e.g. 3"},
+		},
+		spanName: func(c conventionContext) string {
+			operation := kafkaOperation(c)
+			if operation == "" {
+				operation = "send"
+			}
+			if c.atLeast("v1.26.0") {
+				return operation + " " + c.params["topic"]
+			}
+			return c.params["topic"] + " " + operation
+		},
+		attributes: func(c conventionContext) map[string]string {
+			attrs := map[string]string{"messaging.system": "kafka"}
+			operation := kafkaOperation(c)
+			switch {
+			case c.atLeast("v1.26.0"):
+				c.set(attrs, "messaging.destination.name", "topic")
+				c.set(attrs, "messaging.destination.partition.id", "partition")
+				c.set(attrs, "messaging.kafka.message.key", "key")
+				attrs["messaging.operation.type"] = operation
+				attrs["messaging.operation.name"] = operation
+			case c.atLeast("v1.17.0"):
+				c.set(attrs, "messaging.destination.name", "topic")
+				c.set(attrs, "messaging.kafka.destination.partition", "partition")
+				c.set(attrs, "messaging.kafka.message.key", "key")
+				attrs["messaging.operation"] = operation
+			default:
+				c.set(attrs, "messaging.destination", "topic")
+				c.set(attrs, "messaging.kafka.partition", "partition")
+				c.set(attrs, "messaging.kafka.message_key", "key")
+				// sending is the default operation, which has no value before v1.17.0
+				if operation != "" {
+					attrs["messaging.operation"] = operation
+				}
+			}
+			return attrs
+		},
+	},
+}
+
+// kafkaOperation returns the messaging operation of the kind for the version,
+// which is empty for sending before v1.17.0
+func kafkaOperation(c conventionContext) string {
+	switch {
+	case c.kind == trace.SpanKindConsumer:
+		return "receive"
+	case c.atLeast("v1.30.0"):
+		return "send"
+	case c.atLeast("v1.17.0"):
+		return "publish"
+	}
+	return ""
+}
+
+// SpanConventions returns the span conventions sorted by name
+func SpanConventions() []*SpanConvention {
+	conventions := slices.Clone(spanConventions)
+	sort.Slice(conventions, func(i, j int) bool {
+		return conventions[i].Name < conventions[j].Name
+	})
+	return conventions
+}
+
+// LookupSpanConvention returns the span convention with the name
+func LookupSpanConvention(name string) (*SpanConvention, error) {
+	for _, c := range spanConventions {
+		if c.Name == name {
+			return c, nil
+		}
+	}
+	names := make([]string, 0, len(spanConventions))
+	for _, c := range SpanConventions() {
+		names = append(names, c.Name)
+	}
+	return nil, fmt.Errorf("unknown span convention %s (%s)", name, strings.Join(names, ", "))
+}
+
+// ValidateParams checks that the parameters are known and the required ones are specified
+func (c *SpanConvention) ValidateParams(params map[string]string) error {
+	names := make([]string, 0, len(c.Params))
+	for _, p := range c.Params {
+		names = append(names, p.Name)
+	}
+	for name := range params {
+		if !slices.Contains(names, name) {
+			return fmt.Errorf("unknown parameter %s of span convention %s (%s)", name, c.Name, strings.Join(names, ", "))
+		}
+	}
+	for _, p := range c.Params {
+		if _, ok := params[p.Name]; p.Required && !ok {
+			return fmt.Errorf("parameter %s of span convention %s must be specified", p.Name, c.Name)
+		}
+	}
+	return nil
+}
+
+// ValidateKind checks that the convention can be applied to a span of the kind
+func (c *SpanConvention) ValidateKind(kind trace.SpanKind) error {
+	if slices.Contains(c.Kinds, kind) {
+		return nil
+	}
+	kinds := make([]string, 0, len(c.Kinds))
+	for _, k := range c.Kinds {
+		kinds = append(kinds, k.String())
+	}
+	return fmt.Errorf("span convention %s cannot be applied to a %s span (%s)", c.Name, kind, strings.Join(kinds, " or "))
+}
+
+// Apply returns the span name, the kind and the attributes for the parameters following the
+// semantic conventions version. An unspecified kind is the default kind of the convention.
+func (c *SpanConvention) Apply(params map[string]string, kind trace.SpanKind, version string) (*ConventionSpan, error) {
+	if !IsSemconvVersionSupported(version) {
+		return nil, fmt.Errorf("unsupported semconv version: %s", version)
+	}
+	if err := c.ValidateParams(params); err != nil {
+		return nil, err
+	}
+	if kind == trace.SpanKindUnspecified {
+		kind = c.Kinds[0]
+	}
+	if err := c.ValidateKind(kind); err != nil {
+		return nil, err
+	}
+
+	ctx := conventionContext{params: params, kind: kind, version: version}
+	return &ConventionSpan{
+		Name:       c.spanName(ctx),
+		Kind:       kind,
+		Attributes: c.attributes(ctx),
+	}, nil
+}
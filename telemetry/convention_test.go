@@ -0,0 +1,244 @@
+package telemetry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSpanConventionApply(t *testing.T) {
+	tests := []struct {
+		name       string
+		convention string
+		params     map[string]string
+		kind       trace.SpanKind
+		version    string
+		want       *ConventionSpan
+		wantErr    string
+	}{
+		{
+			name:       "HTTP server with stable attributes",
+			convention: "http.server",
+			params:     map[string]string{"method": "GET", "route": "/users", "status": "200"},
+			version:    "v1.26.0",
+			want: &ConventionSpan{
+				Name: "GET /users",
+				Kind: trace.SpanKindServer,
+				Attributes: map[string]string{
+					"http.request.method":       "GET",
+					"http.route":                "/users",
+					"http.response.status_code": "200",
+				},
+			},
+		},
+		{
+			name:       "HTTP server before v1.21.0",
+			convention: "http.server",
+			params:     map[string]string{"method": "POST", "path": "/users/42", "status": "201"},
+			version:    "v1.20.0",
+			want: &ConventionSpan{
+				Name: "POST",
+				Kind: trace.SpanKindServer,
+				Attributes: map[string]string{
+					"http.method":      "POST",
+					"http.target":      "/users/42",
+					"http.status_code": "201",
+				},
+			},
+		},
+		{
+			name:       "HTTP client with the server address from the URL",
+			convention: "http.client",
+			params:     map[string]string{"method": "GET", "url": "http://api:8080/users"},
+			version:    "v1.32.0",
+			want: &ConventionSpan{
+				Name: "GET",
+				Kind: trace.SpanKindClient,
+				Attributes: map[string]string{
+					"http.request.method": "GET",
+					"url.full":            "http://api:8080/users",
+					"server.address":      "api",
+				},
+			},
+		},
+		{
+			name:       "HTTP client before v1.21.0",
+			convention: "http.client",
+			params:     map[string]string{"method": "GET", "url": "http://api/users", "status": "404"},
+			version:    "v1.4.0",
+			want: &ConventionSpan{
+				Name: "GET",
+				Kind: trace.SpanKindClient,
+				Attributes: map[string]string{
+					"http.method":      "GET",
+					"http.url":         "http://api/users",
+					"http.status_code": "404",
+					"net.peer.name":    "api",
+				},
+			},
+		},
+		{
+			name:       "Database with v1.26.0 attributes",
+			convention: "db",
+			params:     map[string]string{"system": "postgresql", "name": "shop", "operation": "SELECT", "table": "users"},
+			version:    "v1.26.0",
+			want: &ConventionSpan{
+				Name: "SELECT users",
+				Kind: trace.SpanKindClient,
+				Attributes: map[string]string{
+					"db.system":          "postgresql",
+					"db.namespace":       "shop",
+					"db.operation.name":  "SELECT",
+					"db.collection.name": "users",
+				},
+			},
+		},
+		{
+			name:       "Database with db.system.name",
+			convention: "db",
+			params:     map[string]string{"system": "redis"},
+			version:    "v1.30.0",
+			want: &ConventionSpan{
+				Name:       "redis",
+				Kind:       trace.SpanKindClient,
+				Attributes: map[string]string{"db.system.name": "redis"},
+			},
+		},
+		{
+			name:       "Database before v1.26.0",
+			convention: "db",
+			params:     map[string]string{"system": "mysql", "name": "shop", "statement": "SELECT 1"},
+			version:    "v1.24.0",
+			want: &ConventionSpan{
+				Name: "shop",
+				Kind: trace.SpanKindClient,
+				Attributes: map[string]string{
+					"db.system":    "mysql",
+					"db.name":      "shop",
+					"db.statement": "SELECT 1",
+				},
+			},
+		},
+		{
+			name:       "gRPC server",
+			convention: "rpc.grpc",
+			params:     map[string]string{"service": "shop.Cart", "method": "AddItem", "status": "0"},
+			kind:       trace.SpanKindServer,
+			version:    "v1.26.0",
+			want: &ConventionSpan{
+				Name: "shop.Cart/AddItem",
+				Kind: trace.SpanKindServer,
+				Attributes: map[string]string{
+					"rpc.system":           "grpc",
+					"rpc.service":          "shop.Cart",
+					"rpc.method":           "AddItem",
+					"rpc.grpc.status_code": "0",
+				},
+			},
+		},
+		{
+			name:       "Kafka producer",
+			convention: "messaging.kafka",
+			params:     map[string]string{"topic": "orders", "partition": "3"},
+			version:    "v1.30.0",
+			want: &ConventionSpan{
+				Name: "send orders",
+				Kind: trace.SpanKindProducer,
+				Attributes: map[string]string{
+					"messaging.system":                   "kafka",
+					"messaging.destination.name":         "orders",
+					"messaging.destination.partition.id": "3",
+					"messaging.operation.type":           "send",
+					"messaging.operation.name":           "send",
+				},
+			},
+		},
+		{
+			name:       "Kafka consumer before v1.26.0",
+			convention: "messaging.kafka",
+			params:     map[string]string{"topic": "orders", "key": "order-42"},
+			kind:       trace.SpanKindConsumer,
+			version:    "v1.24.0",
+			want: &ConventionSpan{
+				Name: "orders receive",
+				Kind: trace.SpanKindConsumer,
+				Attributes: map[string]string{
+					"messaging.system":            "kafka",
+					"messaging.destination.name":  "orders",
+					"messaging.kafka.message.key": "order-42",
+					"messaging.operation":         "receive",
+				},
+			},
+		},
+		{
+			name:       "Kafka producer before v1.17.0",
+			convention: "messaging.kafka",
+			params:     map[string]string{"topic": "orders", "key": "order-42"},
+			version:    "v1.12.0",
+			want: &ConventionSpan{
+				Name: "orders send",
+				Kind: trace.SpanKindProducer,
+				Attributes: map[string]string{
+					"messaging.system":            "kafka",
+					"messaging.destination":       "orders",
+					"messaging.kafka.message_key": "order-42",
+				},
+			},
+		},
+		{
+			name:       "Missing required parameter",
+			convention: "rpc.grpc",
+			params:     map[string]string{"service": "shop.Cart"},
+			version:    "v1.26.0",
+			wantErr:    "parameter method of span convention rpc.grpc must be specified",
+		},
+		{
+			name:       "Unknown parameter",
+			convention: "http.server",
+			params:     map[string]string{"method": "GET", "code": "200"},
+			version:    "v1.26.0",
+			wantErr:    "unknown parameter code of span convention http.server (method, route, path, status)",
+		},
+		{
+			name:       "Kind the convention cannot be applied to",
+			convention: "messaging.kafka",
+			params:     map[string]string{"topic": "orders"},
+			kind:       trace.SpanKindServer,
+			version:    "v1.26.0",
+			wantErr:    "span convention messaging.kafka cannot be applied to a server span (producer or consumer)",
+		},
+		{
+			name:       "Unsupported version",
+			convention: "db",
+			params:     map[string]string{"system": "mysql"},
+			version:    "v9.9.9",
+			wantErr:    "unsupported semconv version: v9.9.9",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := LookupSpanConvention(tt.convention)
+			assert.NoError(t, err)
+			got, err := c.Apply(tt.params, tt.kind, tt.version)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLookupSpanConvention(t *testing.T) {
+	_, err := LookupSpanConvention("http")
+	assert.EqualError(t, err, "unknown span convention http (db, http.client, http.server, messaging.kafka, rpc.grpc)")
+
+	var names []string
+	for _, c := range SpanConventions() {
+		names = append(names, c.Name)
+	}
+	assert.Equal(t, []string{"db", "http.client", "http.server", "messaging.kafka", "rpc.grpc"}, names)
+}
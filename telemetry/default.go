@@ -0,0 +1,320 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// defaultStore is the store used by the package-level functions
+var defaultStore = NewStore()
+
+// DefaultStore returns the store used by the package-level functions
+func DefaultStore() *Store {
+	return defaultStore
+}
+
+// InitStore resets the default store
+func InitStore() {
+	defaultStore.Reset()
+}
+
+func GetTraces() map[string]*Trace {
+	return defaultStore.GetTraces()
+}
+
+func GetSpans() map[string]*Span {
+	return defaultStore.GetSpans()
+}
+
+func GetResources() map[string]*Resource {
+	return defaultStore.GetResources()
+}
+
+func GetEvents() map[string]*Event {
+	return defaultStore.GetEvents()
+}
+
+func IsTraceExists(name string) bool {
+	return defaultStore.IsTraceExists(name)
+}
+
+func IsSpanExists(ref string) bool {
+	return defaultStore.IsSpanExists(ref)
+}
+
+func IsResourceExists(name string) bool {
+	return defaultStore.IsResourceExists(name)
+}
+
+func IsEventExists(name string) bool {
+	return defaultStore.IsEventExists(name)
+}
+
+func LookupSpan(ref string) (*Span, error) {
+	return defaultStore.LookupSpan(ref)
+}
+
+func SpanRef(span *Span) string {
+	return defaultStore.SpanRef(span)
+}
+
+func CreateTrace(name string) *Trace {
+	return defaultStore.CreateTrace(name)
+}
+
+func AddTrace(name string, root *Span) (*Trace, error) {
+	return defaultStore.AddTrace(name, root)
+}
+
+func SetTraceInheritResource(name string, inherit *bool) (*Trace, error) {
+	return defaultStore.SetTraceInheritResource(name, inherit)
+}
+
+func UpdateSpan(ref, newName, resource string, attributes map[string]string) (*Span, error) {
+	return defaultStore.UpdateSpan(ref, newName, resource, attributes)
+}
+
+func SetSpanDuration(ref string, duration *Distribution) (*Span, error) {
+	return defaultStore.SetSpanDuration(ref, duration)
+}
+
+func SetSpanKind(ref string, kind trace.SpanKind) (*Span, error) {
+	return defaultStore.SetSpanKind(ref, kind)
+}
+
+func CreateResource(name string, attributes map[string]string) *Resource {
+	return defaultStore.CreateResource(name, attributes)
+}
+
+func UpdateResource(name, newName string, attributes map[string]string) (*Resource, error) {
+	return defaultStore.UpdateResource(name, newName, attributes)
+}
+
+func SetResourceSemantics(name, semconvVersion string, mergeDefaults *bool) (*Resource, error) {
+	return defaultStore.SetResourceSemantics(name, semconvVersion, mergeDefaults)
+}
+
+func CreateEvent(name string, attributes map[string]string) *Event {
+	return defaultStore.CreateEvent(name, attributes)
+}
+
+func UpdateEvent(name, newName string, attributes map[string]string) (*Event, error) {
+	return defaultStore.UpdateEvent(name, newName, attributes)
+}
+
+func AddSpanToTrace(traceName, spanName string, attributes map[string]string) (*Span, error) {
+	return defaultStore.AddSpanToTrace(traceName, spanName, attributes)
+}
+
+func AddSpanToSpan(parentSpanRef, spanName string, attributes map[string]string) (*Span, error) {
+	return defaultStore.AddSpanToSpan(parentSpanRef, spanName, attributes)
+}
+
+func AddLinkToSpan(from, to string, attributes map[string]string) (*Span, error) {
+	return defaultStore.AddLinkToSpan(from, to, attributes)
+}
+
+func AddEventToSpan(spanRef, eventName string) (*Event, error) {
+	return defaultStore.AddEventToSpan(spanRef, eventName)
+}
+
+func SetResourceToSpan(spanRef, resourceName string) (*Resource, error) {
+	return defaultStore.SetResourceToSpan(spanRef, resourceName)
+}
+
+func PatchSpanAttributes(ref string, add map[string]string, remove []string) (*Span, error) {
+	return defaultStore.PatchSpanAttributes(ref, add, remove)
+}
+
+func PatchResourceAttributes(name string, add map[string]string, remove []string) (*Resource, error) {
+	return defaultStore.PatchResourceAttributes(name, add, remove)
+}
+
+func PatchEventAttributes(name string, add map[string]string, remove []string) (*Event, error) {
+	return defaultStore.PatchEventAttributes(name, add, remove)
+}
+
+func SetLinkAttributes(ref LinkRef, attributes map[string]string) (int, error) {
+	return defaultStore.SetLinkAttributes(ref, attributes)
+}
+
+func PatchLinkAttributes(ref LinkRef, add map[string]string, remove []string) (int, error) {
+	return defaultStore.PatchLinkAttributes(ref, add, remove)
+}
+
+func SpansWithLinks() []*Span {
+	return defaultStore.SpansWithLinks()
+}
+
+func DeleteTrace(name string) (*DeleteResult, error) {
+	return defaultStore.DeleteTrace(name)
+}
+
+func DeleteSpan(ref string) (*DeleteResult, error) {
+	return defaultStore.DeleteSpan(ref)
+}
+
+func DeleteResource(name string) (*DeleteResult, error) {
+	return defaultStore.DeleteResource(name)
+}
+
+func DeleteEvent(name string) (*DeleteResult, error) {
+	return defaultStore.DeleteEvent(name)
+}
+
+func DeleteLinks(ref LinkRef) (*DeleteResult, error) {
+	return defaultStore.DeleteLinks(ref)
+}
+
+func MoveSpanUnder(ref, parentRef string) (*Span, error) {
+	return defaultStore.MoveSpanUnder(ref, parentRef)
+}
+
+func MoveSpanToTrace(ref, traceName string) (*Trace, error) {
+	return defaultStore.MoveSpanToTrace(ref, traceName)
+}
+
+func CloneSpan(ref, prefix string, count int, parentRef string) ([]*Span, error) {
+	return defaultStore.CloneSpan(ref, prefix, count, parentRef)
+}
+
+func GetScenario() *Scenario {
+	return defaultStore.Scenario()
+}
+
+func LoadScenario(sc *Scenario) error {
+	return defaultStore.LoadScenario(sc)
+}
+
+func Track(label string, fn func()) bool {
+	return defaultStore.Track(label, fn)
+}
+
+func Atomically(fn func() error) error {
+	return defaultStore.Atomically(fn)
+}
+
+func Undo() (string, error) {
+	return defaultStore.Undo()
+}
+
+func Redo() (string, error) {
+	return defaultStore.Redo()
+}
+
+// GetOptions returns the settings of the default store
+func GetOptions() Options {
+	return defaultStore.Options()
+}
+
+func SetInheritResource(inherit bool) {
+	defaultStore.SetInheritResource(inherit)
+}
+
+func SetJitter(jitter float64) error {
+	return defaultStore.SetJitter(jitter)
+}
+
+var (
+	// lastSendResultMu guards lastSendResult
+	lastSendResultMu sync.RWMutex
+	// lastSendResult is the result of the last SendAllTraces
+	lastSendResult *SendResult
+)
+
+// SendAllTraces sends the traces in the current workspace of the default store with the
+// global tracer manager, then resets the workspace and the tracer manager
+func SendAllTraces(opts ...SendOption) {
+	if e := GetMemoryExporter(); e != nil {
+		e.Reset()
+	}
+	result, err := defaultStore.Send(context.Background(), GetTracerManager(), opts...)
+	if err != nil {
+		fmt.Printf("Error sending traces: %v\n", err)
+		return
+	}
+	lastSendResultMu.Lock()
+	lastSendResult = result
+	lastSendResultMu.Unlock()
+	for _, t := range result.Traces {
+		if t.Spans > 0 {
+			fmt.Printf("Trace '%s' sent with %d spans.\n", t.Name, t.Spans)
+		} else {
+			fmt.Printf("Trace '%s' has no spans.\n", t.Name)
+		}
+	}
+	if result.Errors > 0 {
+		fmt.Printf("Injected errors into %d spans.\n", result.Errors)
+	}
+	if result.Sampled {
+		fmt.Printf("Random values sampled with seed %d.\n", result.Seed)
+	}
+	for _, w := range result.Warnings {
+		fmt.Printf("Warning: %s\n", w)
+	}
+
+	InitStore()
+	ResetTracerManager()
+}
+
+// ResetTracerManager replaces the global tracer manager with a new one with the same exporter
+// and processor, so that the tracers for the resources are created again on the next send
+func ResetTracerManager() {
+	tracerManagerMu.Lock()
+	defer tracerManagerMu.Unlock()
+	initTracerManager(tracerManager.GetExporterFn(), tracerManager.GetSpanProcessorFn())
+}
+
+// LastSendResult returns the result of the last SendAllTraces, or nil if nothing has been sent
+func LastSendResult() *SendResult {
+	lastSendResultMu.RLock()
+	defer lastSendResultMu.RUnlock()
+	return lastSendResult
+}
+
+func DefineTemplate(t Template) error {
+	return defaultStore.DefineTemplate(t)
+}
+
+func GetTemplates() map[string]*Template {
+	return defaultStore.GetTemplates()
+}
+
+func SelectSpans(sel SpanSelector) ([]*Span, error) {
+	return defaultStore.SelectSpans(sel)
+}
+
+func Workspace() string {
+	return defaultStore.Workspace()
+}
+
+func Workspaces() []WorkspaceInfo {
+	return defaultStore.Workspaces()
+}
+
+func IsWorkspaceExists(name string) bool {
+	return defaultStore.IsWorkspaceExists(name)
+}
+
+func NewWorkspace(name string) error {
+	return defaultStore.NewWorkspace(name)
+}
+
+func SwitchWorkspace(name string) error {
+	return defaultStore.SwitchWorkspace(name)
+}
+
+func DeleteWorkspace(name string) error {
+	return defaultStore.DeleteWorkspace(name)
+}
+
+func CopyWorkspace(from, to string) error {
+	return defaultStore.CopyWorkspace(from, to)
+}
+
+func Lint() []LintFinding {
+	return defaultStore.Lint()
+}
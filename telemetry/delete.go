@@ -0,0 +1,129 @@
+package telemetry
+
+import (
+	"fmt"
+	"slices"
+)
+
+// DeleteResult describes what was removed from the store by a delete operation
+type DeleteResult struct {
+	// Spans are the spans removed from the store
+	Spans []*Span
+	// Links is the number of links removed
+	Links int
+	// Affected are the remaining spans whose resource or events were changed
+	Affected []*Span
+}
+
+func (s *Store) DeleteTrace(name string) (*DeleteResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	trace, ok := s.traces[name]
+	if !ok {
+		return nil, fmt.Errorf("trace %s not found", name)
+	}
+	s.changing()
+	result := &DeleteResult{}
+	if trace.RootSpan != nil {
+		s.removeSpanTree(trace.RootSpan, result)
+	}
+	delete(s.traces, name)
+	return result, nil
+}
+
+func (s *Store) DeleteSpan(ref string) (*DeleteResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	span, err := s.lookupSpan(ref)
+	if err != nil {
+		return nil, err
+	}
+	s.changing()
+	s.detachSpan(span)
+	result := &DeleteResult{}
+	s.removeSpanTree(span, result)
+	return result, nil
+}
+
+func (s *Store) DeleteResource(name string) (*DeleteResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resource, ok := s.resources[name]
+	if !ok {
+		return nil, fmt.Errorf("resource %s not found", name)
+	}
+	s.changing()
+	result := &DeleteResult{}
+	for _, span := range s.spans {
+		if span.Resource == resource {
+			span.Resource = nil
+			result.Affected = append(result.Affected, span)
+		}
+	}
+	delete(s.resources, name)
+	return result, nil
+}
+
+func (s *Store) DeleteEvent(name string) (*DeleteResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	event, ok := s.events[name]
+	if !ok {
+		return nil, fmt.Errorf("event %s not found", name)
+	}
+	s.changing()
+	result := &DeleteResult{}
+	for _, span := range s.spans {
+		if slices.Contains(span.Events, event) {
+			span.Events = slices.DeleteFunc(span.Events, func(e *Event) bool {
+				return e == event
+			})
+			result.Affected = append(result.Affected, span)
+		}
+	}
+	delete(s.events, name)
+	return result, nil
+}
+
+// removeSpanTree removes the span and its descendants from the store
+// together with the links of the remaining spans pointing at them
+func (s *Store) removeSpanTree(span *Span, result *DeleteResult) {
+	removed := make(map[*Span]bool)
+	walkSpan(span, func(sp *Span) {
+		removed[sp] = true
+		result.Spans = append(result.Spans, sp)
+		delete(s.spans, sp.Handle)
+	})
+
+	for _, sp := range s.spans {
+		before := len(sp.Links)
+		sp.Links = slices.DeleteFunc(sp.Links, func(l *Link) bool {
+			return removed[l.TargetSpan]
+		})
+		result.Links += before - len(sp.Links)
+	}
+}
+
+// parentOf returns the parent of the span, or nil if the span is a root span
+func (s *Store) parentOf(span *Span) *Span {
+	for _, sp := range s.spans {
+		if slices.Contains(sp.Children, span) {
+			return sp
+		}
+	}
+	return nil
+}
+
+// traceOf returns the trace which the span belongs to
+func (s *Store) traceOf(span *Span) *Trace {
+	root := span
+	for parent := s.parentOf(root); parent != nil; parent = s.parentOf(root) {
+		root = parent
+	}
+	for _, trace := range s.traces {
+		if trace.RootSpan == root {
+			return trace
+		}
+	}
+	return nil
+}
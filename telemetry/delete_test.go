@@ -0,0 +1,112 @@
+package telemetry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// setupDeleteStore creates the following store:
+//
+//	trace1: root -> child -> grandchild
+//	             -> sibling
+//	trace2: other (links to child and sibling, bound to resource and event)
+func setupDeleteStore() {
+	InitStore()
+	CreateTrace("trace1")
+	AddSpanToTrace("trace1", "root", map[string]string{})
+	AddSpanToSpan("root", "child", map[string]string{})
+	AddSpanToSpan("child", "grandchild", map[string]string{})
+	AddSpanToSpan("root", "sibling", map[string]string{})
+
+	CreateTrace("trace2")
+	AddSpanToTrace("trace2", "other", map[string]string{})
+	AddLinkToSpan("other", "child", map[string]string{})
+	AddLinkToSpan("other", "sibling", map[string]string{})
+
+	CreateResource("res", map[string]string{})
+	SetResourceToSpan("root", "res")
+	SetResourceToSpan("other", "res")
+
+	CreateEvent("evt", map[string]string{})
+	AddEventToSpan("root", "evt")
+	AddEventToSpan("other", "evt")
+}
+
+func TestDeleteTrace(t *testing.T) {
+	setupDeleteStore()
+
+	result, err := DeleteTrace("trace1")
+	assert.NoError(t, err)
+	assert.Len(t, result.Spans, 4)
+	assert.Equal(t, 2, result.Links)
+
+	assert.False(t, IsTraceExists("trace1"))
+	assert.Len(t, GetSpans(), 1)
+	assert.Empty(t, GetSpans()["trace2/other"].Links, "Links to the deleted spans should be removed")
+
+	_, err = DeleteTrace("trace1")
+	assert.Error(t, err)
+}
+
+func TestDeleteSpan(t *testing.T) {
+	t.Run("Subtree", func(t *testing.T) {
+		setupDeleteStore()
+
+		result, err := DeleteSpan("child")
+		assert.NoError(t, err)
+		assert.Len(t, result.Spans, 2)
+		assert.Equal(t, "child", result.Spans[0].Name)
+		assert.Equal(t, "grandchild", result.Spans[1].Name)
+		assert.Equal(t, 1, result.Links)
+
+		root := GetSpans()["trace1/root"]
+		assert.Len(t, root.Children, 1)
+		assert.Equal(t, "sibling", root.Children[0].Name)
+		assert.False(t, IsSpanExists("child"))
+		assert.False(t, IsSpanExists("grandchild"))
+
+		other := GetSpans()["trace2/other"]
+		assert.Len(t, other.Links, 1)
+		assert.Equal(t, "sibling", other.Links[0].TargetSpan.Name)
+	})
+
+	t.Run("RootSpan", func(t *testing.T) {
+		setupDeleteStore()
+
+		result, err := DeleteSpan("root")
+		assert.NoError(t, err)
+		assert.Len(t, result.Spans, 4)
+		assert.True(t, IsTraceExists("trace1"), "Trace should be kept")
+		assert.Nil(t, GetTraces()["trace1"].RootSpan)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		setupDeleteStore()
+
+		_, err := DeleteSpan("non_existent_span")
+		assert.Error(t, err)
+	})
+}
+
+func TestDeleteResource(t *testing.T) {
+	setupDeleteStore()
+
+	result, err := DeleteResource("res")
+	assert.NoError(t, err)
+	assert.Len(t, result.Affected, 2)
+	assert.False(t, IsResourceExists("res"))
+	assert.Nil(t, GetSpans()["trace1/root"].Resource)
+	assert.Nil(t, GetSpans()["trace2/other"].Resource)
+}
+
+func TestDeleteEvent(t *testing.T) {
+	setupDeleteStore()
+
+	result, err := DeleteEvent("evt")
+	assert.NoError(t, err)
+	assert.Len(t, result.Affected, 2)
+	assert.False(t, IsEventExists("evt"))
+	assert.Empty(t, GetSpans()["trace1/root"].Events)
+	assert.Empty(t, GetSpans()["trace2/other"].Events)
+}
@@ -0,0 +1,205 @@
+package telemetry
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Distribution is a distribution of durations written as
+//   - fixed(100ms) or just 100ms
+//   - uniform(10ms, 50ms) with the minimum and the maximum
+//   - normal(100ms, 20ms) with the mean and the standard deviation
+//   - lognormal(100ms, 50ms) with the mean and the standard deviation
+//   - percentiles(p50=20ms, p90=80ms, p99=300ms) with a percentile table
+type Distribution struct {
+	Kind   string
+	Params []time.Duration
+	// Percentiles are the percentiles of Params in ascending order, only for the percentiles kind
+	Percentiles []float64
+}
+
+var distributionPattern = regexp.MustCompile(`^([a-z]+)\((.*)\)$`)
+
+// distributionParams are the number of parameters of each kind
+var distributionParams = map[string]int{
+	"fixed":     1,
+	"uniform":   2,
+	"normal":    2,
+	"lognormal": 2,
+}
+
+// ParseDistribution parses the distribution, e.g. normal(100ms, 20ms)
+func ParseDistribution(s string) (Distribution, error) {
+	s = strings.TrimSpace(s)
+	m := distributionPattern.FindStringSubmatch(s)
+	if m == nil {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return Distribution{}, fmt.Errorf("invalid distribution %s", s)
+		}
+		return Distribution{Kind: "fixed", Params: []time.Duration{d}}, nil
+	}
+
+	kind := m[1]
+	args := strings.Split(m[2], ",")
+	if kind == "percentiles" {
+		return parsePercentiles(args)
+	}
+	count, ok := distributionParams[kind]
+	if !ok {
+		return Distribution{}, fmt.Errorf("unknown distribution %s (fixed, uniform, normal, lognormal or percentiles)", kind)
+	}
+	if len(args) != count {
+		return Distribution{}, fmt.Errorf("%s distribution takes %d durations, got %d", kind, count, len(args))
+	}
+	d := Distribution{Kind: kind}
+	for _, arg := range args {
+		param, err := time.ParseDuration(strings.TrimSpace(arg))
+		if err != nil {
+			return Distribution{}, fmt.Errorf("invalid duration %s in %s distribution", strings.TrimSpace(arg), kind)
+		}
+		if param < 0 {
+			return Distribution{}, fmt.Errorf("duration %s in %s distribution must not be negative", param, kind)
+		}
+		d.Params = append(d.Params, param)
+	}
+
+	switch kind {
+	case "uniform":
+		if d.Params[0] > d.Params[1] {
+			return Distribution{}, fmt.Errorf("minimum %s of uniform distribution is greater than maximum %s", d.Params[0], d.Params[1])
+		}
+	case "lognormal":
+		if d.Params[0] == 0 {
+			return Distribution{}, fmt.Errorf("mean of lognormal distribution must be greater than 0")
+		}
+	}
+	return d, nil
+}
+
+var percentilePattern = regexp.MustCompile(`^p(\d+(?:\.\d+)?)=(.+)$`)
+
+// parsePercentiles parses the arguments of a percentile table, e.g. p50=20ms and p99=300ms.
+// The durations must not decrease as the percentiles increase.
+func parsePercentiles(args []string) (Distribution, error) {
+	d := Distribution{Kind: "percentiles"}
+	for _, arg := range args {
+		arg = strings.TrimSpace(arg)
+		m := percentilePattern.FindStringSubmatch(arg)
+		if m == nil {
+			return Distribution{}, fmt.Errorf("invalid percentile %s (e.g. p99=300ms)", arg)
+		}
+		p, _ := strconv.ParseFloat(m[1], 64)
+		if p > 100 {
+			return Distribution{}, fmt.Errorf("percentile %s must not be greater than 100", m[1])
+		}
+		param, err := time.ParseDuration(m[2])
+		if err != nil {
+			return Distribution{}, fmt.Errorf("invalid duration %s in percentiles distribution", m[2])
+		}
+		if param < 0 {
+			return Distribution{}, fmt.Errorf("duration %s in percentiles distribution must not be negative", param)
+		}
+		d.Percentiles = append(d.Percentiles, p)
+		d.Params = append(d.Params, param)
+	}
+
+	if !sort.Float64sAreSorted(d.Percentiles) {
+		return Distribution{}, fmt.Errorf("percentiles must be in ascending order")
+	}
+	for i := 1; i < len(d.Params); i++ {
+		if d.Percentiles[i] == d.Percentiles[i-1] {
+			return Distribution{}, fmt.Errorf("percentile p%s is specified more than once", formatPercentile(d.Percentiles[i]))
+		}
+		if d.Params[i] < d.Params[i-1] {
+			return Distribution{}, fmt.Errorf("duration %s of p%s is less than %s of p%s", d.Params[i], formatPercentile(d.Percentiles[i]), d.Params[i-1], formatPercentile(d.Percentiles[i-1]))
+		}
+	}
+	return d, nil
+}
+
+func formatPercentile(p float64) string {
+	return strconv.FormatFloat(p, 'f', -1, 64)
+}
+
+func (d Distribution) String() string {
+	params := make([]string, 0, len(d.Params))
+	for i, p := range d.Params {
+		if d.Kind == "percentiles" {
+			params = append(params, fmt.Sprintf("p%s=%s", formatPercentile(d.Percentiles[i]), p))
+			continue
+		}
+		params = append(params, p.String())
+	}
+	return fmt.Sprintf("%s(%s)", d.Kind, strings.Join(params, ", "))
+}
+
+// Sample returns a duration drawn from the distribution, which is never negative
+func (d Distribution) Sample(r *rand.Rand) time.Duration {
+	var sample float64
+	switch d.Kind {
+	case "fixed":
+		return d.Params[0]
+	case "uniform":
+		min, max := d.Params[0], d.Params[1]
+		return min + time.Duration(r.Int64N(int64(max-min)+1))
+	case "normal":
+		sample = float64(d.Params[0]) + r.NormFloat64()*float64(d.Params[1])
+	case "lognormal":
+		// the parameters of the underlying normal distribution which give the mean and the standard deviation
+		mean, stddev := float64(d.Params[0]), float64(d.Params[1])
+		sigma2 := math.Log(1 + stddev*stddev/(mean*mean))
+		mu := math.Log(mean) - sigma2/2
+		sample = math.Exp(mu + math.Sqrt(sigma2)*r.NormFloat64())
+	case "percentiles":
+		sample = d.samplePercentiles(r.Float64() * 100)
+	}
+	return time.Duration(math.Max(sample, 0))
+}
+
+// samplePercentiles returns the duration at the percentile p by linear interpolation of the table.
+// The duration is 0 at p0 unless the table has p0, and the highest duration above the highest percentile.
+func (d Distribution) samplePercentiles(p float64) float64 {
+	lowP, low := 0.0, 0.0
+	for i, percentile := range d.Percentiles {
+		high := float64(d.Params[i])
+		if p <= percentile {
+			if percentile == lowP {
+				return high
+			}
+			return low + (high-low)*(p-lowP)/(percentile-lowP)
+		}
+		lowP, low = percentile, high
+	}
+	return low
+}
+
+func (d *Distribution) UnmarshalYAML(value *yaml.Node) error {
+	var s string
+	if err := value.Decode(&s); err != nil {
+		return err
+	}
+	parsed, err := ParseDistribution(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+func (d Distribution) MarshalYAML() (any, error) {
+	return d.String(), nil
+}
+
+// NewRand returns a random number generator with the seed, which gives the same numbers for the same seed
+func NewRand(seed int64) *rand.Rand {
+	return rand.New(rand.NewPCG(uint64(seed), uint64(seed)))
+}
@@ -0,0 +1,107 @@
+package telemetry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDistribution(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Distribution
+		wantErr string
+	}{
+		{input: "100ms", want: Distribution{Kind: "fixed", Params: []time.Duration{100 * time.Millisecond}}},
+		{input: "fixed(1s)", want: Distribution{Kind: "fixed", Params: []time.Duration{time.Second}}},
+		{input: "uniform(10ms, 50ms)", want: Distribution{Kind: "uniform", Params: []time.Duration{10 * time.Millisecond, 50 * time.Millisecond}}},
+		{input: "normal(100ms,20ms)", want: Distribution{Kind: "normal", Params: []time.Duration{100 * time.Millisecond, 20 * time.Millisecond}}},
+		{input: "lognormal(100ms, 50ms)", want: Distribution{Kind: "lognormal", Params: []time.Duration{100 * time.Millisecond, 50 * time.Millisecond}}},
+		{
+			input: "percentiles(p50=20ms, p99.9=300ms)",
+			want:  Distribution{Kind: "percentiles", Params: []time.Duration{20 * time.Millisecond, 300 * time.Millisecond}, Percentiles: []float64{50, 99.9}},
+		},
+		{input: "fast", wantErr: "invalid distribution fast"},
+		{input: "poisson(1s)", wantErr: "unknown distribution poisson (fixed, uniform, normal, lognormal or percentiles)"},
+		{input: "uniform(1s)", wantErr: "uniform distribution takes 2 durations, got 1"},
+		{input: "normal(1s, x)", wantErr: "invalid duration x in normal distribution"},
+		{input: "uniform(50ms, 10ms)", wantErr: "minimum 50ms of uniform distribution is greater than maximum 10ms"},
+		{input: "fixed(-1s)", wantErr: "duration -1s in fixed distribution must not be negative"},
+		{input: "lognormal(0s, 1s)", wantErr: "mean of lognormal distribution must be greater than 0"},
+		{input: "percentiles(50=20ms)", wantErr: "invalid percentile 50=20ms (e.g. p99=300ms)"},
+		{input: "percentiles(p101=1s)", wantErr: "percentile 101 must not be greater than 100"},
+		{input: "percentiles(p90=80ms, p50=20ms)", wantErr: "percentiles must be in ascending order"},
+		{input: "percentiles(p50=20ms, p50=30ms)", wantErr: "percentile p50 is specified more than once"},
+		{input: "percentiles(p50=80ms, p90=20ms)", wantErr: "duration 20ms of p90 is less than 80ms of p50"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseDistribution(tt.input)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDistributionSample(t *testing.T) {
+	tests := []struct {
+		input    string
+		min, max time.Duration
+		mean     time.Duration
+	}{
+		{input: "fixed(100ms)", min: 100 * time.Millisecond, max: 100 * time.Millisecond, mean: 100 * time.Millisecond},
+		{input: "uniform(10ms, 50ms)", min: 10 * time.Millisecond, max: 50 * time.Millisecond, mean: 30 * time.Millisecond},
+		{input: "normal(100ms, 10ms)", min: 0, max: time.Second, mean: 100 * time.Millisecond},
+		{input: "lognormal(100ms, 50ms)", min: 0, max: 10 * time.Second, mean: 100 * time.Millisecond},
+		{input: "percentiles(p0=10ms, p100=50ms)", min: 10 * time.Millisecond, max: 50 * time.Millisecond, mean: 30 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			d, err := ParseDistribution(tt.input)
+			assert.NoError(t, err)
+
+			r := NewRand(42)
+			var sum time.Duration
+			const n = 10000
+			for range n {
+				sample := d.Sample(r)
+				assert.GreaterOrEqual(t, sample, tt.min)
+				assert.LessOrEqual(t, sample, tt.max)
+				sum += sample
+			}
+			assert.InDelta(t, float64(tt.mean), float64(sum/n), float64(tt.mean)*0.05, "Mean of the samples should be close to the mean")
+			assert.Equal(t, d.Sample(NewRand(1)), d.Sample(NewRand(1)), "Same seed should give the same sample")
+		})
+	}
+}
+
+func TestDistributionSamplePercentiles(t *testing.T) {
+	d, err := ParseDistribution("percentiles(p50=20ms, p90=80ms, p99=300ms)")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{p: 0, want: 0},
+		{p: 25, want: 10 * time.Millisecond},
+		{p: 50, want: 20 * time.Millisecond},
+		{p: 70, want: 50 * time.Millisecond},
+		{p: 99, want: 300 * time.Millisecond},
+		{p: 99.5, want: 300 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(formatPercentile(tt.p), func(t *testing.T) {
+			assert.InDelta(t, float64(tt.want), d.samplePercentiles(tt.p), 1)
+		})
+	}
+	assert.Equal(t, "percentiles(p50=20ms, p90=80ms, p99=300ms)", d.String())
+}
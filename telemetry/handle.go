@@ -0,0 +1,112 @@
+package telemetry
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SpanNotFoundError is returned when no span matches the reference
+type SpanNotFoundError struct {
+	Ref string
+}
+
+func (e *SpanNotFoundError) Error() string {
+	return fmt.Sprintf("span %s not found", e.Ref)
+}
+
+var (
+	handleUnsafeChars = regexp.MustCompile(`[^a-zA-Z0-9_\.\-]`)
+	identPattern      = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_\.\-/]*$`)
+)
+
+// LookupSpan returns the span referenced by ref, which is either the unique handle
+// of the span (e.g. checkout/GET_users) or its display name if no other span has it.
+func (s *Store) LookupSpan(ref string) (*Span, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lookupSpan(ref)
+}
+
+func (s *Store) lookupSpan(ref string) (*Span, error) {
+	if span, ok := s.spans[ref]; ok {
+		return span, nil
+	}
+
+	var matches []*Span
+	for _, span := range s.spans {
+		if span.Name == ref {
+			matches = append(matches, span)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, &SpanNotFoundError{Ref: ref}
+	case 1:
+		return matches[0], nil
+	}
+
+	handles := make([]string, 0, len(matches))
+	for _, span := range matches {
+		handles = append(handles, span.Handle)
+	}
+	sort.Strings(handles)
+	return nil, fmt.Errorf("span name %s is ambiguous, use one of: %s", ref, strings.Join(handles, ", "))
+}
+
+// SpanRef returns the shortest reference to the span which can be typed in commands:
+// the display name if it is unique and doesn't need quoting, otherwise the handle.
+func (s *Store) SpanRef(span *Span) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.spanRef(span)
+}
+
+func (s *Store) spanRef(span *Span) string {
+	if !identPattern.MatchString(span.Name) {
+		return span.Handle
+	}
+	if found, err := s.lookupSpan(span.Name); err == nil && found == span {
+		return span.Name
+	}
+	return span.Handle
+}
+
+// newHandle returns a handle for a span with the display name in the trace
+// which is not used by any other span
+func (s *Store) newHandle(traceName, name string) string {
+	base := traceName + "/" + handleUnsafeChars.ReplaceAllString(name, "_")
+	handle := base
+	for i := 2; ; i++ {
+		if _, exists := s.spans[handle]; !exists {
+			return handle
+		}
+		handle = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// registerSpan assigns a new handle to the span and stores it
+func (s *Store) registerSpan(span *Span, traceName string) {
+	span.Handle = s.newHandle(traceName, span.Name)
+	s.spans[span.Handle] = span
+}
+
+// rehandleSpans reassigns the handles of the span and its descendants for the trace
+func (s *Store) rehandleSpans(span *Span, traceName string) {
+	walkSpan(span, func(sp *Span) {
+		delete(s.spans, sp.Handle)
+	})
+	walkSpan(span, func(sp *Span) {
+		s.registerSpan(sp, traceName)
+	})
+}
+
+// traceNameOf returns the name of the trace which the span belongs to
+func (s *Store) traceNameOf(span *Span) string {
+	if trace := s.traceOf(span); trace != nil {
+		return trace.Name
+	}
+	return ""
+}
@@ -0,0 +1,61 @@
+package telemetry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupHandleStore() {
+	InitStore()
+	CreateTrace("checkout")
+	AddSpanToTrace("checkout", "GET /users", map[string]string{})
+	AddSpanToSpan("checkout/GET__users", "db", map[string]string{})
+	AddSpanToSpan("checkout/GET__users", "db", map[string]string{})
+	CreateTrace("search")
+	AddSpanToTrace("search", "GET /users", map[string]string{})
+	AddSpanToSpan("search/GET__users", "cache", map[string]string{})
+}
+
+func TestLookupSpan(t *testing.T) {
+	setupHandleStore()
+
+	tests := []struct {
+		name     string
+		ref      string
+		wantName string
+		wantErr  string
+	}{
+		{"Handle", "search/GET__users", "GET /users", ""},
+		{"Unique name", "cache", "cache", ""},
+		{"Suffixed handle", "checkout/db-2", "db", ""},
+		{"Ambiguous name across traces", "GET /users", "", "span name GET /users is ambiguous, use one of: checkout/GET__users, search/GET__users"},
+		{"Ambiguous name within trace", "db", "", "span name db is ambiguous, use one of: checkout/db, checkout/db-2"},
+		{"Not found", "unknown", "", "span unknown not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			span, err := LookupSpan(tt.ref)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantName, span.Name)
+		})
+	}
+}
+
+func TestSpanRef(t *testing.T) {
+	setupHandleStore()
+	spans := GetSpans()
+
+	assert.Equal(t, "cache", SpanRef(spans["search/cache"]), "Unique name should be used")
+	assert.Equal(t, "checkout/db-2", SpanRef(spans["checkout/db-2"]), "Handle should be used for duplicate names")
+	assert.Equal(t, "search/GET__users", SpanRef(spans["search/GET__users"]))
+
+	UpdateSpan("checkout/db-2", "query", "", nil)
+	assert.Equal(t, "db", SpanRef(spans["checkout/db"]), "Name should become unique after the other span is renamed")
+	assert.Equal(t, "query", SpanRef(GetSpans()["checkout/query"]))
+}
@@ -0,0 +1,194 @@
+package telemetry
+
+import (
+	"errors"
+	"maps"
+)
+
+// maxHistory is the maximum number of changes which can be undone
+const maxHistory = 100
+
+// change is an entry of the undo/redo history. state is the snapshot of the store
+// taken before the change (undo stack) or before it was undone (redo stack).
+type change struct {
+	label string
+	state *state
+}
+
+type history struct {
+	undo []change
+	redo []change
+}
+
+// Track runs fn and records the change to the store in the undo history with the label
+// (usually the command which was executed). Nothing is recorded if the store is unchanged.
+// The state is copied only when fn makes its first change, so a command which fails or
+// changes nothing costs no copy. Each entry of the history holds a full copy of the state,
+// so the history of a workspace keeps at most maxHistory copies for undo plus the undone ones.
+// fn runs without holding the lock so that it can call the store methods, which means
+// changes made concurrently by other goroutines are recorded together with fn's.
+func (s *Store) Track(label string, fn func()) bool {
+	s.mu.Lock()
+	s.tracking, s.pending = true, nil
+	s.mu.Unlock()
+
+	fn()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	before := s.pending
+	s.tracking, s.pending = false, nil
+	if before == nil {
+		return false
+	}
+
+	s.history.undo = append(s.history.undo, change{label: label, state: before})
+	if len(s.history.undo) > maxHistory {
+		s.history.undo = s.history.undo[len(s.history.undo)-maxHistory:]
+	}
+	s.history.redo = nil
+	return true
+}
+
+// changing keeps the state before the first change made while Track runs fn.
+// The store methods call it with the lock held right before they change the state.
+func (s *Store) changing() {
+	if s.tracking && s.pending == nil {
+		s.pending = s.snapshot()
+	}
+}
+
+// Atomically runs fn and restores the store to the state before fn if it returns an error,
+// so that a change made of many store calls is applied either as a whole or not at all.
+// Like Track, fn runs without holding the lock.
+func (s *Store) Atomically(fn func() error) error {
+	s.mu.RLock()
+	before := s.snapshot()
+	pending := s.pending
+	s.mu.RUnlock()
+
+	if err := fn(); err != nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.state = before
+		// the changes of fn are rolled back, so Track should not record them either
+		s.pending = pending
+		return err
+	}
+	return nil
+}
+
+// Undo restores the store to the state before the last change and returns its label
+func (s *Store) Undo() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.history.undo) == 0 {
+		return "", errors.New("nothing to undo")
+	}
+	last := s.history.undo[len(s.history.undo)-1]
+	s.history.undo = s.history.undo[:len(s.history.undo)-1]
+	s.history.redo = append(s.history.redo, change{label: last.label, state: s.state})
+	s.state = last.state
+	return last.label, nil
+}
+
+// Redo reapplies the last undone change and returns its label
+func (s *Store) Redo() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.history.redo) == 0 {
+		return "", errors.New("nothing to redo")
+	}
+	last := s.history.redo[len(s.history.redo)-1]
+	s.history.redo = s.history.redo[:len(s.history.redo)-1]
+	s.history.undo = append(s.history.undo, change{label: last.label, state: s.state})
+	s.state = last.state
+	return last.label, nil
+}
+
+// snapshot returns a deep copy of the store state
+func (s *Store) snapshot() *state {
+	return s.state.clone()
+}
+
+// clone returns a deep copy of the state. Spans, resources and events shared
+// between entries in the state are also shared in the copy.
+func (st *state) clone() *state {
+	resources := make(map[*Resource]*Resource, len(st.resources))
+	events := make(map[*Event]*Event, len(st.events))
+	spans := make(map[*Span]*Span, len(st.spans))
+
+	snap := &state{
+		traces:    make(map[string]*Trace, len(st.traces)),
+		spans:     make(map[string]*Span, len(st.spans)),
+		resources: make(map[string]*Resource, len(st.resources)),
+		events:    make(map[string]*Event, len(st.events)),
+	}
+
+	for name, r := range st.resources {
+		c := *r
+		c.Attributes = maps.Clone(r.Attributes)
+		resources[r] = &c
+		snap.resources[name] = &c
+	}
+	for name, e := range st.events {
+		c := *e
+		c.Attributes = maps.Clone(e.Attributes)
+		events[e] = &c
+		snap.events[name] = &c
+	}
+	for handle, sp := range st.spans {
+		c := *sp
+		c.Attributes = maps.Clone(sp.Attributes)
+		spans[sp] = &c
+		snap.spans[handle] = &c
+	}
+
+	for _, c := range spans {
+		if c.Resource != nil {
+			c.Resource = resources[c.Resource]
+		}
+		c.Children = copySlice(c.Children, func(child *Span) *Span {
+			return spans[child]
+		})
+		c.Events = copySlice(c.Events, func(e *Event) *Event {
+			if copied, ok := events[e]; ok {
+				return copied
+			}
+			// the event is only added to spans (e.g. loaded from a scenario), not defined in the state
+			copied := *e
+			copied.Attributes = maps.Clone(e.Attributes)
+			events[e] = &copied
+			return &copied
+		})
+		c.Links = copySlice(c.Links, func(l *Link) *Link {
+			return &Link{TargetSpan: spans[l.TargetSpan], Attributes: maps.Clone(l.Attributes)}
+		})
+	}
+
+	for name, t := range st.traces {
+		c := *t
+		if t.RootSpan != nil {
+			c.RootSpan = spans[t.RootSpan]
+		}
+		if t.InheritResource != nil {
+			inherit := *t.InheritResource
+			c.InheritResource = &inherit
+		}
+		snap.traces[name] = &c
+	}
+
+	return snap
+}
+
+// copySlice maps the elements of the slice keeping a nil slice nil
+func copySlice[T any](s []T, fn func(T) T) []T {
+	if s == nil {
+		return nil
+	}
+	result := make([]T, len(s))
+	for i, v := range s {
+		result[i] = fn(v)
+	}
+	return result
+}
@@ -0,0 +1,226 @@
+package telemetry
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestHistory(t *testing.T) {
+	t.Run("Undo and redo", func(t *testing.T) {
+		InitStore()
+		CreateResource("db", map[string]string{"key": "value"})
+		CreateTrace("trace1")
+		AddSpanToTrace("trace1", "root", map[string]string{"a": "1", "b": "2"})
+		AddSpanToSpan("root", "child", map[string]string{})
+		SetResourceToSpan("child", "db")
+		AddLinkToSpan("child", "root", map[string]string{})
+
+		changed := Track("set span root attributes c=3", func() {
+			UpdateSpan("root", "", "", map[string]string{"c": "3"})
+		})
+		assert.True(t, changed)
+		assert.Equal(t, map[string]string{"c": "3"}, GetSpans()["trace1/root"].Attributes)
+
+		label, err := Undo()
+		assert.NoError(t, err)
+		assert.Equal(t, "set span root attributes c=3", label)
+		root := GetSpans()["trace1/root"]
+		child := GetSpans()["trace1/child"]
+		assert.Equal(t, map[string]string{"a": "1", "b": "2"}, root.Attributes)
+		assert.Equal(t, root, GetTraces()["trace1"].RootSpan, "Pointers should be consistent after undo")
+		assert.Equal(t, child, root.Children[0])
+		assert.Equal(t, root, child.Links[0].TargetSpan)
+		assert.Equal(t, GetResources()["db"], child.Resource)
+
+		label, err = Redo()
+		assert.NoError(t, err)
+		assert.Equal(t, "set span root attributes c=3", label)
+		assert.Equal(t, map[string]string{"c": "3"}, GetSpans()["trace1/root"].Attributes)
+
+		_, err = Redo()
+		assert.EqualError(t, err, "nothing to redo")
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		InitStore()
+		CreateTrace("trace1")
+		AddSpanToTrace("trace1", "root", map[string]string{})
+		AddSpanToSpan("root", "child", map[string]string{})
+
+		Track("delete span child", func() {
+			DeleteSpan("child")
+		})
+		assert.False(t, IsSpanExists("child"))
+
+		Undo()
+		assert.True(t, IsSpanExists("child"))
+		assert.Len(t, GetSpans()["trace1/root"].Children, 1)
+	})
+
+	t.Run("Unchanged", func(t *testing.T) {
+		InitStore()
+
+		changed := Track("delete span unknown", func() {
+			DeleteSpan("unknown")
+		})
+		assert.False(t, changed, "Failed commands should not be recorded")
+
+		_, err := Undo()
+		assert.EqualError(t, err, "nothing to undo")
+	})
+
+	t.Run("New change clears redo", func(t *testing.T) {
+		InitStore()
+		Track("create trace trace1", func() { CreateTrace("trace1") })
+		Undo()
+		Track("create trace trace2", func() { CreateTrace("trace2") })
+
+		_, err := Redo()
+		assert.EqualError(t, err, "nothing to redo")
+		assert.False(t, IsTraceExists("trace1"))
+		assert.True(t, IsTraceExists("trace2"))
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		InitStore()
+		for i := 0; i < maxHistory+10; i++ {
+			name := fmt.Sprintf("event%d", i)
+			Track("create event "+name, func() { CreateEvent(name, nil) })
+		}
+
+		count := 0
+		for {
+			if _, err := Undo(); err != nil {
+				break
+			}
+			count++
+		}
+		assert.Equal(t, maxHistory, count)
+		assert.Len(t, GetEvents(), 10)
+	})
+	t.Run("Atomically", func(t *testing.T) {
+		InitStore()
+		CreateTrace("trace1")
+
+		err := Atomically(func() error {
+			AddSpanToTrace("trace1", "root", map[string]string{})
+			return errors.New("failed")
+		})
+		assert.EqualError(t, err, "failed")
+		assert.False(t, IsSpanExists("root"), "Changes should be rolled back")
+
+		changed := Track("create span root", func() {
+			assert.NoError(t, Atomically(func() error {
+				_, err := AddSpanToTrace("trace1", "root", map[string]string{})
+				return err
+			}))
+		})
+		assert.True(t, changed)
+		assert.True(t, IsSpanExists("root"))
+
+		changed = Track("create span child with parent root", func() {
+			Atomically(func() error {
+				AddSpanToSpan("root", "child", map[string]string{})
+				return errors.New("failed")
+			})
+		})
+		assert.False(t, changed, "Rolled back changes should not be recorded")
+		label, err := Undo()
+		assert.NoError(t, err)
+		assert.Equal(t, "create span root", label)
+	})
+}
+
+// TestTrack_Mutators checks that every store method which changes the state is recorded by Track.
+// A new exported method must be added either to the mutators or to the methods which don't
+// change the state, so that a mutator which doesn't call changing is caught.
+func TestTrack_Mutators(t *testing.T) {
+	linkRef := LinkRef{From: "search/db", To: "checkout/db"}
+	inherit := false
+	merge := true
+	mutators := map[string]func(s *Store) error{
+		"AddEventToSpan":  func(s *Store) error { _, err := s.AddEventToSpan("search/db", "cache-miss"); return err },
+		"AddLinkToSpan":   func(s *Store) error { _, err := s.AddLinkToSpan("checkout/db", "search/db", nil); return err },
+		"AddSpanToSpan":   func(s *Store) error { _, err := s.AddSpanToSpan("search/db", "cache", nil); return err },
+		"AddSpanToTrace":  func(s *Store) error { _, err := s.AddSpanToTrace("empty", "root", nil); return err },
+		"AddTrace":        func(s *Store) error { _, err := s.AddTrace("new", &Span{Name: "root"}); return err },
+		"CloneSpan":       func(s *Store) error { _, err := s.CloneSpan("checkout/db", "db", 2, ""); return err },
+		"CreateEvent":     func(s *Store) error { s.CreateEvent("retry", nil); return nil },
+		"CreateResource":  func(s *Store) error { s.CreateResource("db", nil); return nil },
+		"CreateTrace":     func(s *Store) error { s.CreateTrace("new"); return nil },
+		"DeleteEvent":     func(s *Store) error { _, err := s.DeleteEvent("cache-miss"); return err },
+		"DeleteLinks":     func(s *Store) error { _, err := s.DeleteLinks(linkRef); return err },
+		"DeleteResource":  func(s *Store) error { _, err := s.DeleteResource("cart"); return err },
+		"DeleteSpan":      func(s *Store) error { _, err := s.DeleteSpan("checkout/db"); return err },
+		"DeleteTrace":     func(s *Store) error { _, err := s.DeleteTrace("search"); return err },
+		"LoadScenario":    func(s *Store) error { return s.LoadScenario(&Scenario{}) },
+		"MoveSpanToTrace": func(s *Store) error { _, err := s.MoveSpanToTrace("checkout/db", "empty"); return err },
+		"MoveSpanUnder":   func(s *Store) error { _, err := s.MoveSpanUnder("search/db", "checkout/db"); return err },
+		"PatchEventAttributes": func(s *Store) error {
+			_, err := s.PatchEventAttributes("cache-miss", map[string]string{"a": "1"}, nil)
+			return err
+		},
+		"PatchLinkAttributes": func(s *Store) error {
+			_, err := s.PatchLinkAttributes(linkRef, map[string]string{"a": "1"}, nil)
+			return err
+		},
+		"PatchResourceAttributes": func(s *Store) error {
+			_, err := s.PatchResourceAttributes("cart", map[string]string{"a": "1"}, nil)
+			return err
+		},
+		"PatchSpanAttributes": func(s *Store) error {
+			_, err := s.PatchSpanAttributes("checkout/db", map[string]string{"a": "1"}, nil)
+			return err
+		},
+		"SetLinkAttributes":    func(s *Store) error { _, err := s.SetLinkAttributes(linkRef, map[string]string{"a": "1"}); return err },
+		"SetResourceSemantics": func(s *Store) error { _, err := s.SetResourceSemantics("cart", "v1.4.0", &merge); return err },
+		"SetResourceToSpan":    func(s *Store) error { _, err := s.SetResourceToSpan("search/db", "cart"); return err },
+		"SetSpanDuration": func(s *Store) error {
+			_, err := s.SetSpanDuration("checkout/db", &Distribution{Kind: "fixed", Params: []time.Duration{time.Second}})
+			return err
+		},
+		"SetSpanKind":             func(s *Store) error { _, err := s.SetSpanKind("checkout/db", oteltrace.SpanKindServer); return err },
+		"SetTraceInheritResource": func(s *Store) error { _, err := s.SetTraceInheritResource("search", &inherit); return err },
+		"UpdateEvent":             func(s *Store) error { _, err := s.UpdateEvent("cache-miss", "miss", nil); return err },
+		"UpdateResource":          func(s *Store) error { _, err := s.UpdateResource("cart", "shop", nil); return err },
+		"UpdateSpan":              func(s *Store) error { _, err := s.UpdateSpan("checkout/db", "query", "", nil); return err },
+	}
+	// the methods which don't change the state, or manage the history and the workspaces themselves
+	others := []string{
+		"Atomically", "CopyWorkspace", "DefineTemplate", "DeleteWorkspace", "GetEvents", "GetResources",
+		"GetSpans", "GetTemplates", "GetTraces", "IsEventExists", "IsResourceExists", "IsSpanExists",
+		"IsTraceExists", "IsWorkspaceExists", "Lint", "LookupSpan", "NewWorkspace", "Options", "Redo",
+		"Reset", "Scenario", "SelectSpans", "Send", "SetInheritResource", "SetJitter", "SpanRef",
+		"SpansWithLinks", "SwitchWorkspace", "Track", "Undo", "Workspace", "Workspaces",
+	}
+
+	storeType := reflect.TypeOf(&Store{})
+	for i := range storeType.NumMethod() {
+		name := storeType.Method(i).Name
+		_, ok := mutators[name]
+		assert.True(t, ok || slices.Contains(others, name), "Store.%s should be classified as a mutator or not", name)
+	}
+
+	for name, fn := range mutators {
+		t.Run(name, func(t *testing.T) {
+			s := newScenarioStore(t)
+			s.CreateTrace("empty")
+			var err error
+			changed := s.Track(name, func() {
+				err = fn(s)
+			})
+			assert.NoError(t, err)
+			assert.True(t, changed, "Change should be recorded")
+			label, err := s.Undo()
+			assert.NoError(t, err)
+			assert.Equal(t, name, label)
+		})
+	}
+}
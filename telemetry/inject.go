@@ -0,0 +1,87 @@
+package telemetry
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultErrorAttribute is the attribute set to the spans failed by an error injection without an attribute
+var DefaultErrorAttribute = [2]string{"error.type", "injected"}
+
+// ErrorInjection fails a percentage of the sent spans. A failed span has the ERROR status,
+// an exception event and the error attribute.
+type ErrorInjection struct {
+	// Rate is the probability that a span fails, between 0 and 1
+	Rate float64
+	// Resource and SpanName limit the injection to the spans with the resource or the name
+	Resource string
+	SpanName string
+	// Attribute is the key and the value of the error attribute, DefaultErrorAttribute when empty
+	Attribute [2]string
+	// Propagate fails the ancestors of a failed span too, without the exception events
+	Propagate bool
+}
+
+func (inj *ErrorInjection) matches(s *Span, resource *Resource) bool {
+	if inj.SpanName != "" && s.Name != inj.SpanName {
+		return false
+	}
+	if inj.Resource != "" && (resource == nil || resource.Name != inj.Resource) {
+		return false
+	}
+	return true
+}
+
+// errorAttribute returns the error attribute of the injection, whose value can be a generator expression
+func (sd *sender) errorAttribute(inj *ErrorInjection) attribute.KeyValue {
+	key, value := DefaultErrorAttribute[0], DefaultErrorAttribute[1]
+	if inj.Attribute[0] != "" {
+		key, value = inj.Attribute[0], inj.Attribute[1]
+	}
+	return sd.attributes(map[string]string{key: value}, "the error injection")[0]
+}
+
+// WithErrors injects errors into the sent spans. The first injection matching a span decides
+// whether the span fails, so more specific injections should come first.
+func WithErrors(injections ...ErrorInjection) SendOption {
+	return func(sd *sender) {
+		sd.injections = append(sd.injections, injections...)
+	}
+}
+
+// injectError fails the span at the rate of the first matching injection
+func (sd *sender) injectError(s *Span, sp *spanToProcess, resource *Resource) {
+	for i := range sd.injections {
+		inj := &sd.injections[i]
+		if !inj.matches(s, resource) {
+			continue
+		}
+		sd.result.Sampled = true
+		if sd.rand.Float64() >= inj.Rate {
+			return
+		}
+
+		sd.result.Errors++
+		attr := sd.errorAttribute(inj)
+		sp.fail("injected error", attr)
+		sp.span.AddEvent("exception", trace.WithAttributes(
+			attribute.String("exception.type", "InjectedError"),
+			attribute.String("exception.message", "injected error"),
+		))
+		if inj.Propagate {
+			for parent := sp.parent; parent != nil; parent = parent.parent {
+				parent.fail("child span failed", attr)
+			}
+		}
+		return
+	}
+}
+
+// fail sets the ERROR status when the span ends instead of the status in the store
+func (s *spanToProcess) fail(description string, attr attribute.KeyValue) {
+	if s.status == nil || s.status.Code != codes.Error {
+		s.status = &Status{Code: codes.Error, Description: description}
+	}
+	s.span.SetAttributes(attr)
+}
@@ -0,0 +1,148 @@
+package telemetry
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// sendRecorded sends the store and returns the result and the ended spans keyed by name
+func sendRecorded(t *testing.T, s *Store, opts ...SendOption) (*SendResult, map[string]trace.ReadOnlySpan) {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	tm, err := NewTracerManager(func() (trace.SpanExporter, error) {
+		return tracetest.NewNoopExporter(), nil
+	}, func() (trace.SpanProcessor, error) {
+		return recorder, nil
+	})
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, tm.Shutdown(context.Background()))
+	})
+	result, err := s.Send(context.Background(), tm, opts...)
+	assert.NoError(t, err)
+	spans := make(map[string]trace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	return result, spans
+}
+
+func newInjectionStore(t *testing.T) *Store {
+	t.Helper()
+	s := NewStore()
+	s.CreateResource("cart", map[string]string{"service.name": "cart"})
+	s.CreateTrace("t")
+	_, err := s.AddSpanToTrace("t", "checkout", map[string]string{})
+	assert.NoError(t, err)
+	_, err = s.SetResourceToSpan("checkout", "cart")
+	assert.NoError(t, err)
+	_, err = s.AddSpanToSpan("checkout", "db", map[string]string{})
+	assert.NoError(t, err)
+	_, err = s.AddSpanToSpan("db", "query", map[string]string{})
+	assert.NoError(t, err)
+	s.spans["t/checkout"].Status = &Status{Code: codes.Ok}
+	return s
+}
+
+func TestStoreSend_ErrorInjection(t *testing.T) {
+	tests := []struct {
+		name       string
+		injections []ErrorInjection
+		// wantFailed are the spans with the exception event, and wantError the spans with the ERROR status
+		wantFailed []string
+		wantError  []string
+		wantAttr   [2]string
+	}{
+		{
+			name:       "All spans",
+			injections: []ErrorInjection{{Rate: 1}},
+			wantFailed: []string{"checkout", "db", "query"},
+			wantError:  []string{"checkout", "db", "query"},
+			wantAttr:   DefaultErrorAttribute,
+		},
+		{
+			name:       "Span name",
+			injections: []ErrorInjection{{Rate: 1, SpanName: "db", Attribute: [2]string{"error", "timeout"}}},
+			wantFailed: []string{"db"},
+			wantError:  []string{"db"},
+			wantAttr:   [2]string{"error", "timeout"},
+		},
+		{
+			name:       "Resource",
+			injections: []ErrorInjection{{Rate: 1, Resource: "cart"}},
+			wantFailed: []string{"checkout"},
+			wantError:  []string{"checkout"},
+			wantAttr:   DefaultErrorAttribute,
+		},
+		{
+			name:       "Propagate overrides the ok status of the ancestors",
+			injections: []ErrorInjection{{Rate: 1, SpanName: "query", Propagate: true}},
+			wantFailed: []string{"query"},
+			wantError:  []string{"checkout", "db", "query"},
+			wantAttr:   DefaultErrorAttribute,
+		},
+		{
+			name:       "First matching injection decides",
+			injections: []ErrorInjection{{Rate: 0, SpanName: "db"}, {Rate: 1}},
+			wantFailed: []string{"checkout", "query"},
+			wantError:  []string{"checkout", "query"},
+			wantAttr:   DefaultErrorAttribute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newInjectionStore(t)
+			result, spans := sendRecorded(t, s, WithErrors(tt.injections...))
+			assert.Equal(t, len(tt.wantFailed), result.Errors)
+
+			for _, name := range []string{"checkout", "db", "query"} {
+				span := spans[name]
+				hasException := false
+				for _, event := range span.Events() {
+					hasException = hasException || event.Name == "exception"
+				}
+				assert.Equal(t, slices.Contains(tt.wantFailed, name), hasException, "Exception event of %s", name)
+
+				if slices.Contains(tt.wantError, name) {
+					assert.Equal(t, codes.Error, span.Status().Code, "Status of %s", name)
+					assert.Contains(t, attributeMap(span), tt.wantAttr[0])
+					assert.Equal(t, tt.wantAttr[1], attributeMap(span)[tt.wantAttr[0]])
+				} else {
+					assert.NotEqual(t, codes.Error, span.Status().Code, "Status of %s", name)
+				}
+			}
+			assert.Equal(t, codes.Ok, s.spans["t/checkout"].Status.Code, "Store should be unchanged")
+		})
+	}
+}
+
+func TestStoreSend_ErrorInjection_Rate(t *testing.T) {
+	s := NewStore()
+	s.CreateTrace("t")
+	_, err := s.AddSpanToTrace("t", "root", map[string]string{})
+	assert.NoError(t, err)
+	for range 999 {
+		_, err := s.AddSpanToSpan("root", "child", map[string]string{})
+		assert.NoError(t, err)
+	}
+
+	result, _ := sendRecorded(t, s, WithSeed(7), WithErrors(ErrorInjection{Rate: 0.05}))
+	assert.InDelta(t, 50, result.Errors, 20)
+	again, _ := sendRecorded(t, s, WithSeed(7), WithErrors(ErrorInjection{Rate: 0.05}))
+	assert.Equal(t, result.Errors, again.Errors, "Same seed should fail the same spans")
+}
+
+func attributeMap(span trace.ReadOnlySpan) map[string]string {
+	attrs := make(map[string]string)
+	for _, kv := range span.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.AsString()
+	}
+	return attrs
+}
@@ -0,0 +1,104 @@
+package telemetry
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// LinkRef selects links of the span From: the link at Index in Span.Links when
+// Index is set, otherwise all links to the span To
+type LinkRef struct {
+	From  string
+	To    string
+	Index *int
+}
+
+// resolve returns the source span and the links selected by the reference
+func (r LinkRef) resolve(s *Store) (*Span, []*Link, error) {
+	fromSpan, err := s.lookupSpan(r.From)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if r.Index != nil {
+		if *r.Index < 0 || *r.Index >= len(fromSpan.Links) {
+			return nil, nil, fmt.Errorf("span %s has no link at index %d", r.From, *r.Index)
+		}
+		return fromSpan, []*Link{fromSpan.Links[*r.Index]}, nil
+	}
+
+	toSpan, err := s.lookupSpan(r.To)
+	if err != nil {
+		return nil, nil, err
+	}
+	var links []*Link
+	for _, link := range fromSpan.Links {
+		if link.TargetSpan == toSpan {
+			links = append(links, link)
+		}
+	}
+	if len(links) == 0 {
+		return nil, nil, fmt.Errorf("no link from span %s to span %s", r.From, r.To)
+	}
+	return fromSpan, links, nil
+}
+
+// SetLinkAttributes replaces the attributes of the selected links and returns the number of updated links
+func (s *Store) SetLinkAttributes(ref LinkRef, attributes map[string]string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, links, err := ref.resolve(s)
+	if err != nil {
+		return 0, err
+	}
+	s.changing()
+	for _, link := range links {
+		link.Attributes = maps.Clone(attributes)
+	}
+	return len(links), nil
+}
+
+// PatchLinkAttributes sets the attributes in add and removes the keys in remove for
+// the selected links, and returns the number of updated links
+func (s *Store) PatchLinkAttributes(ref LinkRef, add map[string]string, remove []string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, links, err := ref.resolve(s)
+	if err != nil {
+		return 0, err
+	}
+	s.changing()
+	for _, link := range links {
+		link.Attributes = patchAttributes(link.Attributes, add, remove)
+	}
+	return len(links), nil
+}
+
+// DeleteLinks removes the selected links from the span
+func (s *Store) DeleteLinks(ref LinkRef) (*DeleteResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fromSpan, links, err := ref.resolve(s)
+	if err != nil {
+		return nil, err
+	}
+	s.changing()
+	fromSpan.Links = slices.DeleteFunc(fromSpan.Links, func(l *Link) bool {
+		return slices.Contains(links, l)
+	})
+	return &DeleteResult{Links: len(links)}, nil
+}
+
+// SpansWithLinks returns the spans which have at least one link
+func (s *Store) SpansWithLinks() []*Span {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var spans []*Span
+	for _, span := range s.spans {
+		if len(span.Links) > 0 {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
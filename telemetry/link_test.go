@@ -0,0 +1,89 @@
+package telemetry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupLinkStore() {
+	InitStore()
+	CreateTrace("trace1")
+	AddSpanToTrace("trace1", "root", map[string]string{})
+	AddSpanToSpan("root", "child", map[string]string{})
+	AddSpanToSpan("root", "sibling", map[string]string{})
+	AddLinkToSpan("root", "child", map[string]string{"a": "1"})
+	AddLinkToSpan("root", "sibling", map[string]string{})
+	AddLinkToSpan("root", "child", map[string]string{"b": "2"})
+}
+
+func TestLinkAttributes(t *testing.T) {
+	t.Run("Patch by target", func(t *testing.T) {
+		setupLinkStore()
+
+		updated, err := PatchLinkAttributes(LinkRef{From: "root", To: "child"}, map[string]string{"c": "3"}, []string{"a"})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, updated)
+		links := GetSpans()["trace1/root"].Links
+		assert.Equal(t, map[string]string{"c": "3"}, links[0].Attributes)
+		assert.Empty(t, links[1].Attributes)
+		assert.Equal(t, map[string]string{"b": "2", "c": "3"}, links[2].Attributes)
+	})
+
+	t.Run("Set by index", func(t *testing.T) {
+		setupLinkStore()
+		index := 2
+
+		updated, err := SetLinkAttributes(LinkRef{From: "root", Index: &index}, map[string]string{"x": "1"})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, updated)
+		links := GetSpans()["trace1/root"].Links
+		assert.Equal(t, map[string]string{"a": "1"}, links[0].Attributes)
+		assert.Equal(t, map[string]string{"x": "1"}, links[2].Attributes)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		setupLinkStore()
+		index := 3
+
+		_, err := SetLinkAttributes(LinkRef{From: "root", Index: &index}, nil)
+		assert.EqualError(t, err, "span root has no link at index 3")
+
+		_, err = SetLinkAttributes(LinkRef{From: "child", To: "root"}, nil)
+		assert.EqualError(t, err, "no link from span child to span root")
+	})
+}
+
+func TestDeleteLinks(t *testing.T) {
+	t.Run("By target", func(t *testing.T) {
+		setupLinkStore()
+
+		result, err := DeleteLinks(LinkRef{From: "root", To: "child"})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Links)
+		links := GetSpans()["trace1/root"].Links
+		assert.Len(t, links, 1)
+		assert.Equal(t, "sibling", links[0].TargetSpan.Name)
+
+		_, err = DeleteLinks(LinkRef{From: "root", To: "child"})
+		assert.EqualError(t, err, "no link from span root to span child")
+	})
+
+	t.Run("By index", func(t *testing.T) {
+		setupLinkStore()
+		index := 0
+
+		result, err := DeleteLinks(LinkRef{From: "root", Index: &index})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Links)
+		links := GetSpans()["trace1/root"].Links
+		assert.Len(t, links, 2)
+		assert.Equal(t, map[string]string{"b": "2"}, links[1].Attributes, "Only the link at the index should be removed")
+	})
+}
+
+func TestSpansWithLinks(t *testing.T) {
+	setupLinkStore()
+
+	assert.Equal(t, []*Span{GetSpans()["trace1/root"]}, SpansWithLinks())
+}
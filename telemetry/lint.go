@@ -0,0 +1,194 @@
+package telemetry
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Severity is how serious a lint finding is
+type Severity int
+
+const (
+	// SeverityWarning is a finding which is sent as it is but is likely a mistake
+	SeverityWarning Severity = iota
+	// SeverityError is a finding which breaks the semantic conventions
+	SeverityError
+)
+
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+// LintFinding is a problem found by Lint
+type LintFinding struct {
+	Severity Severity
+	// Subject is the signal the finding is about, e.g. span 'checkout/GET__users'
+	Subject string
+	Message string
+}
+
+func (f LintFinding) String() string {
+	return fmt.Sprintf("%s: %s: %s", f.Severity, f.Subject, f.Message)
+}
+
+// lintRequirement is an attribute required by the spans with an attribute in the namespace.
+// Keys are the names of the attribute across the versions, the newest first.
+type lintRequirement struct {
+	namespace string
+	keys      []string
+}
+
+var lintRequirements = []lintRequirement{
+	{namespace: "http", keys: []string{"http.request.method", "http.method"}},
+	{namespace: "db", keys: []string{"db.system.name", "db.system"}},
+	{namespace: "rpc", keys: []string{"rpc.system"}},
+	{namespace: "messaging", keys: []string{"messaging.system"}},
+	{namespace: "messaging", keys: []string{"messaging.destination.name", "messaging.destination"}},
+}
+
+// keyIn returns the name of the required attribute in the semantic conventions version
+func (r lintRequirement) keyIn(version string) string {
+	for _, key := range r.keys {
+		if a := LookupRegistryAttribute(key); a != nil && a.isDefinedIn(version) && !a.isDeprecatedIn(version) {
+			return key
+		}
+	}
+	return r.keys[0]
+}
+
+type linter struct {
+	findings []LintFinding
+}
+
+func (l *linter) report(severity Severity, subject, format string, args ...any) {
+	l.findings = append(l.findings, LintFinding{Severity: severity, Subject: subject, Message: fmt.Sprintf(format, args...)})
+}
+
+// lintAttributes checks the keys, the types and the deprecation of the attributes
+// against the registry of the semantic conventions version
+func (l *linter) lintAttributes(subject string, attrs map[string]string, version string) {
+	for _, key := range sortedKeys(attrs) {
+		value := attrs[key]
+		a := LookupRegistryAttribute(key)
+		if a == nil {
+			if isRegistryNamespace(key) {
+				l.report(SeverityWarning, subject, "unknown attribute %s in semconv %s", key, version)
+			}
+			continue
+		}
+		switch {
+		case !a.isDefinedIn(version):
+			l.report(SeverityWarning, subject, "attribute %s is not defined in semconv %s (added in %s)", key, version, a.Since)
+		case a.isDeprecatedIn(version) && a.ReplacedBy != "":
+			l.report(SeverityWarning, subject, "attribute %s is deprecated in semconv %s, use %s", key, version, a.ReplacedBy)
+		case a.isDeprecatedIn(version):
+			l.report(SeverityWarning, subject, "attribute %s is deprecated in semconv %s", key, version)
+		}
+		// generated values are checked when they are generated on send
+		if !HasValueGenerator(value) && !a.Type.accepts(value) {
+			l.report(SeverityError, subject, "attribute %s must be %s but is '%s'", key, a.Type, value)
+		}
+	}
+}
+
+// lintRequirements checks that the attributes required by the namespaces in use are specified
+func (l *linter) lintRequirements(subject string, attrs map[string]string, version string) {
+	for _, r := range lintRequirements {
+		used := false
+		for key := range attrs {
+			if strings.HasPrefix(key, r.namespace+".") {
+				used = true
+				break
+			}
+		}
+		if !used || slices.ContainsFunc(r.keys, func(key string) bool { _, ok := attrs[key]; return ok }) {
+			continue
+		}
+		l.report(SeverityError, subject, "missing required attribute %s for the %s attributes", r.keyIn(version), r.namespace)
+	}
+}
+
+func (l *linter) lintResource(r *Resource) {
+	subject := fmt.Sprintf("resource '%s'", r.Name)
+	l.lintAttributes(subject, r.Attributes, r.EffectiveSemconvVersion())
+	if name, ok := r.Attributes["service.name"]; ok && name == "" {
+		l.report(SeverityError, subject, "service.name is empty")
+	}
+}
+
+// lintSpan checks the span with its links, and records the versions which the events
+// of the span are sent with
+func (l *linter) lintSpan(s *Span, resource *Resource, eventVersions map[*Event][]string) {
+	subject := fmt.Sprintf("span '%s'", s.Handle)
+	version := DefaultSemconvVersion
+	if resource != nil {
+		version = resource.EffectiveSemconvVersion()
+	} else {
+		l.report(SeverityWarning, subject, "no resource, the span is sent without service.name (unknown_service)")
+	}
+	l.lintAttributes(subject, s.Attributes, version)
+	l.lintRequirements(subject, s.Attributes, version)
+	for _, link := range s.Links {
+		l.lintAttributes(fmt.Sprintf("link from '%s' to '%s'", s.Handle, link.TargetSpan.Handle), link.Attributes, version)
+	}
+	for _, event := range s.Events {
+		if !slices.Contains(eventVersions[event], version) {
+			eventVersions[event] = append(eventVersions[event], version)
+		}
+	}
+}
+
+func (l *linter) lintEvent(e *Event, versions []string) {
+	subject := fmt.Sprintf("event '%s'", e.Name)
+	if len(versions) == 0 {
+		versions = []string{DefaultSemconvVersion}
+	}
+	slices.SortFunc(versions, func(a, b string) int { return semconvIndex(a) - semconvIndex(b) })
+	for _, version := range versions {
+		l.lintAttributes(subject, e.Attributes, version)
+	}
+	if e.Name == "exception" && e.Attributes["exception.type"] == "" && e.Attributes["exception.message"] == "" {
+		l.report(SeverityError, subject, "missing required attribute exception.type or exception.message for the exception event")
+	}
+}
+
+// Lint checks the resources, the spans with their links and the events against the bundled
+// semantic conventions registry, in the semconv version of the resource which they are sent with.
+// The findings are ordered by resource, by trace from the root span down, and by event.
+func (s *Store) Lint() []LintFinding {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	l := &linter{}
+	for _, name := range sortedKeys(s.resources) {
+		l.lintResource(s.resources[name])
+	}
+
+	eventVersions := make(map[*Event][]string)
+	for _, name := range sortedKeys(s.traces) {
+		t := s.traces[name]
+		if t.RootSpan == nil {
+			continue
+		}
+		inherit := t.ShouldInheritResource(s.options)
+		var walk func(span *Span, parentResource *Resource)
+		walk = func(span *Span, parentResource *Resource) {
+			resource, _ := ResolveResource(span, parentResource, inherit)
+			l.lintSpan(span, resource, eventVersions)
+			for _, child := range span.Children {
+				walk(child, resource)
+			}
+		}
+		walk(t.RootSpan, nil)
+	}
+
+	for _, name := range sortedKeys(s.events) {
+		e := s.events[name]
+		l.lintEvent(e, eventVersions[e])
+	}
+	return l.findings
+}
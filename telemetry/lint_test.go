@@ -0,0 +1,72 @@
+package telemetry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreLint(t *testing.T) {
+	s := NewStore()
+	s.CreateResource("api", map[string]string{"deployment.environment": "prod"})
+	s.CreateResource("legacy", map[string]string{"service.name": ""})
+	_, _ = s.SetResourceSemantics("api", "v1.30.0", nil)
+	_, _ = s.SetResourceSemantics("legacy", "v1.20.0", nil)
+	s.CreateEvent("exception", map[string]string{"exception.stacktrace": "..."})
+	s.CreateTrace("checkout")
+	_, _ = s.AddSpanToTrace("checkout", "GET /cart", map[string]string{
+		"http.method":               "GET",
+		"http.response.status_code": "OK",
+		"app.user.id":               "42",
+	})
+	_, _ = s.AddSpanToSpan("checkout/GET__cart", "query", map[string]string{
+		"db.statement":  "SELECT 1",
+		"db.pool.name":  "main",
+		"url.full":      "http://db",
+		"server.port":   "{{rand 1 9}}",
+		"rpc.system":    "grpc",
+		"rpc.grpc.code": "0",
+	})
+	_, _ = s.SetResourceToSpan("checkout/GET__cart", "api")
+	_, _ = s.SetResourceToSpan("checkout/query", "legacy")
+	_, _ = s.AddLinkToSpan("checkout/query", "checkout/GET__cart", map[string]string{"http.status_code": "200"})
+	_, _ = s.AddEventToSpan("checkout/query", "exception")
+	s.CreateTrace("orphan")
+	_, _ = s.AddSpanToTrace("orphan", "job", map[string]string{"messaging.system": "kafka"})
+
+	got := s.Lint()
+	want := []LintFinding{
+		{SeverityWarning, "resource 'api'", "attribute deployment.environment is deprecated in semconv v1.30.0, use deployment.environment.name"},
+		{SeverityError, "resource 'legacy'", "service.name is empty"},
+		{SeverityWarning, "span 'checkout/GET__cart'", "attribute http.method is deprecated in semconv v1.30.0, use http.request.method"},
+		{SeverityError, "span 'checkout/GET__cart'", "attribute http.response.status_code must be int but is 'OK'"},
+		{SeverityWarning, "span 'checkout/query'", "unknown attribute db.pool.name in semconv v1.20.0"},
+		{SeverityWarning, "span 'checkout/query'", "unknown attribute rpc.grpc.code in semconv v1.20.0"},
+		{SeverityWarning, "span 'checkout/query'", "attribute server.port is not defined in semconv v1.20.0 (added in v1.21.0)"},
+		{SeverityWarning, "span 'checkout/query'", "attribute url.full is not defined in semconv v1.20.0 (added in v1.21.0)"},
+		{SeverityError, "span 'checkout/query'", "missing required attribute db.system for the db attributes"},
+		{SeverityWarning, "span 'orphan/job'", "no resource, the span is sent without service.name (unknown_service)"},
+		{SeverityError, "span 'orphan/job'", "missing required attribute messaging.destination.name for the messaging attributes"},
+		{SeverityError, "event 'exception'", "missing required attribute exception.type or exception.message for the exception event"},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestStoreLint_Clean(t *testing.T) {
+	s := NewStore()
+	s.CreateResource("api", map[string]string{"service.version": "1.0.0"})
+	s.CreateTrace("checkout")
+	_, _ = s.AddSpanToTrace("checkout", "GET /cart", map[string]string{
+		"http.request.method":       "GET",
+		"http.response.status_code": "{{pick 200,500}}",
+		"http.request.header.x-id":  "abc",
+	})
+	_, _ = s.SetResourceToSpan("checkout/GET__cart", "api")
+
+	assert.Empty(t, s.Lint())
+}
+
+func TestLintFinding_String(t *testing.T) {
+	f := LintFinding{Severity: SeverityError, Subject: "span 'a'", Message: "service.name is empty"}
+	assert.Equal(t, "error: span 'a': service.name is empty", f.String())
+}
@@ -0,0 +1,75 @@
+package telemetry
+
+import (
+	"context"
+	"sync"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// MemoryExporter keeps the exported spans in memory so that they can be inspected.
+// Unlike tracetest.InMemoryExporter, the spans are kept when the exporter is shut down,
+// because SendAllTraces re-initializes the tracer manager after sending.
+type MemoryExporter struct {
+	mu    sync.Mutex
+	spans []sdktrace.ReadOnlySpan
+}
+
+// NewMemoryExporter returns an empty memory exporter
+func NewMemoryExporter() *MemoryExporter {
+	return &MemoryExporter{}
+}
+
+// ExportSpans stores the spans
+func (e *MemoryExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+// Shutdown does nothing and keeps the spans
+func (e *MemoryExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+// Spans returns a copy of the exported spans
+func (e *MemoryExporter) Spans() []sdktrace.ReadOnlySpan {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]sdktrace.ReadOnlySpan(nil), e.spans...)
+}
+
+// Reset removes the exported spans
+func (e *MemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = nil
+}
+
+var (
+	// memoryExporterMu guards memoryExporter
+	memoryExporterMu sync.RWMutex
+	// memoryExporter is the exporter used by the global tracer manager in memory mode
+	memoryExporter *MemoryExporter
+)
+
+// EnableMemoryExporter switches SendAllTraces to memory mode and returns the exporter function
+// to initialize the global tracer manager with. In memory mode, the exporter only keeps
+// the spans of the last SendAllTraces.
+func EnableMemoryExporter() func() (sdktrace.SpanExporter, error) {
+	e := NewMemoryExporter()
+	memoryExporterMu.Lock()
+	memoryExporter = e
+	memoryExporterMu.Unlock()
+	return func() (sdktrace.SpanExporter, error) {
+		return e, nil
+	}
+}
+
+// GetMemoryExporter returns the exporter of the memory mode, or nil if it is not enabled
+func GetMemoryExporter() *MemoryExporter {
+	memoryExporterMu.RLock()
+	defer memoryExporterMu.RUnlock()
+	return memoryExporter
+}
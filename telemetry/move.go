@@ -0,0 +1,84 @@
+package telemetry
+
+import (
+	"fmt"
+	"slices"
+)
+
+// MoveSpanUnder moves the span with its descendants under the new parent span
+func (s *Store) MoveSpanUnder(ref, parentRef string) (*Span, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	span, err := s.lookupSpan(ref)
+	if err != nil {
+		return nil, err
+	}
+	parent, err := s.lookupSpan(parentRef)
+	if err != nil {
+		return nil, err
+	}
+	if isDescendantOrSelf(parent, span) {
+		return nil, fmt.Errorf("cannot move span %s under itself or its descendant %s", ref, parentRef)
+	}
+	s.changing()
+	oldTraceName := s.traceNameOf(span)
+	s.detachSpan(span)
+	parent.AddChild(span)
+	if traceName := s.traceNameOf(parent); traceName != oldTraceName {
+		s.rehandleSpans(span, traceName)
+	}
+	return parent, nil
+}
+
+// MoveSpanToTrace moves the span with its descendants to the trace as its root span
+func (s *Store) MoveSpanToTrace(ref, traceName string) (*Trace, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	span, err := s.lookupSpan(ref)
+	if err != nil {
+		return nil, err
+	}
+	trace, ok := s.traces[traceName]
+	if !ok {
+		return nil, fmt.Errorf("trace %s not found", traceName)
+	}
+	if trace.RootSpan == span {
+		return trace, nil
+	}
+	if trace.RootSpan != nil {
+		return nil, fmt.Errorf("trace %s already has a root span", traceName)
+	}
+	s.changing()
+	oldTraceName := s.traceNameOf(span)
+	s.detachSpan(span)
+	trace.RootSpan = span
+	if traceName != oldTraceName {
+		s.rehandleSpans(span, traceName)
+	}
+	return trace, nil
+}
+
+// detachSpan removes the span from the children of its parent,
+// or from its trace if the span is a root span
+func (s *Store) detachSpan(span *Span) {
+	if parent := s.parentOf(span); parent != nil {
+		parent.Children = slices.DeleteFunc(parent.Children, func(c *Span) bool {
+			return c == span
+		})
+	} else if trace := s.traceOf(span); trace != nil {
+		trace.RootSpan = nil
+	}
+}
+
+// isDescendantOrSelf reports whether the span is the ancestor itself or one of its descendants
+func isDescendantOrSelf(span, ancestor *Span) bool {
+	if span == ancestor {
+		return true
+	}
+	for _, child := range ancestor.Children {
+		if isDescendantOrSelf(span, child) {
+			return true
+		}
+	}
+	return false
+}
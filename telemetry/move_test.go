@@ -0,0 +1,87 @@
+package telemetry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupMoveStore() {
+	InitStore()
+	CreateTrace("trace1")
+	AddSpanToTrace("trace1", "root", map[string]string{})
+	AddSpanToSpan("root", "child", map[string]string{})
+	AddSpanToSpan("child", "grandchild", map[string]string{})
+	AddSpanToSpan("root", "sibling", map[string]string{})
+	CreateTrace("trace2")
+}
+
+func TestMoveSpanUnder(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		setupMoveStore()
+
+		_, err := MoveSpanUnder("child", "sibling")
+		assert.NoError(t, err)
+
+		spans := GetSpans()
+		assert.Equal(t, []*Span{spans["trace1/sibling"]}, spans["trace1/root"].Children)
+		assert.Equal(t, []*Span{spans["trace1/child"]}, spans["trace1/sibling"].Children)
+		assert.Equal(t, []*Span{spans["trace1/grandchild"]}, spans["trace1/child"].Children, "Descendants should be moved together")
+	})
+
+	t.Run("RootSpan", func(t *testing.T) {
+		setupMoveStore()
+		AddSpanToTrace("trace2", "other", map[string]string{})
+
+		_, err := MoveSpanUnder("other", "grandchild")
+		assert.NoError(t, err)
+		assert.Nil(t, GetTraces()["trace2"].RootSpan, "Trace should lose its root span")
+		assert.Equal(t, "other", GetSpans()["trace1/grandchild"].Children[0].Name)
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		setupMoveStore()
+
+		_, err := MoveSpanUnder("child", "grandchild")
+		assert.EqualError(t, err, "cannot move span child under itself or its descendant grandchild")
+
+		_, err = MoveSpanUnder("child", "child")
+		assert.Error(t, err)
+		assert.Len(t, GetSpans()["trace1/root"].Children, 2, "Tree should be unchanged")
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		setupMoveStore()
+
+		_, err := MoveSpanUnder("non_existent_span", "root")
+		assert.Error(t, err)
+		_, err = MoveSpanUnder("child", "non_existent_span")
+		assert.Error(t, err)
+	})
+}
+
+func TestMoveSpanToTrace(t *testing.T) {
+	t.Run("OK", func(t *testing.T) {
+		setupMoveStore()
+
+		trace, err := MoveSpanToTrace("child", "trace2")
+		assert.NoError(t, err)
+		assert.Equal(t, GetSpans()["trace2/child"], trace.RootSpan, "Handle should be reassigned for the new trace")
+		assert.Equal(t, GetSpans()["trace2/grandchild"], trace.RootSpan.Children[0])
+		assert.Equal(t, []*Span{GetSpans()["trace1/sibling"]}, GetSpans()["trace1/root"].Children)
+	})
+
+	t.Run("RootSpanAlreadyExists", func(t *testing.T) {
+		setupMoveStore()
+
+		_, err := MoveSpanToTrace("child", "trace1")
+		assert.EqualError(t, err, "trace trace1 already has a root span")
+	})
+
+	t.Run("TraceNotFound", func(t *testing.T) {
+		setupMoveStore()
+
+		_, err := MoveSpanToTrace("child", "non_existent_trace")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,38 @@
+package telemetry
+
+import "fmt"
+
+// Options holds the settings of a store. Unlike the signals, they are kept when the store is reset.
+type Options struct {
+	// InheritResource makes spans without a resource use the resource of their parent span
+	InheritResource bool
+	// Jitter scales the duration of each span by a factor sampled between 1-Jitter and 1+Jitter
+	// on each send. It is between 0 and 1, and 0 disables the jitter.
+	Jitter float64
+}
+
+// Options returns the current settings of the store
+func (s *Store) Options() Options {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.options
+}
+
+// SetInheritResource enables or disables the resource inheritance for all traces
+// which don't override it
+func (s *Store) SetInheritResource(inherit bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.options.InheritResource = inherit
+}
+
+// SetJitter sets the jitter factor applied to the span durations on send
+func (s *Store) SetJitter(jitter float64) error {
+	if jitter < 0 || jitter > 1 {
+		return fmt.Errorf("jitter must be between 0 and 1")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.options.Jitter = jitter
+	return nil
+}
@@ -0,0 +1,224 @@
+package telemetry
+
+import (
+	"strconv"
+	"strings"
+)
+
+// AttributeType is the type of the value of an attribute in the semantic conventions
+type AttributeType string
+
+const (
+	AttributeTypeString      AttributeType = "string"
+	AttributeTypeInt         AttributeType = "int"
+	AttributeTypeDouble      AttributeType = "double"
+	AttributeTypeBoolean     AttributeType = "boolean"
+	AttributeTypeStringArray AttributeType = "string[]"
+)
+
+// accepts reports whether the value written in a command is of the type
+func (t AttributeType) accepts(value string) bool {
+	var err error
+	switch t {
+	case AttributeTypeInt:
+		_, err = strconv.ParseInt(value, 10, 64)
+	case AttributeTypeDouble:
+		_, err = strconv.ParseFloat(value, 64)
+	case AttributeTypeBoolean:
+		_, err = strconv.ParseBool(value)
+	}
+	return err == nil
+}
+
+// RegistryAttribute is an attribute of the bundled semantic conventions registry
+type RegistryAttribute struct {
+	Key  string
+	Type AttributeType
+	// Since is the first supported version defining the attribute, or empty for all of them
+	Since string
+	// Deprecated is the version from which the attribute is deprecated, or empty
+	Deprecated string
+	// ReplacedBy is the attribute to use instead of the deprecated one, or empty if there is none
+	ReplacedBy string
+	// Template is true when the key is a prefix followed by a name, e.g. http.request.header.<name>
+	Template bool
+}
+
+// isDefinedIn reports whether the attribute is defined in the semantic conventions version
+func (a *RegistryAttribute) isDefinedIn(version string) bool {
+	return a.Since == "" || semconvIndex(version) >= semconvIndex(a.Since)
+}
+
+// isDeprecatedIn reports whether the attribute is deprecated in the semantic conventions version
+func (a *RegistryAttribute) isDeprecatedIn(version string) bool {
+	return a.Deprecated != "" && semconvIndex(version) >= semconvIndex(a.Deprecated)
+}
+
+// registryNamespaces are the namespaces covered by the registry, in which unknown keys are reported.
+// Keys in other namespaces, e.g. app.user.id, are left to the user.
+var registryNamespaces = []string{
+	"client", "db", "deployment", "error", "exception", "http", "messaging",
+	"net", "network", "rpc", "server", "service", "telemetry", "url", "user_agent",
+}
+
+// semconvRegistry is the bundled registry of the attributes in the namespaces above,
+// covering the semantic conventions versions which resources can be set to
+var semconvRegistry = []RegistryAttribute{
+	// client and server
+	{Key: "client.address", Type: AttributeTypeString, Since: "v1.21.0"},
+	{Key: "client.port", Type: AttributeTypeInt, Since: "v1.21.0"},
+	{Key: "server.address", Type: AttributeTypeString, Since: "v1.21.0"},
+	{Key: "server.port", Type: AttributeTypeInt, Since: "v1.21.0"},
+
+	// database
+	{Key: "db.system", Type: AttributeTypeString, Deprecated: "v1.30.0", ReplacedBy: "db.system.name"},
+	{Key: "db.system.name", Type: AttributeTypeString, Since: "v1.30.0"},
+	{Key: "db.name", Type: AttributeTypeString, Deprecated: "v1.26.0", ReplacedBy: "db.namespace"},
+	{Key: "db.namespace", Type: AttributeTypeString, Since: "v1.26.0"},
+	{Key: "db.operation", Type: AttributeTypeString, Deprecated: "v1.26.0", ReplacedBy: "db.operation.name"},
+	{Key: "db.operation.name", Type: AttributeTypeString, Since: "v1.26.0"},
+	{Key: "db.operation.batch.size", Type: AttributeTypeInt, Since: "v1.30.0"},
+	{Key: "db.statement", Type: AttributeTypeString, Deprecated: "v1.26.0", ReplacedBy: "db.query.text"},
+	{Key: "db.query.text", Type: AttributeTypeString, Since: "v1.26.0"},
+	{Key: "db.query.summary", Type: AttributeTypeString, Since: "v1.30.0"},
+	{Key: "db.query.parameter.", Type: AttributeTypeString, Since: "v1.26.0", Template: true},
+	{Key: "db.sql.table", Type: AttributeTypeString, Deprecated: "v1.26.0", ReplacedBy: "db.collection.name"},
+	{Key: "db.collection.name", Type: AttributeTypeString, Since: "v1.26.0"},
+	{Key: "db.response.status_code", Type: AttributeTypeString, Since: "v1.30.0"},
+	{Key: "db.user", Type: AttributeTypeString, Deprecated: "v1.26.0"},
+	{Key: "db.connection_string", Type: AttributeTypeString, Deprecated: "v1.26.0"},
+	{Key: "db.redis.database_index", Type: AttributeTypeInt, Deprecated: "v1.26.0", ReplacedBy: "db.namespace"},
+	{Key: "db.mongodb.collection", Type: AttributeTypeString, Deprecated: "v1.26.0", ReplacedBy: "db.collection.name"},
+
+	// deployment
+	{Key: "deployment.environment", Type: AttributeTypeString, Deprecated: "v1.30.0", ReplacedBy: "deployment.environment.name"},
+	{Key: "deployment.environment.name", Type: AttributeTypeString, Since: "v1.30.0"},
+
+	// error and exception
+	{Key: "error.type", Type: AttributeTypeString, Since: "v1.24.0"},
+	{Key: "exception.type", Type: AttributeTypeString},
+	{Key: "exception.message", Type: AttributeTypeString},
+	{Key: "exception.stacktrace", Type: AttributeTypeString},
+	{Key: "exception.escaped", Type: AttributeTypeBoolean, Deprecated: "v1.30.0"},
+
+	// HTTP
+	{Key: "http.method", Type: AttributeTypeString, Deprecated: "v1.21.0", ReplacedBy: "http.request.method"},
+	{Key: "http.request.method", Type: AttributeTypeString, Since: "v1.21.0"},
+	{Key: "http.request.method_original", Type: AttributeTypeString, Since: "v1.21.0"},
+	{Key: "http.status_code", Type: AttributeTypeInt, Deprecated: "v1.21.0", ReplacedBy: "http.response.status_code"},
+	{Key: "http.response.status_code", Type: AttributeTypeInt, Since: "v1.21.0"},
+	{Key: "http.route", Type: AttributeTypeString},
+	{Key: "http.target", Type: AttributeTypeString, Deprecated: "v1.21.0", ReplacedBy: "url.path"},
+	{Key: "http.url", Type: AttributeTypeString, Deprecated: "v1.21.0", ReplacedBy: "url.full"},
+	{Key: "http.scheme", Type: AttributeTypeString, Deprecated: "v1.21.0", ReplacedBy: "url.scheme"},
+	{Key: "http.host", Type: AttributeTypeString, Deprecated: "v1.17.0", ReplacedBy: "server.address"},
+	{Key: "http.flavor", Type: AttributeTypeString, Deprecated: "v1.21.0", ReplacedBy: "network.protocol.version"},
+	{Key: "http.user_agent", Type: AttributeTypeString, Deprecated: "v1.21.0", ReplacedBy: "user_agent.original"},
+	{Key: "http.client_ip", Type: AttributeTypeString, Deprecated: "v1.21.0", ReplacedBy: "client.address"},
+	{Key: "http.request_content_length", Type: AttributeTypeInt, Deprecated: "v1.21.0", ReplacedBy: "http.request.body.size"},
+	{Key: "http.response_content_length", Type: AttributeTypeInt, Deprecated: "v1.21.0", ReplacedBy: "http.response.body.size"},
+	{Key: "http.request.body.size", Type: AttributeTypeInt, Since: "v1.21.0"},
+	{Key: "http.response.body.size", Type: AttributeTypeInt, Since: "v1.21.0"},
+	{Key: "http.resend_count", Type: AttributeTypeInt, Since: "v1.17.0", Deprecated: "v1.24.0", ReplacedBy: "http.request.resend_count"},
+	{Key: "http.request.resend_count", Type: AttributeTypeInt, Since: "v1.24.0"},
+	{Key: "http.request.header.", Type: AttributeTypeStringArray, Template: true},
+	{Key: "http.response.header.", Type: AttributeTypeStringArray, Template: true},
+
+	// messaging
+	{Key: "messaging.system", Type: AttributeTypeString},
+	{Key: "messaging.destination", Type: AttributeTypeString, Deprecated: "v1.17.0", ReplacedBy: "messaging.destination.name"},
+	{Key: "messaging.destination.name", Type: AttributeTypeString, Since: "v1.17.0"},
+	{Key: "messaging.destination.partition.id", Type: AttributeTypeString, Since: "v1.26.0"},
+	{Key: "messaging.operation", Type: AttributeTypeString, Deprecated: "v1.26.0", ReplacedBy: "messaging.operation.type"},
+	{Key: "messaging.operation.type", Type: AttributeTypeString, Since: "v1.26.0"},
+	{Key: "messaging.operation.name", Type: AttributeTypeString, Since: "v1.26.0"},
+	{Key: "messaging.message_id", Type: AttributeTypeString, Deprecated: "v1.17.0", ReplacedBy: "messaging.message.id"},
+	{Key: "messaging.message.id", Type: AttributeTypeString, Since: "v1.17.0"},
+	{Key: "messaging.conversation_id", Type: AttributeTypeString, Deprecated: "v1.17.0", ReplacedBy: "messaging.message.conversation_id"},
+	{Key: "messaging.message.conversation_id", Type: AttributeTypeString, Since: "v1.17.0"},
+	{Key: "messaging.message.body.size", Type: AttributeTypeInt, Since: "v1.24.0"},
+	{Key: "messaging.batch.message_count", Type: AttributeTypeInt, Since: "v1.17.0"},
+	{Key: "messaging.kafka.message_key", Type: AttributeTypeString, Deprecated: "v1.17.0", ReplacedBy: "messaging.kafka.message.key"},
+	{Key: "messaging.kafka.message.key", Type: AttributeTypeString, Since: "v1.17.0"},
+	{Key: "messaging.kafka.partition", Type: AttributeTypeInt, Deprecated: "v1.17.0", ReplacedBy: "messaging.kafka.destination.partition"},
+	{Key: "messaging.kafka.destination.partition", Type: AttributeTypeInt, Since: "v1.17.0", Deprecated: "v1.26.0", ReplacedBy: "messaging.destination.partition.id"},
+	{Key: "messaging.kafka.consumer_group", Type: AttributeTypeString, Deprecated: "v1.17.0", ReplacedBy: "messaging.kafka.consumer.group"},
+	{Key: "messaging.kafka.consumer.group", Type: AttributeTypeString, Since: "v1.17.0", Deprecated: "v1.30.0", ReplacedBy: "messaging.consumer.group.name"},
+	{Key: "messaging.consumer.group.name", Type: AttributeTypeString, Since: "v1.30.0"},
+	{Key: "messaging.kafka.message.offset", Type: AttributeTypeInt, Since: "v1.17.0", Deprecated: "v1.30.0", ReplacedBy: "messaging.kafka.offset"},
+	{Key: "messaging.kafka.offset", Type: AttributeTypeInt, Since: "v1.30.0"},
+	{Key: "messaging.kafka.tombstone", Type: AttributeTypeBoolean, Deprecated: "v1.17.0", ReplacedBy: "messaging.kafka.message.tombstone"},
+	{Key: "messaging.kafka.message.tombstone", Type: AttributeTypeBoolean, Since: "v1.17.0"},
+
+	// network
+	{Key: "net.peer.name", Type: AttributeTypeString, Deprecated: "v1.21.0", ReplacedBy: "server.address"},
+	{Key: "net.peer.port", Type: AttributeTypeInt, Deprecated: "v1.21.0", ReplacedBy: "server.port"},
+	{Key: "net.peer.ip", Type: AttributeTypeString, Deprecated: "v1.17.0", ReplacedBy: "network.peer.address"},
+	{Key: "net.host.name", Type: AttributeTypeString, Deprecated: "v1.21.0", ReplacedBy: "server.address"},
+	{Key: "net.host.port", Type: AttributeTypeInt, Deprecated: "v1.21.0", ReplacedBy: "server.port"},
+	{Key: "net.transport", Type: AttributeTypeString, Deprecated: "v1.21.0", ReplacedBy: "network.transport"},
+	{Key: "network.transport", Type: AttributeTypeString, Since: "v1.21.0"},
+	{Key: "network.type", Type: AttributeTypeString, Since: "v1.21.0"},
+	{Key: "network.protocol.name", Type: AttributeTypeString, Since: "v1.21.0"},
+	{Key: "network.protocol.version", Type: AttributeTypeString, Since: "v1.21.0"},
+	{Key: "network.peer.address", Type: AttributeTypeString, Since: "v1.24.0"},
+	{Key: "network.peer.port", Type: AttributeTypeInt, Since: "v1.24.0"},
+
+	// RPC
+	{Key: "rpc.system", Type: AttributeTypeString},
+	{Key: "rpc.service", Type: AttributeTypeString},
+	{Key: "rpc.method", Type: AttributeTypeString},
+	{Key: "rpc.grpc.status_code", Type: AttributeTypeInt},
+	{Key: "rpc.grpc.request.metadata.", Type: AttributeTypeStringArray, Template: true},
+	{Key: "rpc.grpc.response.metadata.", Type: AttributeTypeStringArray, Template: true},
+	{Key: "rpc.jsonrpc.version", Type: AttributeTypeString},
+	{Key: "rpc.jsonrpc.request_id", Type: AttributeTypeString},
+	{Key: "rpc.jsonrpc.error_code", Type: AttributeTypeInt},
+	{Key: "rpc.jsonrpc.error_message", Type: AttributeTypeString},
+
+	// service
+	{Key: "service.name", Type: AttributeTypeString},
+	{Key: "service.namespace", Type: AttributeTypeString},
+	{Key: "service.instance.id", Type: AttributeTypeString},
+	{Key: "service.version", Type: AttributeTypeString},
+
+	// telemetry SDK
+	{Key: "telemetry.sdk.name", Type: AttributeTypeString},
+	{Key: "telemetry.sdk.language", Type: AttributeTypeString},
+	{Key: "telemetry.sdk.version", Type: AttributeTypeString},
+	{Key: "telemetry.auto.version", Type: AttributeTypeString, Deprecated: "v1.24.0", ReplacedBy: "telemetry.distro.version"},
+	{Key: "telemetry.distro.name", Type: AttributeTypeString, Since: "v1.24.0"},
+	{Key: "telemetry.distro.version", Type: AttributeTypeString, Since: "v1.24.0"},
+
+	// URL and user agent
+	{Key: "url.full", Type: AttributeTypeString, Since: "v1.21.0"},
+	{Key: "url.path", Type: AttributeTypeString, Since: "v1.21.0"},
+	{Key: "url.query", Type: AttributeTypeString, Since: "v1.21.0"},
+	{Key: "url.scheme", Type: AttributeTypeString, Since: "v1.21.0"},
+	{Key: "url.fragment", Type: AttributeTypeString, Since: "v1.21.0"},
+	{Key: "user_agent.original", Type: AttributeTypeString, Since: "v1.20.0"},
+}
+
+// LookupRegistryAttribute returns the attribute of the registry for the key, or nil
+func LookupRegistryAttribute(key string) *RegistryAttribute {
+	for i, a := range semconvRegistry {
+		if a.Key == key || (a.Template && strings.HasPrefix(key, a.Key) && len(key) > len(a.Key)) {
+			return &semconvRegistry[i]
+		}
+	}
+	return nil
+}
+
+// isRegistryNamespace reports whether the key is in a namespace covered by the registry
+func isRegistryNamespace(key string) bool {
+	namespace, _, found := strings.Cut(key, ".")
+	if !found {
+		return false
+	}
+	for _, ns := range registryNamespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
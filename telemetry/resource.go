@@ -0,0 +1,113 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	semconv1120 "go.opentelemetry.io/otel/semconv/v1.12.0"
+	semconv1170 "go.opentelemetry.io/otel/semconv/v1.17.0"
+	semconv1200 "go.opentelemetry.io/otel/semconv/v1.20.0"
+	semconv1210 "go.opentelemetry.io/otel/semconv/v1.21.0"
+	semconv1240 "go.opentelemetry.io/otel/semconv/v1.24.0"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	semconv1300 "go.opentelemetry.io/otel/semconv/v1.30.0"
+	semconv1320 "go.opentelemetry.io/otel/semconv/v1.32.0"
+	semconv140 "go.opentelemetry.io/otel/semconv/v1.4.0"
+)
+
+// DefaultSemconvVersion is the semantic conventions version used by resources
+// which don't specify one. It matches the version of the SDK default resource
+// so that merging the defaults never causes a schema URL conflict.
+const DefaultSemconvVersion = "v1.26.0"
+
+// semconvVersions lists the supported semantic conventions versions in ascending order
+var semconvVersions = []struct {
+	version   string
+	schemaURL string
+}{
+	{"v1.4.0", semconv140.SchemaURL},
+	{"v1.12.0", semconv1120.SchemaURL},
+	{"v1.17.0", semconv1170.SchemaURL},
+	{"v1.20.0", semconv1200.SchemaURL},
+	{"v1.21.0", semconv1210.SchemaURL},
+	{"v1.24.0", semconv1240.SchemaURL},
+	{"v1.26.0", semconv.SchemaURL},
+	{"v1.30.0", semconv1300.SchemaURL},
+	{"v1.32.0", semconv1320.SchemaURL},
+}
+
+// SupportedSemconvVersions returns the semantic conventions versions which can be set to a resource
+func SupportedSemconvVersions() []string {
+	versions := make([]string, 0, len(semconvVersions))
+	for _, v := range semconvVersions {
+		versions = append(versions, v.version)
+	}
+	return versions
+}
+
+// IsSemconvVersionSupported reports whether the given semantic conventions version is supported
+func IsSemconvVersionSupported(version string) bool {
+	_, ok := schemaURLForVersion(version)
+	return ok
+}
+
+func schemaURLForVersion(version string) (string, bool) {
+	for _, v := range semconvVersions {
+		if v.version == version {
+			return v.schemaURL, true
+		}
+	}
+	return "", false
+}
+
+// EffectiveSemconvVersion returns the semantic conventions version used when the resource is exported
+func (r *Resource) EffectiveSemconvVersion() string {
+	if r.SemconvVersion == "" {
+		return DefaultSemconvVersion
+	}
+	return r.SemconvVersion
+}
+
+// Build returns the SDK resource which is attached to the spans bound to this resource.
+// The resource name is used as service.name unless the attributes override it. When
+// MergeDefaults is enabled, the SDK default resource and the attributes detected from
+// the environment (OTEL_RESOURCE_ATTRIBUTES, OTEL_SERVICE_NAME) are merged underneath.
+// The merged attributes are exported under the schema URL of the resource's semconv version.
+func (r *Resource) Build(ctx context.Context) (*sdkresource.Resource, error) {
+	schemaURL, ok := schemaURLForVersion(r.EffectiveSemconvVersion())
+	if !ok {
+		return nil, fmt.Errorf("unsupported semconv version: %s", r.SemconvVersion)
+	}
+
+	attrs := []attribute.KeyValue{
+		semconv.ServiceName(r.Name),
+	}
+	for k, v := range r.Attributes {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+
+	res := sdkresource.NewWithAttributes(schemaURL, attrs...)
+	if !r.MergeDefaults {
+		return res, nil
+	}
+
+	env, err := sdkresource.New(ctx, sdkresource.WithFromEnv())
+	if err != nil && !errors.Is(err, sdkresource.ErrPartialResource) {
+		return nil, err
+	}
+	// Merge returns the merged attributes together with ErrSchemaURLConflict when the schema
+	// URLs differ, so the defaults are kept for every semconv version
+	base, err := sdkresource.Merge(sdkresource.Default(), env)
+	if err != nil && !errors.Is(err, sdkresource.ErrSchemaURLConflict) {
+		return nil, err
+	}
+	merged, err := sdkresource.Merge(base, res)
+	if err != nil && !errors.Is(err, sdkresource.ErrSchemaURLConflict) {
+		return nil, err
+	}
+	// The semconv version chosen for the resource always wins over the one of the defaults
+	return sdkresource.NewWithAttributes(schemaURL, merged.Attributes()...), nil
+}
@@ -0,0 +1,92 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestResourceBuild(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		res := &Resource{
+			Name:       "checkout-svc",
+			Attributes: map[string]string{"service.version": "1.0.0"},
+		}
+
+		got, err := res.Build(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "https://opentelemetry.io/schemas/1.26.0", got.SchemaURL())
+		assert.Equal(t, []attribute.KeyValue{
+			attribute.String("service.name", "checkout-svc"),
+			attribute.String("service.version", "1.0.0"),
+		}, got.Attributes())
+	})
+
+	t.Run("SemconvVersion", func(t *testing.T) {
+		res := &Resource{
+			Name:           "checkout-svc",
+			SemconvVersion: "v1.4.0",
+		}
+
+		got, err := res.Build(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "https://opentelemetry.io/schemas/1.4.0", got.SchemaURL())
+	})
+
+	t.Run("UnsupportedSemconvVersion", func(t *testing.T) {
+		res := &Resource{
+			Name:           "checkout-svc",
+			SemconvVersion: "v0.1.0",
+		}
+
+		_, err := res.Build(context.Background())
+		assert.EqualError(t, err, "unsupported semconv version: v0.1.0")
+	})
+
+	t.Run("MergeDefaults", func(t *testing.T) {
+		t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "deployment.environment=ci,service.version=0.0.1")
+
+		res := &Resource{
+			Name:           "checkout-svc",
+			Attributes:     map[string]string{"service.version": "1.0.0"},
+			SemconvVersion: "v1.4.0",
+			MergeDefaults:  true,
+		}
+
+		got, err := res.Build(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "https://opentelemetry.io/schemas/1.4.0", got.SchemaURL(), "Schema URL of the resource should win")
+
+		attrs := got.Set()
+		v, _ := attrs.Value("telemetry.sdk.language")
+		assert.Equal(t, "go", v.AsString(), "SDK default attributes should be merged for another semconv version")
+		v, _ = attrs.Value("deployment.environment")
+		assert.Equal(t, "ci", v.AsString(), "Environment attributes should be merged")
+		v, _ = attrs.Value("service.version")
+		assert.Equal(t, "1.0.0", v.AsString(), "Resource attributes should override the environment")
+		v, _ = attrs.Value("service.name")
+		assert.Equal(t, "checkout-svc", v.AsString(), "Resource name should override the default service name")
+	})
+	t.Run("MergeDefaultsSameSemconvVersion", func(t *testing.T) {
+		t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "deployment.environment=ci")
+
+		res := &Resource{
+			Name:          "checkout-svc",
+			MergeDefaults: true,
+		}
+
+		got, err := res.Build(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "https://opentelemetry.io/schemas/1.26.0", got.SchemaURL())
+
+		attrs := got.Set()
+		v, _ := attrs.Value("telemetry.sdk.language")
+		assert.Equal(t, "go", v.AsString(), "SDK default attributes should be merged")
+		v, _ = attrs.Value("deployment.environment")
+		assert.Equal(t, "ci", v.AsString(), "Environment attributes should be merged")
+		v, _ = attrs.Value("service.name")
+		assert.Equal(t, "checkout-svc", v.AsString(), "Resource name should override the default service name")
+	})
+}
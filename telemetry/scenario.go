@@ -0,0 +1,399 @@
+package telemetry
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gopkg.in/yaml.v3"
+)
+
+// Scenario is the content of a store which can be saved to and loaded from a YAML file
+type Scenario struct {
+	Resources []ScenarioResource `yaml:"resources,omitempty"`
+	Events    []ScenarioEvent    `yaml:"events,omitempty"`
+	Traces    []ScenarioTrace    `yaml:"traces,omitempty"`
+	Templates []ScenarioTemplate `yaml:"templates,omitempty"`
+}
+
+type ScenarioResource struct {
+	Name           string            `yaml:"name"`
+	Attributes     map[string]string `yaml:"attributes,omitempty"`
+	SemconvVersion string            `yaml:"semconv,omitempty"`
+	MergeDefaults  bool              `yaml:"defaults,omitempty"`
+}
+
+type ScenarioEvent struct {
+	Name       string            `yaml:"name"`
+	Attributes map[string]string `yaml:"attributes,omitempty"`
+}
+
+type ScenarioTrace struct {
+	Name            string        `yaml:"name"`
+	InheritResource *bool         `yaml:"inherit_resource,omitempty"`
+	Root            *ScenarioSpan `yaml:"root,omitempty"`
+}
+
+type ScenarioSpan struct {
+	// ID identifies the span as a link target. It is only set to spans which are linked.
+	ID         string            `yaml:"id,omitempty"`
+	Name       string            `yaml:"name"`
+	Resource   string            `yaml:"resource,omitempty"`
+	Attributes map[string]string `yaml:"attributes,omitempty"`
+	// Kind is the span kind (e.g. server), which is internal when empty
+	Kind string `yaml:"kind,omitempty"`
+	// Offset and Duration are the timing of the span (e.g. 1.5ms). The timing is derived
+	// from the parent when Duration is empty.
+	Offset   string `yaml:"offset,omitempty"`
+	Duration string `yaml:"duration,omitempty"`
+	// DurationDistribution is the distribution the duration is sampled from on each send,
+	// e.g. normal(100ms, 20ms), which overrides Duration
+	DurationDistribution *Distribution `yaml:"duration_distribution,omitempty"`
+	// Status is ok or error, and the status is unset when it is empty
+	Status        string          `yaml:"status,omitempty"`
+	StatusMessage string          `yaml:"status_message,omitempty"`
+	Events        []ScenarioEvent `yaml:"events,omitempty"`
+	Links         []ScenarioLink  `yaml:"links,omitempty"`
+	Children      []*ScenarioSpan `yaml:"children,omitempty"`
+}
+
+type ScenarioTemplate struct {
+	Name     string   `yaml:"name"`
+	Params   []string `yaml:"params,omitempty"`
+	Commands []string `yaml:"commands"`
+}
+
+type ScenarioLink struct {
+	// Span is the ID of the linked span
+	Span       string            `yaml:"span"`
+	Attributes map[string]string `yaml:"attributes,omitempty"`
+}
+
+// ReadScenarioFile reads the scenario from the YAML file
+func ReadScenarioFile(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var sc Scenario
+	if err := yaml.Unmarshal(data, &sc); err != nil {
+		return nil, fmt.Errorf("failed to parse scenario %s: %w", path, err)
+	}
+	return &sc, nil
+}
+
+// WriteFile writes the scenario to the YAML file
+func (sc *Scenario) WriteFile(path string) error {
+	data, err := yaml.Marshal(sc)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Scenario returns the content of the store. Traces and resources are sorted by name.
+func (s *Store) Scenario() *Scenario {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sc := &Scenario{}
+	for _, name := range sortedKeys(s.resources) {
+		r := s.resources[name]
+		sc.Resources = append(sc.Resources, ScenarioResource{
+			Name:           r.Name,
+			Attributes:     scenarioAttributes(r.Attributes),
+			SemconvVersion: r.SemconvVersion,
+			MergeDefaults:  r.MergeDefaults,
+		})
+	}
+	for _, name := range sortedKeys(s.events) {
+		sc.Events = append(sc.Events, scenarioEvent(s.events[name]))
+	}
+
+	linked := make(map[*Span]bool)
+	for _, span := range s.spans {
+		for _, link := range span.Links {
+			linked[link.TargetSpan] = true
+		}
+	}
+	for _, name := range sortedKeys(s.traces) {
+		t := s.traces[name]
+		st := ScenarioTrace{Name: t.Name, InheritResource: t.InheritResource}
+		if t.RootSpan != nil {
+			st.Root = scenarioSpan(t.RootSpan, linked)
+		}
+		sc.Traces = append(sc.Traces, st)
+	}
+	for _, name := range sortedKeys(s.templates) {
+		t := s.templates[name]
+		sc.Templates = append(sc.Templates, ScenarioTemplate{Name: t.Name, Params: t.Params, Commands: t.Commands})
+	}
+	return sc
+}
+
+func scenarioSpan(span *Span, linked map[*Span]bool) *ScenarioSpan {
+	ss := &ScenarioSpan{
+		Name:       span.Name,
+		Attributes: scenarioAttributes(span.Attributes),
+	}
+	if linked[span] {
+		ss.ID = span.Handle
+	}
+	if span.Resource != nil {
+		ss.Resource = span.Resource.Name
+	}
+	if span.Kind != trace.SpanKindUnspecified {
+		ss.Kind = span.Kind.String()
+	}
+	if span.Timing != nil {
+		ss.Offset = span.Timing.Offset.String()
+		ss.Duration = span.Timing.Duration.String()
+	}
+	ss.DurationDistribution = span.Duration
+	if span.Status != nil {
+		ss.Status = strings.ToLower(span.Status.Code.String())
+		ss.StatusMessage = span.Status.Description
+	}
+	for _, event := range span.Events {
+		ss.Events = append(ss.Events, scenarioEvent(event))
+	}
+	for _, link := range span.Links {
+		ss.Links = append(ss.Links, ScenarioLink{
+			Span:       link.TargetSpan.Handle,
+			Attributes: scenarioAttributes(link.Attributes),
+		})
+	}
+	for _, child := range span.Children {
+		ss.Children = append(ss.Children, scenarioSpan(child, linked))
+	}
+	return ss
+}
+
+func scenarioEvent(event *Event) ScenarioEvent {
+	return ScenarioEvent{Name: event.Name, Attributes: scenarioAttributes(event.Attributes)}
+}
+
+// LoadScenario replaces the content of the store with the scenario. The store is unchanged
+// if the scenario is invalid. Options and the undo history are kept, and the templates of
+// the scenario are added to the defined templates.
+func (s *Store) LoadScenario(sc *Scenario) error {
+	l := &scenarioLoader{
+		store: &Store{state: newState()},
+		ids:   make(map[string]*Span),
+	}
+	if err := l.load(sc); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.changing()
+	s.state = l.store.state
+	if s.templates == nil {
+		s.templates = make(map[string]*Template)
+	}
+	maps.Copy(s.templates, l.store.templates)
+	return nil
+}
+
+// scenarioLoader builds a new store state from a scenario
+type scenarioLoader struct {
+	store *Store
+	// ids are the spans by scenario span ID
+	ids   map[string]*Span
+	links []pendingLink
+}
+
+// pendingLink is a link which is added after all the spans are loaded
+type pendingLink struct {
+	span *Span
+	link ScenarioLink
+}
+
+func (l *scenarioLoader) load(sc *Scenario) error {
+	st := l.store.state
+	for _, r := range sc.Resources {
+		if _, exists := st.resources[r.Name]; exists {
+			return fmt.Errorf("resource %s is defined more than once", r.Name)
+		}
+		if r.SemconvVersion != "" && !IsSemconvVersionSupported(r.SemconvVersion) {
+			return fmt.Errorf("unsupported semconv version of resource %s: %s", r.Name, r.SemconvVersion)
+		}
+		st.resources[r.Name] = &Resource{
+			Name:           r.Name,
+			Attributes:     attributesOrEmpty(r.Attributes),
+			SemconvVersion: r.SemconvVersion,
+			MergeDefaults:  r.MergeDefaults,
+		}
+	}
+	for _, e := range sc.Events {
+		if _, exists := st.events[e.Name]; exists {
+			return fmt.Errorf("event %s is defined more than once", e.Name)
+		}
+		st.events[e.Name] = &Event{Name: e.Name, Attributes: attributesOrEmpty(e.Attributes)}
+	}
+
+	for _, t := range sc.Traces {
+		if _, exists := st.traces[t.Name]; exists {
+			return fmt.Errorf("trace %s is defined more than once", t.Name)
+		}
+		trace := &Trace{Name: t.Name, InheritResource: t.InheritResource}
+		st.traces[t.Name] = trace
+		if t.Root != nil {
+			root, err := l.loadSpan(t.Root, t.Name)
+			if err != nil {
+				return err
+			}
+			trace.RootSpan = root
+		}
+	}
+
+	for _, t := range sc.Templates {
+		if _, exists := l.store.templates[t.Name]; exists {
+			return fmt.Errorf("template %s is defined more than once", t.Name)
+		}
+		if err := l.store.DefineTemplate(Template{Name: t.Name, Params: t.Params, Commands: t.Commands}); err != nil {
+			return err
+		}
+	}
+
+	for _, pending := range l.links {
+		target, ok := l.ids[pending.link.Span]
+		if !ok {
+			return fmt.Errorf("link target %s of span %s not found", pending.link.Span, pending.span.Handle)
+		}
+		pending.span.AddLink(target, attributesOrEmpty(pending.link.Attributes))
+	}
+	return nil
+}
+
+func (l *scenarioLoader) loadSpan(ss *ScenarioSpan, traceName string) (*Span, error) {
+	st := l.store.state
+	span := &Span{
+		Name:       ss.Name,
+		Attributes: attributesOrEmpty(ss.Attributes),
+	}
+	l.store.registerSpan(span, traceName)
+
+	if ss.ID != "" {
+		if _, exists := l.ids[ss.ID]; exists {
+			return nil, fmt.Errorf("span ID %s is used more than once", ss.ID)
+		}
+		l.ids[ss.ID] = span
+	}
+	if ss.Resource != "" {
+		resource, ok := st.resources[ss.Resource]
+		if !ok {
+			return nil, fmt.Errorf("resource %s of span %s not found", ss.Resource, span.Handle)
+		}
+		span.Resource = resource
+	}
+	if ss.Kind != "" {
+		kind, err := ParseSpanKind(ss.Kind)
+		if err != nil {
+			return nil, fmt.Errorf("invalid kind of span %s: %w", span.Handle, err)
+		}
+		span.Kind = kind
+	}
+	timing, err := scenarioTiming(ss)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timing of span %s: %w", span.Handle, err)
+	}
+	span.Timing = timing
+	span.Duration = ss.DurationDistribution
+	status, err := scenarioStatus(ss)
+	if err != nil {
+		return nil, fmt.Errorf("invalid status of span %s: %w", span.Handle, err)
+	}
+	span.Status = status
+
+	for _, e := range ss.Events {
+		span.AddEvent(l.event(e))
+	}
+	for _, link := range ss.Links {
+		l.links = append(l.links, pendingLink{span: span, link: link})
+	}
+	for _, child := range ss.Children {
+		c, err := l.loadSpan(child, traceName)
+		if err != nil {
+			return nil, err
+		}
+		span.AddChild(c)
+	}
+	return span, nil
+}
+
+// event returns the defined event if it has the same attributes, so that changing the
+// event also changes the span, otherwise a new event only added to the span
+func (l *scenarioLoader) event(e ScenarioEvent) *Event {
+	attrs := attributesOrEmpty(e.Attributes)
+	if defined, ok := l.store.events[e.Name]; ok && maps.Equal(defined.Attributes, attrs) {
+		return defined
+	}
+	return &Event{Name: e.Name, Attributes: attrs}
+}
+
+func scenarioTiming(ss *ScenarioSpan) (*Timing, error) {
+	if ss.Duration == "" {
+		if ss.Offset != "" {
+			return nil, fmt.Errorf("offset %s is specified without duration", ss.Offset)
+		}
+		return nil, nil
+	}
+	duration, err := time.ParseDuration(ss.Duration)
+	if err != nil {
+		return nil, err
+	}
+	timing := &Timing{Duration: duration}
+	if ss.Offset != "" {
+		if timing.Offset, err = time.ParseDuration(ss.Offset); err != nil {
+			return nil, err
+		}
+	}
+	return timing, nil
+}
+
+// scenarioAttributes copies the attributes, returning nil for no attributes so that
+// a store saved and loaded again has the same scenario
+func scenarioAttributes(attrs map[string]string) map[string]string {
+	if len(attrs) == 0 {
+		return nil
+	}
+	return maps.Clone(attrs)
+}
+
+func scenarioStatus(ss *ScenarioSpan) (*Status, error) {
+	switch ss.Status {
+	case "":
+		if ss.StatusMessage != "" {
+			return nil, fmt.Errorf("status message %s is specified without status", ss.StatusMessage)
+		}
+		return nil, nil
+	case "ok":
+		return &Status{Code: codes.Ok, Description: ss.StatusMessage}, nil
+	case "error":
+		return &Status{Code: codes.Error, Description: ss.StatusMessage}, nil
+	}
+	return nil, fmt.Errorf("unknown status %s (ok or error)", ss.Status)
+}
+
+func attributesOrEmpty(attrs map[string]string) map[string]string {
+	if attrs == nil {
+		return map[string]string{}
+	}
+	return attrs
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
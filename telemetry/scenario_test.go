@@ -0,0 +1,297 @@
+package telemetry
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func newScenarioStore(t *testing.T) *Store {
+	t.Helper()
+	s := NewStore()
+	s.CreateResource("cart", map[string]string{"service.name": "cart"})
+	s.CreateEvent("cache-miss", map[string]string{"cache": "redis"})
+	s.CreateTrace("checkout")
+	s.CreateTrace("search")
+	_, err := s.AddSpanToTrace("checkout", "GET /cart", map[string]string{"http.method": "GET"})
+	assert.NoError(t, err)
+	_, err = s.AddSpanToSpan("checkout/GET__cart", "db", map[string]string{})
+	assert.NoError(t, err)
+	_, err = s.AddSpanToTrace("search", "db", map[string]string{})
+	assert.NoError(t, err)
+	_, err = s.SetResourceToSpan("checkout/GET__cart", "cart")
+	assert.NoError(t, err)
+	_, err = s.AddEventToSpan("checkout/db", "cache-miss")
+	assert.NoError(t, err)
+	_, err = s.AddLinkToSpan("search/db", "checkout/db", map[string]string{"reason": "retry"})
+	assert.NoError(t, err)
+	s.spans["checkout/db"].Timing = &Timing{Offset: 5 * time.Millisecond, Duration: 20 * time.Millisecond}
+	s.spans["checkout/db"].Status = &Status{Code: codes.Error, Description: "timeout"}
+	s.spans["search/db"].Duration = &Distribution{Kind: "normal", Params: []time.Duration{100 * time.Millisecond, 20 * time.Millisecond}}
+	s.spans["search/db"].Kind = oteltrace.SpanKindClient
+	return s
+}
+
+func TestStoreScenario(t *testing.T) {
+	s := newScenarioStore(t)
+
+	sc := s.Scenario()
+	assert.Equal(t, &Scenario{
+		Resources: []ScenarioResource{
+			{Name: "cart", Attributes: map[string]string{"service.name": "cart"}},
+		},
+		Events: []ScenarioEvent{
+			{Name: "cache-miss", Attributes: map[string]string{"cache": "redis"}},
+		},
+		Traces: []ScenarioTrace{
+			{
+				Name: "checkout",
+				Root: &ScenarioSpan{
+					Name:       "GET /cart",
+					Resource:   "cart",
+					Attributes: map[string]string{"http.method": "GET"},
+					Children: []*ScenarioSpan{
+						{
+							ID:            "checkout/db",
+							Name:          "db",
+							Offset:        "5ms",
+							Duration:      "20ms",
+							Status:        "error",
+							StatusMessage: "timeout",
+							Events: []ScenarioEvent{
+								{Name: "cache-miss", Attributes: map[string]string{"cache": "redis"}},
+							},
+						},
+					},
+				},
+			},
+			{
+				Name: "search",
+				Root: &ScenarioSpan{
+					Name:                 "db",
+					Kind:                 "client",
+					DurationDistribution: &Distribution{Kind: "normal", Params: []time.Duration{100 * time.Millisecond, 20 * time.Millisecond}},
+					Links: []ScenarioLink{
+						{Span: "checkout/db", Attributes: map[string]string{"reason": "retry"}},
+					},
+				},
+			},
+		},
+	}, sc)
+
+	path := filepath.Join(t.TempDir(), "scenario.yaml")
+	assert.NoError(t, sc.WriteFile(path))
+	read, err := ReadScenarioFile(path)
+	assert.NoError(t, err)
+
+	loaded := NewStore()
+	assert.NoError(t, loaded.LoadScenario(read))
+	assert.Equal(t, sc, loaded.Scenario())
+
+	db, err := loaded.LookupSpan("checkout/db")
+	assert.NoError(t, err)
+	assert.Same(t, loaded.GetEvents()["cache-miss"], db.Events[0], "Event with the same attributes should be shared")
+	assert.Same(t, db, loaded.GetSpans()["search/db"].Links[0].TargetSpan)
+}
+
+func TestStoreLoadScenario_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		sc   *Scenario
+		want string
+	}{
+		{
+			name: "Unknown resource",
+			sc:   &Scenario{Traces: []ScenarioTrace{{Name: "t", Root: &ScenarioSpan{Name: "root", Resource: "cart"}}}},
+			want: "resource cart of span t/root not found",
+		},
+		{
+			name: "Unknown link target",
+			sc:   &Scenario{Traces: []ScenarioTrace{{Name: "t", Root: &ScenarioSpan{Name: "root", Links: []ScenarioLink{{Span: "x"}}}}}},
+			want: "link target x of span t/root not found",
+		},
+		{
+			name: "Invalid duration",
+			sc:   &Scenario{Traces: []ScenarioTrace{{Name: "t", Root: &ScenarioSpan{Name: "root", Duration: "fast"}}}},
+			want: `invalid timing of span t/root: time: invalid duration "fast"`,
+		},
+		{
+			name: "Offset without duration",
+			sc:   &Scenario{Traces: []ScenarioTrace{{Name: "t", Root: &ScenarioSpan{Name: "root", Offset: "1ms"}}}},
+			want: "invalid timing of span t/root: offset 1ms is specified without duration",
+		},
+		{
+			name: "Unknown status",
+			sc:   &Scenario{Traces: []ScenarioTrace{{Name: "t", Root: &ScenarioSpan{Name: "root", Status: "failed"}}}},
+			want: "invalid status of span t/root: unknown status failed (ok or error)",
+		},
+		{
+			name: "Unknown kind",
+			sc:   &Scenario{Traces: []ScenarioTrace{{Name: "t", Root: &ScenarioSpan{Name: "root", Kind: "sideways"}}}},
+			want: "invalid kind of span t/root: unknown span kind sideways (internal, server, client, producer or consumer)",
+		},
+		{
+			name: "Duplicate trace",
+			sc:   &Scenario{Traces: []ScenarioTrace{{Name: "t"}, {Name: "t"}}},
+			want: "trace t is defined more than once",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newScenarioStore(t)
+			before := s.Scenario()
+			err := s.LoadScenario(tt.sc)
+			assert.EqualError(t, err, tt.want)
+			assert.Equal(t, before, s.Scenario(), "Store should be unchanged")
+		})
+	}
+}
+
+func TestStoreSend_Timing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tm, err := NewTracerManager(func() (trace.SpanExporter, error) {
+		return tracetest.NewNoopExporter(), nil
+	}, func() (trace.SpanProcessor, error) {
+		return recorder, nil
+	})
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, tm.Shutdown(context.Background()))
+	})
+
+	s := NewStore()
+	s.CreateTrace("t")
+	root, err := s.AddSpanToTrace("t", "root", map[string]string{})
+	assert.NoError(t, err)
+	root.Timing = &Timing{Duration: 100 * time.Millisecond}
+	child, err := s.AddSpanToSpan("root", "child", map[string]string{})
+	assert.NoError(t, err)
+	child.Timing = &Timing{Offset: 10 * time.Millisecond, Duration: 30 * time.Millisecond}
+	child.Status = &Status{Code: codes.Error, Description: "timeout"}
+	_, err = s.AddSpanToSpan("root", "derived", map[string]string{})
+	assert.NoError(t, err)
+
+	_, err = s.Send(context.Background(), tm)
+	assert.NoError(t, err)
+
+	spans := make(map[string]trace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	rootStart := spans["root"].StartTime()
+	assert.Equal(t, 100*time.Millisecond, spans["root"].EndTime().Sub(rootStart))
+	assert.Equal(t, 10*time.Millisecond, spans["child"].StartTime().Sub(rootStart))
+	assert.Equal(t, 30*time.Millisecond, spans["child"].EndTime().Sub(spans["child"].StartTime()))
+	assert.Equal(t, trace.Status{Code: codes.Error, Description: "timeout"}, spans["child"].Status())
+	assert.Equal(t, 90*time.Millisecond, spans["derived"].EndTime().Sub(spans["derived"].StartTime()), "Span without timing should take 90% of the recorded parent")
+}
+
+func TestStoreSend_Jitter(t *testing.T) {
+	s := NewStore()
+	s.CreateTrace("t")
+	root, err := s.AddSpanToTrace("t", "root", map[string]string{})
+	assert.NoError(t, err)
+	root.Duration = &Distribution{Kind: "uniform", Params: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}}
+	child, err := s.AddSpanToSpan("root", "child", map[string]string{})
+	assert.NoError(t, err)
+	child.Timing = &Timing{Offset: 10 * time.Millisecond, Duration: 50 * time.Millisecond}
+	_, err = s.AddSpanToSpan("root", "derived", map[string]string{})
+	assert.NoError(t, err)
+	assert.NoError(t, s.SetJitter(0.2))
+
+	send := func(opts ...SendOption) (*SendResult, map[string]trace.ReadOnlySpan) {
+		recorder := tracetest.NewSpanRecorder()
+		tm, err := NewTracerManager(func() (trace.SpanExporter, error) {
+			return tracetest.NewNoopExporter(), nil
+		}, func() (trace.SpanProcessor, error) {
+			return recorder, nil
+		})
+		assert.NoError(t, err)
+		t.Cleanup(func() {
+			assert.NoError(t, tm.Shutdown(context.Background()))
+		})
+		result, err := s.Send(context.Background(), tm, opts...)
+		assert.NoError(t, err)
+		spans := make(map[string]trace.ReadOnlySpan)
+		for _, span := range recorder.Ended() {
+			spans[span.Name()] = span
+		}
+		return result, spans
+	}
+	durationOf := func(span trace.ReadOnlySpan) time.Duration {
+		return span.EndTime().Sub(span.StartTime())
+	}
+
+	result, spans := send(WithSeed(42))
+	assert.True(t, result.Sampled)
+	assert.Equal(t, int64(42), result.Seed)
+	rootDuration := durationOf(spans["root"])
+	assert.GreaterOrEqual(t, rootDuration, 80*time.Millisecond)
+	assert.LessOrEqual(t, rootDuration, 240*time.Millisecond)
+	assert.InDelta(t, float64(50*time.Millisecond), float64(durationOf(spans["child"])), float64(10*time.Millisecond))
+	assert.InDelta(t, float64(10*time.Millisecond), float64(spans["child"].StartTime().Sub(spans["root"].StartTime())), float64(2*time.Millisecond))
+	assert.LessOrEqual(t, durationOf(spans["derived"]), rootDuration, "Span without timing should fit in its parent")
+	assert.False(t, spans["derived"].StartTime().Before(spans["root"].StartTime()))
+
+	_, again := send(WithSeed(42))
+	for _, name := range []string{"root", "child", "derived"} {
+		assert.Equal(t, durationOf(spans[name]), durationOf(again[name]), "Same seed should give the same duration of %s", name)
+	}
+
+	durations := make(map[time.Duration]bool)
+	for seed := range int64(10) {
+		_, spans := send(WithSeed(seed))
+		durations[durationOf(spans["root"])] = true
+	}
+	assert.Greater(t, len(durations), 1, "Different seeds should give different durations")
+
+	assert.NoError(t, s.SetJitter(0))
+	root.Duration = nil
+	result, _ = send()
+	assert.False(t, result.Sampled, "Nothing should be sampled without the jitter and the distributions")
+}
+
+func TestStoreLoadScenario_UndoSpanEvents(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tm, err := NewTracerManager(func() (trace.SpanExporter, error) {
+		return tracetest.NewNoopExporter(), nil
+	}, func() (trace.SpanProcessor, error) {
+		return recorder, nil
+	})
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, tm.Shutdown(context.Background()))
+	})
+
+	s := NewStore()
+	assert.NoError(t, s.LoadScenario(&Scenario{
+		Traces: []ScenarioTrace{{
+			Name: "t",
+			Root: &ScenarioSpan{
+				Name:   "root",
+				Events: []ScenarioEvent{{Name: "retry", Attributes: map[string]string{"attempt": "2"}}},
+			},
+		}},
+	}))
+	s.Track("create resource db", func() {
+		s.CreateResource("db", nil)
+	})
+	_, err = s.Undo()
+	assert.NoError(t, err)
+
+	_, err = s.Send(context.Background(), tm)
+	assert.NoError(t, err)
+	if spans := recorder.Ended(); assert.Len(t, spans, 1) {
+		if events := spans[0].Events(); assert.Len(t, events, 1) {
+			assert.Equal(t, "retry", events[0].Name, "Events only added to the span should survive undo")
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package telemetry
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// SpanSelector selects spans to change at once, e.g. the spans named db_* which are bound
+// to the resource postgres in the trace checkout. Empty fields match any span.
+type SpanSelector struct {
+	// Pattern is a glob matched against the name and the handle of the span,
+	// where * matches any characters and ? matches one character
+	Pattern    string
+	Resource   string
+	Attributes map[string]string
+	Trace      string
+}
+
+// globToRegexp converts the glob to a regular expression matching the whole string
+func globToRegexp(glob string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for _, r := range glob {
+		switch r {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String())
+}
+
+func (sel SpanSelector) matches(span *Span, pattern *regexp.Regexp) bool {
+	if !pattern.MatchString(span.Name) && !pattern.MatchString(span.Handle) {
+		return false
+	}
+	if sel.Resource != "" && (span.Resource == nil || span.Resource.Name != sel.Resource) {
+		return false
+	}
+	for key, value := range sel.Attributes {
+		if v, ok := span.Attributes[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+// SelectSpans returns the spans matching the selector, ordered by trace name and
+// then from the root span down, so that parents come before their descendants
+func (s *Store) SelectSpans(sel SpanSelector) ([]*Span, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if sel.Trace != "" {
+		if _, exists := s.traces[sel.Trace]; !exists {
+			return nil, fmt.Errorf("trace '%s' does not exist", sel.Trace)
+		}
+	}
+
+	pattern := sel.Pattern
+	if pattern == "" {
+		pattern = "*"
+	}
+	re := globToRegexp(pattern)
+
+	names := make([]string, 0, len(s.traces))
+	for name := range s.traces {
+		if sel.Trace == "" || name == sel.Trace {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var spans []*Span
+	for _, name := range names {
+		root := s.traces[name].RootSpan
+		if root == nil {
+			continue
+		}
+		walkSpan(root, func(span *Span) {
+			if sel.matches(span, re) {
+				spans = append(spans, span)
+			}
+		})
+	}
+	return spans, nil
+}
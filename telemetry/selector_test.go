@@ -0,0 +1,82 @@
+package telemetry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreSelectSpans(t *testing.T) {
+	s := NewStore()
+	s.CreateResource("db", map[string]string{})
+	s.CreateTrace("checkout")
+	s.CreateTrace("search")
+	_, _ = s.AddSpanToTrace("checkout", "GET /cart", map[string]string{"http.method": "GET"})
+	_, _ = s.AddSpanToSpan("checkout/GET__cart", "db_query", map[string]string{"db.system": "postgresql"})
+	_, _ = s.AddSpanToSpan("checkout/db_query", "db_insert", map[string]string{})
+	_, _ = s.AddSpanToTrace("search", "db_query", map[string]string{"db.system": "mysql"})
+	_, _ = s.SetResourceToSpan("checkout/db_query", "db")
+
+	tests := []struct {
+		name    string
+		sel     SpanSelector
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "Glob on names in trace order",
+			sel:  SpanSelector{Pattern: "db_*"},
+			want: []string{"checkout/db_query", "checkout/db_insert", "search/db_query"},
+		},
+		{
+			name: "Glob with spaces and one character",
+			sel:  SpanSelector{Pattern: "GET /ca?t"},
+			want: []string{"checkout/GET__cart"},
+		},
+		{
+			name: "Glob on handles",
+			sel:  SpanSelector{Pattern: "search/*"},
+			want: []string{"search/db_query"},
+		},
+		{
+			name: "Empty pattern matches all",
+			sel:  SpanSelector{Trace: "search"},
+			want: []string{"search/db_query"},
+		},
+		{
+			name: "Resource",
+			sel:  SpanSelector{Pattern: "*", Resource: "db"},
+			want: []string{"checkout/db_query"},
+		},
+		{
+			name: "Attribute",
+			sel:  SpanSelector{Pattern: "db_*", Attributes: map[string]string{"db.system": "mysql"}},
+			want: []string{"search/db_query"},
+		},
+		{
+			name: "No match",
+			sel:  SpanSelector{Pattern: "cache_*"},
+		},
+		{
+			name:    "Unknown trace",
+			sel:     SpanSelector{Pattern: "*", Trace: "nope"},
+			wantErr: "trace 'nope' does not exist",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spans, err := s.SelectSpans(tt.sel)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			var handles []string
+			for _, span := range spans {
+				handles = append(handles, span.Handle)
+			}
+			assert.Equal(t, tt.want, handles)
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SendResult describes the traces sent by Store.Send
+type SendResult struct {
+	// Traces are the sent traces in the order of their names
+	Traces []SentTrace
+	// Warnings are the problems which didn't stop sending, e.g. links to spans which were not sent
+	Warnings []string
+	// SpanContexts are the span contexts of the sent spans keyed by handle
+	SpanContexts map[string]trace.SpanContext
+	// Seed is the seed of the random numbers for the durations, the jitter, the generated
+	// attribute values and the injected errors, and Sampled reports whether any of them is used,
+	// so that the same seed reproduces the same traces
+	Seed    int64
+	Sampled bool
+	// Errors is the number of the spans failed by the error injections
+	Errors int
+}
+
+// SendOption configures Store.Send
+type SendOption func(*sender)
+
+// WithSeed sets the seed of the random numbers used on the send instead of a random seed
+func WithSeed(seed int64) SendOption {
+	return func(sd *sender) {
+		sd.result.Seed = seed
+	}
+}
+
+// SentTrace is the number of spans sent for a trace. A trace without spans has Spans 0
+// and an invalid TraceID.
+type SentTrace struct {
+	Name    string
+	TraceID trace.TraceID
+	Spans   int
+}
+
+// Send exports all the traces in the store through the tracer manager.
+// Unlike SendAllTraces, the store is not reset after sending.
+func (s *Store) Send(ctx context.Context, tm *TracerManager, opts ...SendOption) (*SendResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	traces := make([]*Trace, 0, len(s.traces))
+	for _, t := range s.traces {
+		traces = append(traces, t)
+	}
+	sort.Slice(traces, func(i, j int) bool {
+		return traces[i].Name < traces[j].Name
+	})
+
+	sd := &sender{
+		ctx:    ctx,
+		tm:     tm,
+		jitter: s.options.Jitter,
+		spans:  make(map[*Span]*spanToProcess),
+		result: &SendResult{SpanContexts: make(map[string]trace.SpanContext), Seed: rand.Int64()},
+	}
+	for _, opt := range opts {
+		opt(sd)
+	}
+	sd.rand = NewRand(sd.result.Seed)
+	sd.values = &valueGenerator{rand: sd.rand, sequences: &s.sequences}
+	var err error
+	for _, traceData := range traces {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		sent := SentTrace{Name: traceData.Name}
+		if traceData.RootSpan != nil {
+			sd.processSpan(nil, traceData.RootSpan, &sent.Spans, nil, nil, traceData.ShouldInheritResource(s.options))
+			sent.TraceID = sd.spans[traceData.RootSpan].span.SpanContext().TraceID()
+		}
+		sd.result.Traces = append(sd.result.Traces, sent)
+	}
+	// loop again to link spans and finish them, also when cancelled so that no span is left open
+	for _, storedSpan := range sd.order {
+		span := sd.spans[storedSpan]
+		for _, link := range storedSpan.Links {
+			if linkedSpan, exists := sd.spans[link.TargetSpan]; exists {
+				span.span.AddLink(trace.Link{
+					SpanContext: linkedSpan.span.SpanContext(),
+					Attributes:  sd.attributes(link.Attributes, "the link from span '"+storedSpan.Handle+"'"),
+				})
+			} else {
+				sd.warnf("Linked span '%s' not found for span '%s'.", link.TargetSpan.Handle, storedSpan.Handle)
+			}
+		}
+		span.End()
+	}
+
+	return sd.result, err
+}
+
+type spanToProcess struct {
+	span   trace.Span
+	timing spanTiming
+	// status is set when the span ends, so that an injected error overrides the status in the store
+	status *Status
+	parent *spanToProcess
+}
+
+func (s *spanToProcess) End() {
+	if s.status != nil {
+		s.span.SetStatus(s.status.Code, s.status.Description)
+	}
+	s.span.End(trace.WithTimestamp(s.timing.start.Add(s.timing.duration)))
+}
+
+// sender holds the state of a single Store.Send call
+type sender struct {
+	ctx    context.Context
+	tm     *TracerManager
+	jitter float64
+	rand   *rand.Rand
+	values *valueGenerator
+	spans  map[*Span]*spanToProcess
+	// order is the spans in the order they are started, so that the links are generated in the same order
+	order      []*Span
+	injections []ErrorInjection
+	result     *SendResult
+}
+
+func (sd *sender) warnf(format string, args ...any) {
+	sd.result.Warnings = append(sd.result.Warnings, fmt.Sprintf(format, args...))
+}
+
+// processSpan creates the span and its descendants. The duration of the span is
+//   - sampled from its duration distribution if any
+//   - the duration of its recorded timing if any
+//   - 1 second for a root span
+//   - otherwise 90% of the duration of its parent
+//
+// then scaled by the jitter factor of the span. A root span ends at the current time. Spans with
+// a recorded timing start at the offset from their parent's start, which is scaled by the jitter
+// factor of the parent, and the other spans are centered within their parent's timeframe.
+//
+// When inherit is true, spans without a resource use parentResource, the resource of their parent.
+// parent is nil for a root span.
+func (sd *sender) processSpan(parentCtx context.Context, s *Span, spanCount *int, parent *spanToProcess, parentResource *Resource, inherit bool) {
+	var tracer trace.Tracer
+	resource, _ := ResolveResource(s, parentResource, inherit)
+	if resource != nil {
+		t, err := sd.tm.CreateTracerForResource(sd.ctx, resource.Name, resource)
+		if err != nil {
+			sd.warnf("Failed to create tracer for resource '%s': %v", resource.Name, err)
+			tracer = sd.tm.GetDefaultTracer()
+		} else {
+			tracer = t
+		}
+	} else {
+		// Use default tracer when no resource is attached to span
+		tracer = sd.tm.GetDefaultTracer()
+	}
+	attrs := sd.attributes(s.Attributes, "span '"+s.Handle+"'")
+
+	var (
+		spanCtx context.Context
+		span    trace.Span
+		timing  = spanTiming{factor: sd.jitterFactor()}
+	)
+
+	switch {
+	case s.Duration != nil:
+		sd.result.Sampled = true
+		timing.duration = scaleDuration(s.Duration.Sample(sd.rand), timing.factor)
+	case s.Timing != nil:
+		timing.duration = scaleDuration(s.Timing.Duration, timing.factor)
+	case parent == nil:
+		timing.duration = scaleDuration(time.Second, timing.factor)
+	default:
+		// a derived span never takes longer than its parent even with the jitter
+		timing.duration = min(scaleDuration(parent.timing.duration, derivedDurationRatio*timing.factor), parent.timing.duration)
+	}
+
+	if parent == nil {
+		timing.start = time.Now().Add(-timing.duration)
+
+		// the caller's context may carry a span, which must not become the parent of the trace
+		spanCtx, span = tracer.Start(sd.ctx, s.Name, trace.WithNewRoot(), trace.WithSpanKind(s.Kind), trace.WithAttributes(attrs...), trace.WithTimestamp(timing.start))
+	} else {
+		if s.Timing != nil {
+			timing.start = parent.timing.start.Add(scaleDuration(s.Timing.Offset, parent.timing.factor))
+		} else {
+			timing.start = parent.timing.start.Add(max(parent.timing.duration-timing.duration, 0) / 2)
+		}
+
+		spanCtx, span = tracer.Start(parentCtx, s.Name, trace.WithSpanKind(s.Kind), trace.WithAttributes(attrs...), trace.WithTimestamp(timing.start))
+	}
+
+	for _, event := range s.Events {
+		eventAttrs := sd.attributes(event.Attributes, "event '"+event.Name+"' of span '"+s.Handle+"'")
+		span.AddEvent(event.Name, trace.WithAttributes(eventAttrs...))
+	}
+
+	sp := &spanToProcess{
+		span:   span,
+		timing: timing,
+		status: s.Status,
+		parent: parent,
+	}
+	sd.injectError(s, sp, resource)
+	sd.order = append(sd.order, s)
+	sd.spans[s] = sp
+	sd.result.SpanContexts[s.Handle] = span.SpanContext()
+
+	*spanCount++
+
+	for _, childSpan := range s.Children {
+		sd.processSpan(spanCtx, childSpan, spanCount, sp, resource, inherit)
+	}
+}
+
+// derivedDurationRatio is the ratio of the duration of a span without a timing to its parent
+const derivedDurationRatio = 0.9
+
+// spanTiming is the timing of a sent span and the jitter factor applied to it
+type spanTiming struct {
+	start    time.Time
+	duration time.Duration
+	factor   float64
+}
+
+// jitterFactor returns a factor between 1-jitter and 1+jitter, which is 1 without the jitter
+func (sd *sender) jitterFactor() float64 {
+	if sd.jitter == 0 {
+		return 1
+	}
+	sd.result.Sampled = true
+	return 1 - sd.jitter + sd.rand.Float64()*2*sd.jitter
+}
+
+// attributes converts the attributes of the owner, evaluating the generator expressions in the values.
+// The keys are sorted so that the same seed generates the same values.
+func (sd *sender) attributes(attrs map[string]string, owner string) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for _, k := range sortedKeys(attrs) {
+		v := attrs[k]
+		if HasValueGenerator(v) {
+			sd.result.Sampled = true
+			generated, err := sd.values.evaluate(k, v)
+			if err != nil {
+				sd.warnf("Invalid value of attribute '%s' of %s is sent as it is: %v", k, owner, err)
+			}
+			v = generated
+		}
+		kvs = append(kvs, attribute.String(k, v))
+	}
+	return kvs
+}
+
+func scaleDuration(d time.Duration, factor float64) time.Duration {
+	return time.Duration(float64(d) * factor)
+}
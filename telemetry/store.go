@@ -1,18 +1,23 @@
 package telemetry
 
 import (
-	"context"
 	"fmt"
 	"maps"
+	"sync"
 	"time"
 
-	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 )
 
 type Resource struct {
 	Name       string
 	Attributes map[string]string
+	// SemconvVersion is the semantic conventions version (e.g. v1.26.0) of the resource.
+	// DefaultSemconvVersion is used when empty.
+	SemconvVersion string
+	// MergeDefaults merges the SDK default resource and the environment into the resource
+	MergeDefaults bool
 }
 
 type Link struct {
@@ -26,12 +31,55 @@ type Event struct {
 }
 
 type Span struct {
+	// Handle is the unique key of the span in the store. Unlike the name, it is unique across traces.
+	Handle     string
 	Name       string
 	Attributes map[string]string
 	Children   []*Span
 	Resource   *Resource
 	Links      []*Link
 	Events     []*Event
+	// Timing is the recorded timing of the span. The timing is derived from the parent when nil.
+	Timing *Timing
+	// Status is the status of the span, which is unset when nil
+	Status *Status
+	// Duration is the distribution the duration is sampled from on each send. It overrides
+	// the duration of Timing, and like Timing, it is not changed after it is set to a span.
+	Duration *Distribution
+	// Kind is the span kind, which is sent as internal when unspecified
+	Kind trace.SpanKind
+}
+
+// Status is the status of a span. Like Timing, it is not changed after it is set to a span.
+type Status struct {
+	Code        codes.Code
+	Description string
+}
+
+// spanKinds are the span kinds which can be set to a span by name
+var spanKinds = []trace.SpanKind{
+	trace.SpanKindInternal,
+	trace.SpanKindServer,
+	trace.SpanKindClient,
+	trace.SpanKindProducer,
+	trace.SpanKindConsumer,
+}
+
+// ParseSpanKind returns the span kind of the name, e.g. server
+func ParseSpanKind(name string) (trace.SpanKind, error) {
+	for _, kind := range spanKinds {
+		if kind.String() == name {
+			return kind, nil
+		}
+	}
+	return trace.SpanKindUnspecified, fmt.Errorf("unknown span kind %s (internal, server, client, producer or consumer)", name)
+}
+
+// Timing is the start of a span relative to the start of its parent and its duration.
+// It is not changed after it is set to a span, so it can be shared between copies.
+type Timing struct {
+	Offset   time.Duration
+	Duration time.Duration
 }
 
 func (s *Span) AddChild(child *Span) {
@@ -53,19 +101,41 @@ func (s *Span) AddEvent(event *Event) {
 type Trace struct {
 	Name     string
 	RootSpan *Span
+	// InheritResource overrides the store-wide resource inheritance option when not nil
+	InheritResource *bool
 }
 
-type Store struct {
+// ShouldInheritResource reports whether spans in the trace without a resource
+// use the resource of their parent span under the store options
+func (t *Trace) ShouldInheritResource(opts Options) bool {
+	if t.InheritResource != nil {
+		return *t.InheritResource
+	}
+	return opts.InheritResource
+}
+
+// ResolveResource returns the resource used when the span is sent and whether it
+// is inherited from an ancestor. parent is the resolved resource of the parent span.
+func ResolveResource(s *Span, parent *Resource, inherit bool) (*Resource, bool) {
+	if s.Resource != nil {
+		return s.Resource, false
+	}
+	if inherit && parent != nil {
+		return parent, true
+	}
+	return nil, false
+}
+
+// state is the content of a store which is swapped as a whole by undo and redo
+type state struct {
 	traces    map[string]*Trace
 	spans     map[string]*Span
 	resources map[string]*Resource
 	events    map[string]*Event
 }
 
-var store *Store
-
-func InitStore() {
-	store = &Store{
+func newState() *state {
+	return &state{
 		traces:    make(map[string]*Trace),
 		spans:     make(map[string]*Span),
 		resources: make(map[string]*Resource),
@@ -73,137 +143,302 @@ func InitStore() {
 	}
 }
 
-func GetTraces() map[string]*Trace {
-	return store.traces
-}
-
-func GetSpans() map[string]*Span {
-	return store.spans
-}
-
-func GetResources() map[string]*Resource {
-	return store.resources
-}
-
-func GetEvents() map[string]*Event {
-	return store.events
-}
-
-func IsTraceExists(name string) bool {
-	_, exists := store.traces[name]
+// Store holds the traces, spans, resources and events of a scenario.
+// Its methods are safe for concurrent use. The returned signals point into the store,
+// so they must only be changed through the store methods while it is shared.
+type Store struct {
+	mu sync.RWMutex
+	*state
+	options   Options
+	history   history
+	sequences sequences
+	templates map[string]*Template
+	// workspace is the name of the current workspace, whose signals are in state.
+	// The other workspaces are kept in workspaces by name.
+	workspace  string
+	workspaces map[string]*workspace
+	// tracking is set while Track runs its function, and pending is the state
+	// before the first change made in the meantime
+	tracking bool
+	pending  *state
+}
+
+// NewStore returns an empty store with the default options
+func NewStore() *Store {
+	return &Store{state: newState(), workspace: DefaultWorkspace}
+}
+
+// Reset removes all the signals and the undo history of the current workspace.
+// Options, templates and the other workspaces are kept.
+func (s *Store) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = newState()
+	s.history = history{}
+}
+
+// GetTraces returns a copy of the traces keyed by name
+func (s *Store) GetTraces() map[string]*Trace {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return maps.Clone(s.traces)
+}
+
+// GetSpans returns a copy of the spans keyed by handle
+func (s *Store) GetSpans() map[string]*Span {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return maps.Clone(s.spans)
+}
+
+// GetResources returns a copy of the resources keyed by name
+func (s *Store) GetResources() map[string]*Resource {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return maps.Clone(s.resources)
+}
+
+// GetEvents returns a copy of the events keyed by name
+func (s *Store) GetEvents() map[string]*Event {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return maps.Clone(s.events)
+}
+
+func (s *Store) IsTraceExists(name string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, exists := s.traces[name]
 	return exists
 }
 
-func IsSpanExists(name string) bool {
-	_, exists := store.spans[name]
-	return exists
+// IsSpanExists reports whether the reference (handle or unique name) resolves to a span
+func (s *Store) IsSpanExists(ref string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, err := s.lookupSpan(ref)
+	return err == nil
 }
 
-func IsResourceExists(name string) bool {
-	_, exists := store.resources[name]
+func (s *Store) IsResourceExists(name string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, exists := s.resources[name]
 	return exists
 }
 
-func IsEventExists(name string) bool {
-	_, exists := store.events[name]
+func (s *Store) IsEventExists(name string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, exists := s.events[name]
 	return exists
 }
 
-func CreateTrace(name string) *Trace {
+func (s *Store) CreateTrace(name string) *Trace {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.changing()
 	trace := &Trace{
 		Name: name,
 	}
-	store.traces[name] = trace
+	s.traces[name] = trace
 	return trace
 }
 
-func UpdateSpan(name, newName, resource string, attributes map[string]string) (*Span, error) {
-	span, ok := store.spans[name]
+// AddTrace adds a new trace with the span tree built outside the store, registering the handles
+// of the spans. The resources of the spans must be the resources in the store.
+func (s *Store) AddTrace(name string, root *Span) (*Trace, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.traces[name]; exists {
+		return nil, fmt.Errorf("trace with name %s already exists", name)
+	}
+	var err error
+	walkSpan(root, func(span *Span) {
+		if err == nil && span.Resource != nil && s.resources[span.Resource.Name] != span.Resource {
+			err = fmt.Errorf("resource %s of span %s is not in the store", span.Resource.Name, span.Name)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.changing()
+	trace := &Trace{
+		Name:     name,
+		RootSpan: root,
+	}
+	s.traces[name] = trace
+	walkSpan(root, func(span *Span) {
+		s.registerSpan(span, name)
+	})
+	return trace, nil
+}
+
+func (s *Store) SetTraceInheritResource(name string, inherit *bool) (*Trace, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	trace, ok := s.traces[name]
 	if !ok {
-		return nil, fmt.Errorf("span %s not found", name)
+		return nil, fmt.Errorf("trace %s not found", name)
+	}
+	s.changing()
+	trace.InheritResource = inherit
+	return trace, nil
+}
+
+func (s *Store) UpdateSpan(ref, newName, resource string, attributes map[string]string) (*Span, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	span, err := s.lookupSpan(ref)
+	if err != nil {
+		return nil, err
 	}
 	if newName != "" {
-		if _, exists := store.spans[newName]; exists {
-			return nil, fmt.Errorf("span with name %s already exists", newName)
-		}
-		delete(store.spans, name)
+		s.changing()
+		delete(s.spans, span.Handle)
 		span.Name = newName
-		store.spans[newName] = span
+		s.registerSpan(span, s.traceNameOf(span))
 	}
 	if resource != "" {
-		res, ok := store.resources[resource]
+		res, ok := s.resources[resource]
 		if !ok {
 			return nil, fmt.Errorf("resource %s not found", resource)
 		}
+		s.changing()
 		span.Resource = res
 	}
 	if attributes != nil {
+		s.changing()
 		span.Attributes = make(map[string]string)
 		maps.Copy(span.Attributes, attributes)
 	}
 	return span, nil
 }
 
-func CreateResource(name string, attributes map[string]string) *Resource {
+// SetSpanDuration sets the distribution of the duration of the span, or removes it when nil
+func (s *Store) SetSpanDuration(ref string, duration *Distribution) (*Span, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	span, err := s.lookupSpan(ref)
+	if err != nil {
+		return nil, err
+	}
+	s.changing()
+	span.Duration = duration
+	return span, nil
+}
+
+// SetSpanKind sets the kind of the span
+func (s *Store) SetSpanKind(ref string, kind trace.SpanKind) (*Span, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	span, err := s.lookupSpan(ref)
+	if err != nil {
+		return nil, err
+	}
+	s.changing()
+	span.Kind = kind
+	return span, nil
+}
+
+func (s *Store) CreateResource(name string, attributes map[string]string) *Resource {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	resource := &Resource{
 		Name:       name,
 		Attributes: attributes,
 	}
-	store.resources[name] = resource
+	s.changing()
+	s.resources[name] = resource
 	return resource
 }
 
-func UpdateResource(name, newName string, attributes map[string]string) (*Resource, error) {
-	resource, ok := store.resources[name]
+func (s *Store) UpdateResource(name, newName string, attributes map[string]string) (*Resource, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resource, ok := s.resources[name]
 	if !ok {
 		return nil, fmt.Errorf("resource %s not found", name)
 	}
 	if newName != "" {
-		if _, exists := store.resources[newName]; exists {
+		if _, exists := s.resources[newName]; exists {
 			return nil, fmt.Errorf("resource with name %s already exists", newName)
 		}
-		delete(store.resources, name)
+		s.changing()
+		delete(s.resources, name)
 		resource.Name = newName
-		store.resources[newName] = resource
+		s.resources[newName] = resource
 	}
 	if attributes != nil {
+		s.changing()
 		resource.Attributes = make(map[string]string)
 		maps.Copy(resource.Attributes, attributes)
 	}
 	return resource, nil
 }
 
-func CreateEvent(name string, attributes map[string]string) *Event {
+func (s *Store) SetResourceSemantics(name, semconvVersion string, mergeDefaults *bool) (*Resource, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resource, ok := s.resources[name]
+	if !ok {
+		return nil, fmt.Errorf("resource %s not found", name)
+	}
+	if semconvVersion != "" {
+		if !IsSemconvVersionSupported(semconvVersion) {
+			return nil, fmt.Errorf("unsupported semconv version: %s", semconvVersion)
+		}
+		s.changing()
+		resource.SemconvVersion = semconvVersion
+	}
+	if mergeDefaults != nil {
+		s.changing()
+		resource.MergeDefaults = *mergeDefaults
+	}
+	return resource, nil
+}
+
+func (s *Store) CreateEvent(name string, attributes map[string]string) *Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	event := Event{
 		Name:       name,
 		Attributes: attributes,
 	}
-	store.events[name] = &event
+	s.changing()
+	s.events[name] = &event
 	return &event
 }
 
-func UpdateEvent(name, newName string, attributes map[string]string) (*Event, error) {
-	event, ok := store.events[name]
+func (s *Store) UpdateEvent(name, newName string, attributes map[string]string) (*Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	event, ok := s.events[name]
 	if !ok {
 		return nil, fmt.Errorf("event %s not found", name)
 	}
 	if newName != "" {
-		if _, exists := store.events[newName]; exists {
+		if _, exists := s.events[newName]; exists {
 			return nil, fmt.Errorf("event with name %s already exists", newName)
 		}
-		delete(store.events, name)
+		s.changing()
+		delete(s.events, name)
 		event.Name = newName
-		store.events[newName] = event
+		s.events[newName] = event
 	}
 	if attributes != nil {
+		s.changing()
 		event.Attributes = make(map[string]string)
 		maps.Copy(event.Attributes, attributes)
 	}
 	return event, nil
 }
 
-func AddSpanToTrace(traceName, spanName string, attributes map[string]string) (*Span, error) {
-	trace, ok := store.traces[traceName]
+func (s *Store) AddSpanToTrace(traceName, spanName string, attributes map[string]string) (*Span, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	trace, ok := s.traces[traceName]
 	if !ok {
 		return nil, fmt.Errorf("trace %s not found", traceName)
 	}
@@ -214,189 +449,74 @@ func AddSpanToTrace(traceName, spanName string, attributes map[string]string) (*
 	if trace.RootSpan != nil {
 		return nil, fmt.Errorf("trace %s already has a root span", traceName)
 	}
+	s.changing()
 	trace.RootSpan = &span
-	store.spans[spanName] = &span
+	s.registerSpan(&span, traceName)
 	return &span, nil
 }
 
-func AddSpanToSpan(parentSpanName, spanName string, attributes map[string]string) (*Span, error) {
-	parentSpan, ok := store.spans[parentSpanName]
-	if !ok {
-		return nil, fmt.Errorf("parent span %s not found", parentSpanName)
+func (s *Store) AddSpanToSpan(parentSpanRef, spanName string, attributes map[string]string) (*Span, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	parentSpan, err := s.lookupSpan(parentSpanRef)
+	if err != nil {
+		return nil, err
 	}
 	span := Span{
 		Name:       spanName,
 		Attributes: attributes,
 	}
+	s.changing()
 	parentSpan.AddChild(&span)
-	store.spans[spanName] = &span
+	s.registerSpan(&span, s.traceNameOf(parentSpan))
 	return &span, nil
 }
 
-func AddLinkToSpan(from, to string, attributes map[string]string) (*Span, error) {
-	fromSpan, ok := store.spans[from]
-	if !ok {
-		return nil, fmt.Errorf("from span %s not found", from)
+func (s *Store) AddLinkToSpan(from, to string, attributes map[string]string) (*Span, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fromSpan, err := s.lookupSpan(from)
+	if err != nil {
+		return nil, err
 	}
-	toSpan, ok := store.spans[to]
-	if !ok {
-		return nil, fmt.Errorf("to span %s not found", to)
+	toSpan, err := s.lookupSpan(to)
+	if err != nil {
+		return nil, err
 	}
+	s.changing()
 	fromSpan.AddLink(toSpan, attributes)
 	return toSpan, nil
 }
 
-func AddEventToSpan(spanName, eventName string) (*Event, error) {
-	span, ok := store.spans[spanName]
-	if !ok {
-		return nil, fmt.Errorf("span %s not found", spanName)
+func (s *Store) AddEventToSpan(spanRef, eventName string) (*Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	span, err := s.lookupSpan(spanRef)
+	if err != nil {
+		return nil, err
 	}
-	event, ok := store.events[eventName]
+	event, ok := s.events[eventName]
 	if !ok {
 		return nil, fmt.Errorf("event %s not found", eventName)
 	}
+	s.changing()
 	span.AddEvent(event)
 	return event, nil
 }
 
-func SetResourceToSpan(spanName, resourceName string) (*Resource, error) {
-	span, ok := store.spans[spanName]
-	if !ok {
-		return nil, fmt.Errorf("span %s not found", spanName)
+func (s *Store) SetResourceToSpan(spanRef, resourceName string) (*Resource, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	span, err := s.lookupSpan(spanRef)
+	if err != nil {
+		return nil, err
 	}
-	resource, ok := store.resources[resourceName]
+	resource, ok := s.resources[resourceName]
 	if !ok {
 		return nil, fmt.Errorf("resource %s not found", resourceName)
 	}
+	s.changing()
 	span.Resource = resource
 	return resource, nil
 
 }
-
-func SendAllTraces() {
-	spans := make(map[string]*spanToProcess)
-	for _, traceData := range store.traces {
-		if traceData.RootSpan != nil {
-			spanCount := 0
-			processSpan(nil, traceData.RootSpan, &spanCount, 1.0, nil, spans)
-			fmt.Printf("Trace '%s' sent with %d spans.\n", traceData.Name, spanCount)
-		} else {
-			fmt.Printf("Trace '%s' has no spans.\n", traceData.Name)
-		}
-	}
-	// loop again to link spans and finish them
-	for name, span := range spans {
-		storedSpan, exists := store.spans[name]
-		if !exists {
-			fmt.Printf("Warning: Span '%s' not found in store, cannot link.\n", name)
-			span.End()
-			continue
-		}
-		if len(storedSpan.Links) > 0 {
-			for _, link := range storedSpan.Links {
-				if linkedSpan, exists := spans[link.TargetSpan.Name]; exists {
-					attrs := []attribute.KeyValue{}
-					for k, v := range link.Attributes {
-						attrs = append(attrs, attribute.String(k, v))
-					}
-					span.span.AddLink(trace.Link{
-						SpanContext: linkedSpan.span.SpanContext(),
-						Attributes:  attrs,
-					})
-				} else {
-					fmt.Printf("Warning: Linked span '%s' not found for span '%s'.\n", link.TargetSpan.Name, name)
-				}
-			}
-		}
-		span.End()
-	}
-
-	InitStore()
-
-	exporterFn := GetTracerManager().GetExporterFn()
-	processorFn := GetTracerManager().GetSpanProcessorFn()
-
-	InitTracerManager(exporterFn, processorFn)
-}
-
-type spanToProcess struct {
-	span    trace.Span
-	endTime time.Time
-}
-
-func (s *spanToProcess) End() {
-	s.span.End(trace.WithTimestamp(s.endTime))
-}
-
-// processSpan handles span creation with duration distribution:
-// - Root span takes 1 second (current time - 1s to current time)
-// - Each child span takes 90% of parent's duration
-// - Child spans are centered within their parent's timeframe
-func processSpan(parentCtx context.Context, s *Span, spanCount *int, parentDuration float64, parentStartTime *time.Time, spans map[string]*spanToProcess) {
-	var tracer trace.Tracer
-	if s.Resource != nil {
-		tm := GetTracerManager()
-		resourceName := s.Resource.Name
-
-		if t := tm.GetTracerForResource(resourceName); t != nil {
-			tracer = t
-		} else {
-			if t, err := tm.CreateTracerForResource(resourceName, s.Resource); err != nil {
-				fmt.Printf("Warning: Failed to create tracer for resource '%s': %v\n", resourceName, err)
-				tracer = tm.GetDefaultTracer()
-			} else {
-				tracer = t
-			}
-		}
-	} else {
-		// Use default tracer when no resource is attached to span
-		tracer = GetTracerManager().GetDefaultTracer()
-	}
-	attrs := []attribute.KeyValue{}
-	for k, v := range s.Attributes {
-		attrs = append(attrs, attribute.String(k, v))
-	}
-
-	var (
-		spanCtx   context.Context
-		span      trace.Span
-		startTime time.Time
-		endTime   time.Time
-	)
-
-	if parentCtx == nil {
-		now := time.Now()
-		startTime = now.Add(-1 * time.Second)
-		endTime = startTime.Add(time.Duration(parentDuration * float64(time.Second)))
-
-		spanCtx, span = tracer.Start(context.Background(), s.Name, trace.WithAttributes(attrs...), trace.WithTimestamp(startTime))
-	} else {
-		childDuration := parentDuration * 0.9
-
-		timePadding := time.Duration((parentDuration - childDuration) / 2 * float64(time.Second))
-		startTime = parentStartTime.Add(timePadding)
-		endTime = startTime.Add(time.Duration(childDuration * float64(time.Second)))
-
-		spanCtx, span = tracer.Start(parentCtx, s.Name, trace.WithAttributes(attrs...), trace.WithTimestamp(startTime))
-	}
-
-	for _, event := range s.Events {
-		eventAttrs := []attribute.KeyValue{}
-		for k, v := range event.Attributes {
-			eventAttrs = append(eventAttrs, attribute.String(k, v))
-		}
-		span.AddEvent(event.Name, trace.WithAttributes(eventAttrs...))
-	}
-
-	spans[s.Name] = &spanToProcess{
-		span:    span,
-		endTime: endTime,
-	}
-
-	*spanCount++
-
-	for _, childSpan := range s.Children {
-		childDuration := parentDuration * 0.9
-		processSpan(spanCtx, childSpan, spanCount, childDuration, &startTime, spans)
-	}
-}
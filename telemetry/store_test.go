@@ -2,12 +2,15 @@ package telemetry
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
 func TestSpanAddChild(t *testing.T) {
@@ -59,7 +62,7 @@ func TestStore(t *testing.T) {
 
 			spans = GetSpans()
 			assert.Len(t, spans, 1, "Expected one span after creation")
-			assert.Equal(t, span, spans[spanName], "Expected created span to be retrieved correctly")
+			assert.Equal(t, span, spans[traceName+"/"+spanName], "Expected created span to be retrieved correctly")
 			assert.Equal(t, trace.RootSpan, span, "Expected span to be added to trace root span")
 			assert.True(t, IsSpanExists(spanName), "Expected span to exist after creation")
 		})
@@ -87,7 +90,7 @@ func TestStore(t *testing.T) {
 
 			spans = GetSpans()
 			assert.Len(t, spans, 2, "Expected two spans after adding child span")
-			assert.Equal(t, childSpan, spans[childSpanName], "Expected created child span to be retrieved correctly")
+			assert.Equal(t, childSpan, spans[traceName+"/"+childSpanName], "Expected created child span to be retrieved correctly")
 			assert.Contains(t, parentSpan.Children, childSpan, "Expected child span to be added to parent span's children")
 		})
 
@@ -280,6 +283,176 @@ func TestSendAllTraces(t *testing.T) {
 	assert.Empty(t, GetResources(), "Store should be reset after sending resources")
 }
 
+func TestSendAllTraces_InheritResource(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+
+	exporterFn := func() (trace.SpanExporter, error) {
+		return tracetest.NewNoopExporter(), nil
+	}
+	processorFn := func() (trace.SpanProcessor, error) {
+		return recorder, nil
+	}
+
+	InitTracerManager(exporterFn, processorFn)
+	t.Cleanup(func() {
+		if err := GetTracerManager().Shutdown(context.Background()); err != nil {
+			t.Fatalf("Failed to shutdown tracer manager: %v", err)
+		}
+		SetInheritResource(false)
+	})
+
+	InitStore()
+	SetInheritResource(true)
+
+	CreateResource("checkout-svc", map[string]string{})
+	CreateResource("db", map[string]string{})
+
+	CreateTrace("inherited")
+	AddSpanToTrace("inherited", "root", map[string]string{})
+	SetResourceToSpan("root", "checkout-svc")
+	AddSpanToSpan("root", "child", map[string]string{})
+	AddSpanToSpan("child", "query", map[string]string{})
+	SetResourceToSpan("query", "db")
+	AddSpanToSpan("query", "grandchild", map[string]string{})
+
+	CreateTrace("not_inherited")
+	AddSpanToTrace("not_inherited", "other_root", map[string]string{})
+	SetResourceToSpan("other_root", "checkout-svc")
+	AddSpanToSpan("other_root", "other_child", map[string]string{})
+	inherit := false
+	SetTraceInheritResource("not_inherited", &inherit)
+
+	SendAllTraces()
+
+	serviceNames := make(map[string]string)
+	for _, span := range recorder.Ended() {
+		v, _ := span.Resource().Set().Value("service.name")
+		serviceNames[span.Name()] = v.AsString()
+	}
+
+	assert.Equal(t, "checkout-svc", serviceNames["root"])
+	assert.Equal(t, "checkout-svc", serviceNames["child"], "Child should inherit the resource of the root span")
+	assert.Equal(t, "db", serviceNames["query"], "Explicit resource should override the inherited one")
+	assert.Equal(t, "db", serviceNames["grandchild"], "Grandchild should inherit the nearest resource")
+	assert.Equal(t, "checkout-svc", serviceNames["other_root"])
+	assert.NotEqual(t, "checkout-svc", serviceNames["other_child"], "Trace option should override the global option")
+}
+
+func TestSendAllTraces_DuplicateSpanNames(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+
+	exporterFn := func() (trace.SpanExporter, error) {
+		return tracetest.NewNoopExporter(), nil
+	}
+	processorFn := func() (trace.SpanProcessor, error) {
+		return recorder, nil
+	}
+
+	InitTracerManager(exporterFn, processorFn)
+	t.Cleanup(func() {
+		if err := GetTracerManager().Shutdown(context.Background()); err != nil {
+			t.Fatalf("Failed to shutdown tracer manager: %v", err)
+		}
+	})
+
+	InitStore()
+	CreateTrace("checkout")
+	AddSpanToTrace("checkout", "GET /users", map[string]string{"trace": "checkout"})
+	CreateTrace("search")
+	AddSpanToTrace("search", "GET /users", map[string]string{"trace": "search"})
+	AddLinkToSpan("search/GET__users", "checkout/GET__users", map[string]string{})
+
+	SendAllTraces()
+
+	ended := recorder.Ended()
+	assert.Len(t, ended, 2, "Spans with the same name should not overwrite each other")
+
+	var checkout, search trace.ReadOnlySpan
+	for _, span := range ended {
+		assert.Equal(t, "GET /users", span.Name())
+		switch getAttributeValue(span.Attributes(), "trace") {
+		case "checkout":
+			checkout = span
+		case "search":
+			search = span
+		}
+	}
+	if assert.NotNil(t, checkout) && assert.NotNil(t, search) {
+		assert.Len(t, search.Links(), 1)
+		assert.Equal(t, checkout.SpanContext().SpanID(), search.Links()[0].SpanContext.SpanID(), "Link should point at the span in the other trace")
+	}
+}
+
+func TestStoreSend_Parallel(t *testing.T) {
+	for _, name := range []string{"checkout", "search"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := tracetest.NewSpanRecorder()
+			tm, err := NewTracerManager(func() (trace.SpanExporter, error) {
+				return tracetest.NewNoopExporter(), nil
+			}, func() (trace.SpanProcessor, error) {
+				return recorder, nil
+			})
+			assert.NoError(t, err)
+			t.Cleanup(func() {
+				assert.NoError(t, tm.Shutdown(context.Background()))
+			})
+
+			s := NewStore()
+			s.CreateTrace(name)
+			s.CreateResource(name+"-service", map[string]string{})
+			_, err = s.AddSpanToTrace(name, "root", map[string]string{})
+			assert.NoError(t, err)
+
+			var wg sync.WaitGroup
+			for i := range 10 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					child, err := s.AddSpanToSpan(name+"/root", fmt.Sprintf("child-%d", i), map[string]string{})
+					assert.NoError(t, err)
+					_, err = s.SetResourceToSpan(child.Handle, name+"-service")
+					assert.NoError(t, err)
+				}()
+			}
+			wg.Wait()
+
+			result, err := s.Send(context.Background(), tm)
+			assert.NoError(t, err)
+			if assert.Len(t, result.Traces, 1) {
+				assert.Equal(t, name, result.Traces[0].Name)
+				assert.Equal(t, 11, result.Traces[0].Spans)
+				assert.True(t, result.Traces[0].TraceID.IsValid())
+			}
+			assert.Len(t, result.SpanContexts, 11)
+			assert.Empty(t, result.Warnings)
+			assert.Len(t, recorder.Ended(), 11)
+			assert.Len(t, s.GetSpans(), 11, "Store should not be reset after sending")
+		})
+	}
+}
+
+func TestStoreSend_Canceled(t *testing.T) {
+	tm, err := NewTracerManager(func() (trace.SpanExporter, error) {
+		return tracetest.NewNoopExporter(), nil
+	}, nil)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, tm.Shutdown(context.Background()))
+	})
+
+	s := NewStore()
+	s.CreateTrace("checkout")
+	_, err = s.AddSpanToTrace("checkout", "root", map[string]string{})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = s.Send(ctx, tm)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
 func getAttributeValue(attributes []attribute.KeyValue, key string) string {
 	for _, attr := range attributes {
 		if string(attr.Key) == key {
@@ -288,3 +461,94 @@ func getAttributeValue(attributes []attribute.KeyValue, key string) string {
 	}
 	return ""
 }
+
+func TestStoreAddTrace(t *testing.T) {
+	s := NewStore()
+	cart := s.CreateResource("cart", map[string]string{})
+	root := &Span{Name: "GET /cart", Resource: cart}
+	root.AddChild(&Span{Name: "db"})
+	root.AddChild(&Span{Name: "db"})
+
+	trace, err := s.AddTrace("t", root)
+	assert.NoError(t, err)
+	assert.Same(t, root, trace.RootSpan)
+	assert.Equal(t, "t/GET__cart", root.Handle)
+	assert.Equal(t, "t/db", root.Children[0].Handle)
+	assert.Equal(t, "t/db-2", root.Children[1].Handle)
+	assert.Len(t, s.GetSpans(), 3)
+
+	_, err = s.AddTrace("t", &Span{Name: "root"})
+	assert.EqualError(t, err, "trace with name t already exists")
+
+	_, err = s.AddTrace("other", &Span{Name: "root", Resource: &Resource{Name: "cart"}})
+	assert.EqualError(t, err, "resource cart of span root is not in the store")
+	assert.False(t, s.IsTraceExists("other"))
+}
+
+func TestParseSpanKind(t *testing.T) {
+	kind, err := ParseSpanKind("server")
+	assert.NoError(t, err)
+	assert.Equal(t, oteltrace.SpanKindServer, kind)
+
+	_, err = ParseSpanKind("unspecified")
+	assert.EqualError(t, err, "unknown span kind unspecified (internal, server, client, producer or consumer)")
+}
+
+func TestStoreSend_Kind(t *testing.T) {
+	s := NewStore()
+	s.CreateTrace("t")
+	_, err := s.AddSpanToTrace("t", "root", map[string]string{})
+	assert.NoError(t, err)
+	_, err = s.AddSpanToSpan("root", "child", map[string]string{})
+	assert.NoError(t, err)
+	_, err = s.SetSpanKind("root", oteltrace.SpanKindServer)
+	assert.NoError(t, err)
+
+	_, spans := sendRecorded(t, s)
+	assert.Equal(t, oteltrace.SpanKindServer, spans["root"].SpanKind())
+	assert.Equal(t, oteltrace.SpanKindInternal, spans["child"].SpanKind(), "Unspecified kind should be sent as internal")
+}
+
+func TestStoreSend_ReusedTracerManager(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tm, err := NewTracerManager(func() (trace.SpanExporter, error) {
+		return tracetest.NewNoopExporter(), nil
+	}, func() (trace.SpanProcessor, error) {
+		return recorder, nil
+	})
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, tm.Shutdown(context.Background()))
+	})
+
+	send := func(s *Store) string {
+		t.Helper()
+		recorder.Reset()
+		_, err := s.Send(context.Background(), tm)
+		assert.NoError(t, err)
+		spans := recorder.Ended()
+		if !assert.Len(t, spans, 1) {
+			return ""
+		}
+		v, _ := spans[0].Resource().Set().Value("service.version")
+		return v.AsString()
+	}
+	newStore := func(version string) *Store {
+		s := NewStore()
+		s.CreateResource("cart", map[string]string{"service.version": version})
+		s.CreateTrace("t")
+		_, err := s.AddSpanToTrace("t", "root", map[string]string{})
+		assert.NoError(t, err)
+		_, err = s.SetResourceToSpan("t/root", "cart")
+		assert.NoError(t, err)
+		return s
+	}
+
+	s := newStore("1")
+	assert.Equal(t, "1", send(s))
+	_, err = s.PatchResourceAttributes("cart", map[string]string{"service.version": "2"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", send(s), "Changed resource should not be exported with the old attributes")
+	assert.Equal(t, "3", send(newStore("3")), "Resource of another store with the same name should not share the provider")
+	assert.Equal(t, "1", send(newStore("1")))
+}
@@ -0,0 +1,48 @@
+package telemetry
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Template is a reusable subtree of spans written as commands, which refer to the parameters
+// as ${name}. The commands are expanded by the executor when the template is instantiated.
+// Like the options, templates are kept when the store is reset.
+type Template struct {
+	Name     string
+	Params   []string
+	Commands []string
+}
+
+// DefineTemplate adds the template, replacing the template with the same name
+func (s *Store) DefineTemplate(t Template) error {
+	if t.Name == "" {
+		return fmt.Errorf("template name must not be empty")
+	}
+	for i, p := range t.Params {
+		if slices.Contains(t.Params[:i], p) {
+			return fmt.Errorf("parameter %s of template %s is specified more than once", p, t.Name)
+		}
+	}
+	t.Params = slices.Clone(t.Params)
+	t.Commands = slices.Clone(t.Commands)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.templates == nil {
+		s.templates = make(map[string]*Template)
+	}
+	s.templates[t.Name] = &t
+	return nil
+}
+
+// GetTemplates returns a copy of the templates keyed by name
+func (s *Store) GetTemplates() map[string]*Template {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	templates := make(map[string]*Template, len(s.templates))
+	for name, t := range s.templates {
+		templates[name] = t
+	}
+	return templates
+}
@@ -0,0 +1,45 @@
+package telemetry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreDefineTemplate(t *testing.T) {
+	s := NewStore()
+	params := []string{"svc"}
+	assert.NoError(t, s.DefineTemplate(Template{Name: "handler", Params: params, Commands: []string{"create span a with parent ${parent}"}}))
+	params[0] = "changed"
+	assert.Equal(t, []string{"svc"}, s.GetTemplates()["handler"].Params, "Template should not share the parameters")
+
+	assert.NoError(t, s.DefineTemplate(Template{Name: "handler", Commands: []string{"create span b with parent ${parent}"}}))
+	assert.Equal(t, []string{"create span b with parent ${parent}"}, s.GetTemplates()["handler"].Commands, "Template should be replaced")
+
+	assert.EqualError(t, s.DefineTemplate(Template{}), "template name must not be empty")
+	assert.EqualError(t, s.DefineTemplate(Template{Name: "x", Params: []string{"a", "a"}}), "parameter a of template x is specified more than once")
+
+	s.Reset()
+	assert.Contains(t, s.GetTemplates(), "handler", "Templates should be kept on reset")
+}
+
+func TestStoreScenario_Templates(t *testing.T) {
+	s := NewStore()
+	assert.NoError(t, s.DefineTemplate(Template{Name: "b", Commands: []string{"create span b in trace t"}}))
+	assert.NoError(t, s.DefineTemplate(Template{Name: "a", Params: []string{"svc"}, Commands: []string{"create span a in trace ${svc}"}}))
+
+	sc := s.Scenario()
+	assert.Equal(t, []ScenarioTemplate{
+		{Name: "a", Params: []string{"svc"}, Commands: []string{"create span a in trace ${svc}"}},
+		{Name: "b", Commands: []string{"create span b in trace t"}},
+	}, sc.Templates)
+
+	loaded := NewStore()
+	assert.NoError(t, loaded.DefineTemplate(Template{Name: "other"}))
+	assert.NoError(t, loaded.LoadScenario(sc))
+	assert.Len(t, loaded.GetTemplates(), 3, "Templates of the scenario should be added")
+	assert.Equal(t, s.GetTemplates()["a"], loaded.GetTemplates()["a"])
+
+	sc.Templates = append(sc.Templates, ScenarioTemplate{Name: "a"})
+	assert.EqualError(t, NewStore().LoadScenario(sc), "template a is defined more than once")
+}
@@ -3,18 +3,23 @@ package telemetry
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"go.opentelemetry.io/otel/attribute"
 	sdkresource "go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
 	"go.opentelemetry.io/otel/trace"
 )
 
-// TracerManager manages multiple tracers for different resources
+// TracerManager manages multiple tracers for different resources.
+// Its methods are safe for concurrent use.
 type TracerManager struct {
-	// Maps resource name to tracer provider
-	providers map[string]*sdktrace.TracerProvider
+	mu sync.Mutex
+	// Maps the name and the built attributes of a resource to tracer provider, so that
+	// a resource which is changed or replaced by another one of the same name gets a new one
+	providers map[providerKey]*sdktrace.TracerProvider
+	// Maps resource name to the tracer provider created last for it
+	latest map[string]*sdktrace.TracerProvider
 	// Default tracer provider
 	defaultProvider *sdktrace.TracerProvider
 	// Function to create a new exporter
@@ -25,19 +30,26 @@ type TracerManager struct {
 	processor sdktrace.SpanProcessor
 }
 
-// Global instance of the tracer manager
-var tracerManager *TracerManager
+// providerKey identifies the tracer provider of a resource
+type providerKey struct {
+	name      string
+	schemaURL string
+	attrs     attribute.Distinct
+}
 
-// InitTracerManager initializes the global tracer manager
-func InitTracerManager(exporterFn func() (sdktrace.SpanExporter, error), processorFn func() (sdktrace.SpanProcessor, error)) error {
-	if tracerManager != nil {
-		if err := tracerManager.Shutdown(context.Background()); err != nil {
-			fmt.Printf("Error shutting down tracer manager: %v\n", err)
-		}
-	}
+var (
+	// tracerManagerMu guards tracerManager
+	tracerManagerMu sync.RWMutex
+	// Global instance of the tracer manager
+	tracerManager *TracerManager
+)
+
+// NewTracerManager returns a tracer manager whose tracer providers export spans
+// with the exporters created by exporterFn
+func NewTracerManager(exporterFn func() (sdktrace.SpanExporter, error), processorFn func() (sdktrace.SpanProcessor, error)) (*TracerManager, error) {
 	exporter, err := exporterFn()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defaultProvider := sdktrace.NewTracerProvider(
 		sdktrace.WithSyncer(exporter),
@@ -47,44 +59,65 @@ func InitTracerManager(exporterFn func() (sdktrace.SpanExporter, error), process
 	if processorFn != nil {
 		processor, err = processorFn()
 		if err != nil {
-			return err
+			return nil, err
 		}
 		defaultProvider.RegisterSpanProcessor(processor)
 	}
-	tracerManager = &TracerManager{
-		providers:       make(map[string]*sdktrace.TracerProvider),
+	return &TracerManager{
+		providers:       make(map[providerKey]*sdktrace.TracerProvider),
+		latest:          make(map[string]*sdktrace.TracerProvider),
 		defaultProvider: defaultProvider,
 		exporterFn:      exporterFn,
 		processorFn:     processorFn,
 		processor:       processor,
+	}, nil
+}
+
+// InitTracerManager initializes the global tracer manager
+func InitTracerManager(exporterFn func() (sdktrace.SpanExporter, error), processorFn func() (sdktrace.SpanProcessor, error)) error {
+	tracerManagerMu.Lock()
+	defer tracerManagerMu.Unlock()
+	return initTracerManager(exporterFn, processorFn)
+}
+
+// initTracerManager replaces the global tracer manager. It must be called with tracerManagerMu held.
+func initTracerManager(exporterFn func() (sdktrace.SpanExporter, error), processorFn func() (sdktrace.SpanProcessor, error)) error {
+	if tracerManager != nil {
+		if err := tracerManager.Shutdown(context.Background()); err != nil {
+			fmt.Printf("Error shutting down tracer manager: %v\n", err)
+		}
+	}
+	tm, err := NewTracerManager(exporterFn, processorFn)
+	if err != nil {
+		return err
 	}
+	tracerManager = tm
 	return nil
 }
 
 // GetTracerManager returns the global tracer manager
 func GetTracerManager() *TracerManager {
+	tracerManagerMu.RLock()
+	defer tracerManagerMu.RUnlock()
 	return tracerManager
 }
 
-// CreateTracerForResource creates a new tracer provider for a resource
-func (tm *TracerManager) CreateTracerForResource(resourceName string, res *Resource) (trace.Tracer, error) {
-	if provider, exists := tm.providers[resourceName]; exists {
-		return provider.Tracer("otelgen"), nil
-	}
-
-	resAttrs := []attribute.KeyValue{
-		semconv.ServiceNameKey.String(res.Name),
+// CreateTracerForResource returns the tracer for a resource, creating its tracer provider
+// if it doesn't exist yet for the resource name and the attributes the resource is built with
+func (tm *TracerManager) CreateTracerForResource(ctx context.Context, resourceName string, res *Resource) (trace.Tracer, error) {
+	r, err := res.Build(ctx)
+	if err != nil {
+		return nil, err
 	}
+	key := providerKey{name: resourceName, schemaURL: r.SchemaURL(), attrs: r.Equivalent()}
 
-	for k, v := range res.Attributes {
-		resAttrs = append(resAttrs, attribute.String(k, v))
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if provider, exists := tm.providers[key]; exists {
+		tm.latest[resourceName] = provider
+		return provider.Tracer("otelgen"), nil
 	}
 
-	r := sdkresource.NewWithAttributes(
-		semconv.SchemaURL,
-		resAttrs...,
-	)
-
 	exporter, err := tm.exporterFn()
 	if err != nil {
 		return nil, err
@@ -103,14 +136,17 @@ func (tm *TracerManager) CreateTracerForResource(resourceName string, res *Resou
 	}
 
 	tm.processor = processor
-	tm.providers[resourceName] = tp
+	tm.providers[key] = tp
+	tm.latest[resourceName] = tp
 
 	return tp.Tracer("otelgen"), nil
 }
 
-// GetTracerForResource returns a tracer for the given resource
+// GetTracerForResource returns a tracer of the provider created last for the given resource
 func (tm *TracerManager) GetTracerForResource(resourceName string) trace.Tracer {
-	if provider, exists := tm.providers[resourceName]; exists {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if provider, exists := tm.latest[resourceName]; exists {
 		return provider.Tracer("otelgen")
 	}
 
@@ -134,11 +170,15 @@ func (tm *TracerManager) GetSpanProcessorFn() func() (sdktrace.SpanProcessor, er
 
 // GetSpanProcessor returns the current span processor. This is only used for testing
 func (tm *TracerManager) GetSpanProcessor() sdktrace.SpanProcessor {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
 	return tm.processor
 }
 
 // Shutdown closes all tracer providers
 func (tm *TracerManager) Shutdown(ctx context.Context) error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
 	var lastErr error
 	for _, provider := range tm.providers {
 		if err := provider.Shutdown(ctx); err != nil {
@@ -0,0 +1,191 @@
+package telemetry
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Attribute values can contain generator expressions which are evaluated on each send, e.g.
+//   - {{uuid}} a random UUID
+//   - {{seq}} or {{seq 1000}} a number incremented on each send, which starts at 1 or the number
+//   - {{pick /a,/b,/c}} one of the comma separated values
+//   - {{rand 1 500}} a random number between the minimum and the maximum
+//   - {{email}}, {{ipv4}} and {{ipv6}} a random email address or IP address
+//
+// The expressions are kept in the store as they are written.
+var valueExprPattern = regexp.MustCompile(`\{\{([^}]*)\}\}`)
+
+// HasValueGenerator reports whether the value contains generator expressions
+func HasValueGenerator(value string) bool {
+	return valueExprPattern.MatchString(value)
+}
+
+// ValidateValue returns an error if a generator expression in the value is invalid
+func ValidateValue(value string) error {
+	g := &valueGenerator{rand: NewRand(0), sequences: &sequences{}}
+	_, err := g.evaluate("", value)
+	return err
+}
+
+// sequences are the counters of {{seq}} by attribute key. Like the options, they are kept
+// when the store is reset so that the values don't repeat across sends.
+type sequences struct {
+	mu   sync.Mutex
+	next map[string]int64
+}
+
+func (s *sequences) nextOf(key string, start int64) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.next == nil {
+		s.next = make(map[string]int64)
+	}
+	n, ok := s.next[key]
+	if !ok {
+		n = start
+	}
+	s.next[key] = n + 1
+	return n
+}
+
+// valueGenerator evaluates the generator expressions in attribute values on a send
+type valueGenerator struct {
+	rand      *rand.Rand
+	sequences *sequences
+}
+
+// evaluate replaces the generator expressions in the value of the attribute with the generated values
+func (g *valueGenerator) evaluate(key, value string) (string, error) {
+	var err error
+	result := valueExprPattern.ReplaceAllStringFunc(value, func(expr string) string {
+		if err != nil {
+			return expr
+		}
+		var generated string
+		generated, err = g.generate(key, strings.TrimSpace(valueExprPattern.FindStringSubmatch(expr)[1]))
+		return generated
+	})
+	if err != nil {
+		return value, err
+	}
+	return result, nil
+}
+
+func (g *valueGenerator) generate(key, expr string) (string, error) {
+	name, args, _ := strings.Cut(expr, " ")
+	args = strings.TrimSpace(args)
+	fields := strings.Fields(args)
+
+	switch name {
+	case "uuid":
+		if args != "" {
+			return "", fmt.Errorf("uuid takes no arguments")
+		}
+		return g.uuid(), nil
+	case "seq":
+		start := int64(1)
+		if len(fields) > 1 {
+			return "", fmt.Errorf("seq takes at most one start number")
+		}
+		if len(fields) == 1 {
+			n, err := strconv.ParseInt(fields[0], 10, 64)
+			if err != nil {
+				return "", fmt.Errorf("invalid start %s of seq", fields[0])
+			}
+			start = n
+		}
+		return strconv.FormatInt(g.sequences.nextOf(key, start), 10), nil
+	case "pick":
+		if args == "" {
+			return "", fmt.Errorf("pick takes comma separated values")
+		}
+		values := strings.Split(args, ",")
+		return strings.TrimSpace(values[g.rand.IntN(len(values))]), nil
+	case "rand":
+		if len(fields) != 2 {
+			return "", fmt.Errorf("rand takes the minimum and the maximum")
+		}
+		return g.randomNumber(fields[0], fields[1])
+	case "email":
+		if args != "" {
+			return "", fmt.Errorf("email takes no arguments")
+		}
+		return g.email(), nil
+	case "ipv4":
+		if args != "" {
+			return "", fmt.Errorf("ipv4 takes no arguments")
+		}
+		// skip 0 and the multicast and reserved ranges at the top
+		return netip.AddrFrom4([4]byte{byte(1 + g.rand.IntN(223)), g.byte(), g.byte(), g.byte()}).String(), nil
+	case "ipv6":
+		if args != "" {
+			return "", fmt.Errorf("ipv6 takes no arguments")
+		}
+		var b [16]byte
+		b[0], b[1] = 0x20, 0x01
+		for i := 2; i < len(b); i++ {
+			b[i] = g.byte()
+		}
+		return netip.AddrFrom16(b).String(), nil
+	}
+	return "", fmt.Errorf("unknown generator %s (uuid, seq, pick, rand, email, ipv4 or ipv6)", name)
+}
+
+// randomNumber returns an integer between the minimum and the maximum, or a number with
+// 2 decimal places if either of them has a decimal point
+func (g *valueGenerator) randomNumber(minArg, maxArg string) (string, error) {
+	if !strings.Contains(minArg+maxArg, ".") {
+		min, errMin := strconv.ParseInt(minArg, 10, 64)
+		max, errMax := strconv.ParseInt(maxArg, 10, 64)
+		if errMin == nil && errMax == nil {
+			if min > max {
+				return "", fmt.Errorf("minimum %d of rand is greater than maximum %d", min, max)
+			}
+			return strconv.FormatInt(min+g.rand.Int64N(max-min+1), 10), nil
+		}
+	}
+	min, errMin := strconv.ParseFloat(minArg, 64)
+	max, errMax := strconv.ParseFloat(maxArg, 64)
+	if errMin != nil || errMax != nil {
+		return "", fmt.Errorf("invalid range %s %s of rand", minArg, maxArg)
+	}
+	if min > max {
+		return "", fmt.Errorf("minimum %s of rand is greater than maximum %s", minArg, maxArg)
+	}
+	return strconv.FormatFloat(min+g.rand.Float64()*(max-min), 'f', 2, 64), nil
+}
+
+// uuid returns a random version 4 UUID
+func (g *valueGenerator) uuid() string {
+	var b [16]byte
+	for i := range b {
+		b[i] = g.byte()
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+var (
+	firstNames  = []string{"alice", "bob", "carol", "dave", "erin", "frank", "grace", "heidi", "ivan", "judy", "mallory", "oscar", "peggy", "trent", "victor", "wendy"}
+	lastNames   = []string{"smith", "johnson", "williams", "brown", "jones", "garcia", "miller", "davis", "martin", "lee", "walker", "young"}
+	mailDomains = []string{"example.com", "example.net", "example.org"}
+)
+
+func (g *valueGenerator) email() string {
+	return fmt.Sprintf("%s.%s%d@%s",
+		firstNames[g.rand.IntN(len(firstNames))],
+		lastNames[g.rand.IntN(len(lastNames))],
+		g.rand.IntN(100),
+		mailDomains[g.rand.IntN(len(mailDomains))],
+	)
+}
+
+func (g *valueGenerator) byte() byte {
+	return byte(g.rand.UintN(256))
+}
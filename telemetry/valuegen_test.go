@@ -0,0 +1,144 @@
+package telemetry
+
+import (
+	"context"
+	"net/netip"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestValueGeneratorEvaluate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "plain", want: `^plain$`},
+		{value: "{{uuid}}", want: `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		{value: "{{pick /a,/b,/c}}", want: `^/[abc]$`},
+		{value: "{{ pick x, y }}", want: `^[xy]$`},
+		{value: "{{rand 1 500}}", want: `^\d{1,3}$`},
+		{value: "{{rand 0.5 9.99}}", want: `^\d\.\d{2}$`},
+		{value: "{{email}}", want: `^[a-z]+\.[a-z]+\d{1,2}@example\.(com|net|org)$`},
+		{value: "user-{{seq}}-{{pick a}}", want: `^user-1-a$`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			g := &valueGenerator{rand: NewRand(42), sequences: &sequences{}}
+			got, err := g.evaluate("key", tt.value)
+			assert.NoError(t, err)
+			assert.Regexp(t, regexp.MustCompile(tt.want), got)
+
+			again, err := (&valueGenerator{rand: NewRand(42), sequences: &sequences{}}).evaluate("key", tt.value)
+			assert.NoError(t, err)
+			assert.Equal(t, got, again, "Same seed should give the same value")
+		})
+	}
+
+	t.Run("IP addresses", func(t *testing.T) {
+		g := &valueGenerator{rand: NewRand(42), sequences: &sequences{}}
+		for _, expr := range []string{"{{ipv4}}", "{{ipv6}}"} {
+			got, err := g.evaluate("ip", expr)
+			assert.NoError(t, err)
+			addr, err := netip.ParseAddr(got)
+			assert.NoError(t, err)
+			assert.Equal(t, expr == "{{ipv4}}", addr.Is4())
+		}
+	})
+}
+
+func TestValueGeneratorEvaluate_Seq(t *testing.T) {
+	seqs := &sequences{}
+	g := &valueGenerator{rand: NewRand(1), sequences: seqs}
+
+	for _, want := range []string{"1", "2", "3"} {
+		got, err := g.evaluate("order.id", "{{seq}}")
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	got, err := g.evaluate("invoice.id", "{{seq 1000}}")
+	assert.NoError(t, err)
+	assert.Equal(t, "1000", got, "Each key should have its own sequence")
+
+	// another send keeps the sequences
+	got, err = (&valueGenerator{rand: NewRand(2), sequences: seqs}).evaluate("order.id", "{{seq}}")
+	assert.NoError(t, err)
+	assert.Equal(t, "4", got)
+}
+
+func TestValidateValue(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr string
+	}{
+		{value: "{{uuid}} and {{seq 10}}"},
+		{value: "{{nope}}", wantErr: "unknown generator nope (uuid, seq, pick, rand, email, ipv4 or ipv6)"},
+		{value: "{{uuid 4}}", wantErr: "uuid takes no arguments"},
+		{value: "{{seq x}}", wantErr: "invalid start x of seq"},
+		{value: "{{pick}}", wantErr: "pick takes comma separated values"},
+		{value: "{{rand 1}}", wantErr: "rand takes the minimum and the maximum"},
+		{value: "{{rand 500 1}}", wantErr: "minimum 500 of rand is greater than maximum 1"},
+		{value: "{{rand a b}}", wantErr: "invalid range a b of rand"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			err := ValidateValue(tt.value)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestStoreSend_ValueGenerators(t *testing.T) {
+	s := NewStore()
+	s.CreateTrace("t")
+	_, err := s.AddSpanToTrace("t", "root", map[string]string{
+		"user.id":  "{{uuid}}",
+		"order.id": "{{seq}}",
+		"route":    "/cart",
+		"broken":   "{{nope}}",
+	})
+	assert.NoError(t, err)
+
+	send := func(seed int64) (*SendResult, map[string]string) {
+		recorder := tracetest.NewSpanRecorder()
+		tm, err := NewTracerManager(func() (trace.SpanExporter, error) {
+			return tracetest.NewNoopExporter(), nil
+		}, func() (trace.SpanProcessor, error) {
+			return recorder, nil
+		})
+		assert.NoError(t, err)
+		t.Cleanup(func() {
+			assert.NoError(t, tm.Shutdown(context.Background()))
+		})
+		result, err := s.Send(context.Background(), tm, WithSeed(seed))
+		assert.NoError(t, err)
+		attrs := make(map[string]string)
+		for _, kv := range recorder.Ended()[0].Attributes() {
+			attrs[string(kv.Key)] = kv.Value.AsString()
+		}
+		return result, attrs
+	}
+
+	result, first := send(42)
+	assert.True(t, result.Sampled)
+	assert.Equal(t, []string{"Invalid value of attribute 'broken' of span 't/root' is sent as it is: unknown generator nope (uuid, seq, pick, rand, email, ipv4 or ipv6)"}, result.Warnings)
+	assert.Equal(t, "1", first["order.id"])
+	assert.Equal(t, "/cart", first["route"])
+	assert.Equal(t, "{{nope}}", first["broken"])
+	assert.Len(t, first["user.id"], 36)
+
+	_, second := send(42)
+	assert.Equal(t, "2", second["order.id"], "Sequence should continue on the next send")
+	assert.Equal(t, first["user.id"], second["user.id"], "Same seed should give the same value")
+
+	assert.Equal(t, "{{uuid}}", s.GetSpans()["t/root"].Attributes["user.id"], "Store should keep the expression")
+}
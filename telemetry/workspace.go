@@ -0,0 +1,139 @@
+package telemetry
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DefaultWorkspace is the workspace which the store starts with
+const DefaultWorkspace = "default"
+
+// workspace is the state and the undo history of a workspace while another one is current
+type workspace struct {
+	state   *state
+	history history
+}
+
+// WorkspaceInfo describes a workspace for listing
+type WorkspaceInfo struct {
+	Name    string
+	Current bool
+	Traces  int
+	Spans   int
+}
+
+// Workspace returns the name of the current workspace
+func (s *Store) Workspace() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.workspace
+}
+
+// Workspaces returns the workspaces sorted by name
+func (s *Store) Workspaces() []WorkspaceInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	infos := []WorkspaceInfo{{Name: s.workspace, Current: true, Traces: len(s.traces), Spans: len(s.spans)}}
+	for name, ws := range s.workspaces {
+		infos = append(infos, WorkspaceInfo{Name: name, Traces: len(ws.state.traces), Spans: len(ws.state.spans)})
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+	return infos
+}
+
+// IsWorkspaceExists reports whether the workspace exists
+func (s *Store) IsWorkspaceExists(name string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.isWorkspaceExists(name)
+}
+
+func (s *Store) isWorkspaceExists(name string) bool {
+	_, exists := s.workspaces[name]
+	return exists || name == s.workspace
+}
+
+// NewWorkspace creates an empty workspace and switches to it
+func (s *Store) NewWorkspace(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if name == "" {
+		return fmt.Errorf("workspace name must not be empty")
+	}
+	if s.isWorkspaceExists(name) {
+		return fmt.Errorf("workspace '%s' already exists", name)
+	}
+	s.switchWorkspace(name, &workspace{state: newState()})
+	return nil
+}
+
+// SwitchWorkspace makes the workspace current. The traces, spans, resources, events and
+// the undo history of the previous workspace are kept until it is switched back to.
+func (s *Store) SwitchWorkspace(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if name == s.workspace {
+		return nil
+	}
+	ws, exists := s.workspaces[name]
+	if !exists {
+		return fmt.Errorf("workspace '%s' does not exist", name)
+	}
+	delete(s.workspaces, name)
+	s.switchWorkspace(name, ws)
+	return nil
+}
+
+// switchWorkspace keeps the current workspace and makes ws current
+func (s *Store) switchWorkspace(name string, ws *workspace) {
+	if s.workspaces == nil {
+		s.workspaces = make(map[string]*workspace)
+	}
+	s.workspaces[s.workspace] = &workspace{state: s.state, history: s.history}
+	s.workspace = name
+	s.state = ws.state
+	s.history = ws.history
+}
+
+// DeleteWorkspace removes the workspace, which must not be the current one
+func (s *Store) DeleteWorkspace(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if name == s.workspace {
+		return fmt.Errorf("cannot delete the current workspace '%s', switch to another workspace first", name)
+	}
+	if _, exists := s.workspaces[name]; !exists {
+		return fmt.Errorf("workspace '%s' does not exist", name)
+	}
+	delete(s.workspaces, name)
+	return nil
+}
+
+// CopyWorkspace creates the workspace to with a copy of the signals of the workspace from.
+// The undo history is not copied and the current workspace is not changed.
+func (s *Store) CopyWorkspace(from, to string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if to == "" {
+		return fmt.Errorf("workspace name must not be empty")
+	}
+	if s.isWorkspaceExists(to) {
+		return fmt.Errorf("workspace '%s' already exists", to)
+	}
+
+	src := s.state
+	if from != s.workspace {
+		ws, exists := s.workspaces[from]
+		if !exists {
+			return fmt.Errorf("workspace '%s' does not exist", from)
+		}
+		src = ws.state
+	}
+	if s.workspaces == nil {
+		s.workspaces = make(map[string]*workspace)
+	}
+	s.workspaces[to] = &workspace{state: src.clone()}
+	return nil
+}
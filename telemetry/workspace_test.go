@@ -0,0 +1,64 @@
+package telemetry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreWorkspaces(t *testing.T) {
+	s := NewStore()
+	assert.Equal(t, DefaultWorkspace, s.Workspace())
+	s.CreateTrace("checkout")
+	s.Track("create span root in trace checkout", func() {
+		_, _ = s.AddSpanToTrace("checkout", "root", map[string]string{})
+	})
+
+	assert.NoError(t, s.NewWorkspace("batch"))
+	assert.Equal(t, "batch", s.Workspace())
+	assert.Empty(t, s.GetTraces(), "New workspace should be empty")
+	_, err := s.Undo()
+	assert.Error(t, err, "New workspace should have its own undo history")
+	s.CreateTrace("job")
+
+	assert.EqualError(t, s.NewWorkspace("default"), "workspace 'default' already exists")
+	assert.EqualError(t, s.SwitchWorkspace("nope"), "workspace 'nope' does not exist")
+
+	assert.NoError(t, s.SwitchWorkspace(DefaultWorkspace))
+	assert.Contains(t, s.GetTraces(), "checkout")
+	assert.NotContains(t, s.GetTraces(), "job")
+	label, err := s.Undo()
+	assert.NoError(t, err)
+	assert.Equal(t, "create span root in trace checkout", label, "Undo history should be kept while switched away")
+
+	s.Reset()
+	assert.Equal(t, []WorkspaceInfo{
+		{Name: "batch", Traces: 1},
+		{Name: DefaultWorkspace, Current: true},
+	}, s.Workspaces(), "Reset should keep the other workspaces")
+
+	assert.EqualError(t, s.DeleteWorkspace(DefaultWorkspace), "cannot delete the current workspace 'default', switch to another workspace first")
+	assert.NoError(t, s.DeleteWorkspace("batch"))
+	assert.False(t, s.IsWorkspaceExists("batch"))
+	assert.EqualError(t, s.DeleteWorkspace("batch"), "workspace 'batch' does not exist")
+}
+
+func TestStoreCopyWorkspace(t *testing.T) {
+	s := NewStore()
+	s.CreateTrace("checkout")
+	_, _ = s.AddSpanToTrace("checkout", "root", map[string]string{"k": "v"})
+
+	assert.NoError(t, s.CopyWorkspace(DefaultWorkspace, "copy"))
+	assert.EqualError(t, s.CopyWorkspace(DefaultWorkspace, "copy"), "workspace 'copy' already exists")
+	assert.EqualError(t, s.CopyWorkspace("nope", "other"), "workspace 'nope' does not exist")
+	assert.Equal(t, DefaultWorkspace, s.Workspace(), "Copy should not switch the workspace")
+
+	_, err := s.UpdateSpan("root", "", "", map[string]string{"k": "changed"})
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.SwitchWorkspace("copy"))
+	assert.Equal(t, map[string]string{"k": "v"}, s.GetSpans()["checkout/root"].Attributes, "Copy should not share the spans")
+
+	assert.NoError(t, s.CopyWorkspace(DefaultWorkspace, "copy2"))
+	assert.Equal(t, WorkspaceInfo{Name: "copy2", Traces: 1, Spans: 1}, s.Workspaces()[1], "Non-current workspace should be copied")
+}